@@ -22,6 +22,11 @@ type UserSettings struct {
 	DefaultEnv         string `json:"defaultEnv"`
 	EnableAnimations   bool   `json:"enableAnimations"`
 	ResponseHistoryTTL int    `json:"responseHistoryTTL"`
+	// ResponseHistoryMaxAgeDays and ResponseHistoryMaxSizeMB are disabled when zero.
+	ResponseHistoryMaxAgeDays int `json:"responseHistoryMaxAgeDays"`
+	ResponseHistoryMaxSizeMB  int `json:"responseHistoryMaxSizeMB"`
+	// TrashRetentionDays keeps deleted items forever when zero.
+	TrashRetentionDays int `json:"trashRetentionDays"`
 }
 
 type AppStateService struct {
@@ -68,6 +73,7 @@ func (s *AppStateService) LoadUserSettings() (*UserSettings, error) {
 		DefaultEnv:         "",
 		EnableAnimations:   defaultEnableAnimate,
 		ResponseHistoryTTL: defaultResponseHistoryTTL,
+		TrashRetentionDays: defaultTrashRetentionDays,
 	}
 
 	if rawTheme, err := s.getAppStateValue(themeKey); err != nil {
@@ -98,6 +104,24 @@ func (s *AppStateService) LoadUserSettings() (*UserSettings, error) {
 		settings.ResponseHistoryTTL = ttl
 	}
 
+	if maxAge, err := loadRetentionLimit(s.db, responseHistoryMaxAgeKey); err != nil {
+		fmt.Println("Failed to load response history max age:", err)
+	} else {
+		settings.ResponseHistoryMaxAgeDays = maxAge
+	}
+
+	if maxSize, err := loadRetentionLimit(s.db, responseHistoryMaxSizeKey); err != nil {
+		fmt.Println("Failed to load response history max size:", err)
+	} else {
+		settings.ResponseHistoryMaxSizeMB = maxSize
+	}
+
+	if trashDays, err := loadTrashRetentionDays(s.db); err != nil {
+		fmt.Println("Failed to load trash retention:", err)
+	} else {
+		settings.TrashRetentionDays = trashDays
+	}
+
 	return settings, nil
 }
 
@@ -111,6 +135,12 @@ func (s *AppStateService) SaveUserSettings(settings *UserSettings) error {
 	if settings.ResponseHistoryTTL < 1 {
 		return fmt.Errorf("response history TTL must be greater than zero")
 	}
+	if settings.ResponseHistoryMaxAgeDays < 0 || settings.ResponseHistoryMaxSizeMB < 0 {
+		return fmt.Errorf("response history limits must not be negative")
+	}
+	if settings.TrashRetentionDays < 0 {
+		return fmt.Errorf("trash retention must not be negative")
+	}
 
 	theme := strings.TrimSpace(settings.Theme)
 	if theme == "" {
@@ -132,6 +162,18 @@ func (s *AppStateService) SaveUserSettings(settings *UserSettings) error {
 		return err
 	}
 
+	if err := saveRetentionLimit(s.db, responseHistoryMaxAgeKey, settings.ResponseHistoryMaxAgeDays); err != nil {
+		return err
+	}
+
+	if err := saveRetentionLimit(s.db, responseHistoryMaxSizeKey, settings.ResponseHistoryMaxSizeMB); err != nil {
+		return err
+	}
+
+	if err := saveRetentionLimit(s.db, trashRetentionDaysKey, settings.TrashRetentionDays); err != nil {
+		return err
+	}
+
 	return nil
 }
 
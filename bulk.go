@@ -0,0 +1,254 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BulkResult reports what a bulk operation did. Matched counts the requests it applied to and
+// Changed lists the ones it actually modified; the rest already had the requested value.
+type BulkResult struct {
+	Operation string `json:"operation"`
+	Matched   int    `json:"matched"`
+	Changed   []int  `json:"changed"`
+}
+
+// bulkRequestField is a column a bulk edit rewrites, with the function computing its new value.
+// changed, when set, runs in the same transaction for every request whose value changed.
+type bulkRequestField struct {
+	column  string
+	update  func(current sql.NullString) sql.NullString
+	changed func(tx *sql.Tx, requestID int, value sql.NullString) error
+}
+
+// subtreeRequestsScope selects the active requests in a collection, and with recursive set in
+// the collections below it too.
+func subtreeRequestsScope(collectionID string, recursive bool) journalScope {
+	if !recursive {
+		return journalScope{table: "requests", condition: "collection_id = ? AND deleted_at IS NULL", args: []interface{}{collectionID}}
+	}
+	return journalScope{
+		table:     "requests",
+		condition: "collection_id IN (SELECT id FROM (" + collectionSubtreeQuery + ")) AND deleted_at IS NULL",
+		args:      []interface{}{collectionID},
+	}
+}
+
+// BulkMoveRequests moves requests to a collection, or out of any collection when collectionID is
+// nil, appending them in the order given. Either all of them move or none do.
+func (s *RequestCRUDService) BulkMoveRequests(requestIDs []int, collectionID *string) (BulkResult, error) {
+	result := BulkResult{Operation: "move", Changed: []int{}}
+	target := collectionRef(collectionID)
+
+	scopes := []journalScope{{table: "requests", condition: "collection_id IS ? AND deleted_at IS NULL", args: []interface{}{target}}}
+	for _, id := range requestIDs {
+		scopes = append(scopes, siblingRequestsScope(id))
+	}
+	err := s.journaled(fmt.Sprintf("Move %d requests", len(requestIDs)), scopes, func(tx *sql.Tx) error {
+		if target != nil {
+			active, err := activeCollection(tx, *collectionID)
+			if err != nil {
+				return err
+			}
+			if !active {
+				return fmt.Errorf("collection %s not found", *collectionID)
+			}
+		}
+
+		for _, id := range requestIDs {
+			var current sql.NullString
+			err := tx.QueryRow("SELECT collection_id FROM requests WHERE id = ? AND deleted_at IS NULL", id).Scan(&current)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("request %d not found", id)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to load request %d: %w", id, err)
+			}
+			result.Matched++
+			if current.Valid == (target != nil) && (target == nil || current.String == *collectionID) {
+				continue
+			}
+
+			if err := closeSortOrderGap(tx, id); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(
+				`UPDATE requests
+				 SET collection_id = ?,
+				     sort_order = (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM requests WHERE collection_id IS ? AND deleted_at IS NULL AND id != ?)
+				 WHERE id = ?`,
+				target, target, id, id,
+			); err != nil {
+				return fmt.Errorf("failed to move request %d: %w", id, err)
+			}
+			result.Changed = append(result.Changed, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return BulkResult{}, err
+	}
+	return result, nil
+}
+
+// BulkDeleteRequests moves requests to the trash. Either all of them go or none do.
+func (s *RequestCRUDService) BulkDeleteRequests(requestIDs []int) (BulkResult, error) {
+	result := BulkResult{Operation: "delete", Changed: []int{}}
+
+	scopes := make([]journalScope, 0, len(requestIDs))
+	for _, id := range requestIDs {
+		scopes = append(scopes, siblingRequestsScope(id))
+	}
+	err := s.journaled(fmt.Sprintf("Delete %d requests", len(requestIDs)), scopes, func(tx *sql.Tx) error {
+		for _, id := range requestIDs {
+			if err := closeSortOrderGap(tx, id); err != nil {
+				return err
+			}
+			deleted, err := tx.Exec("UPDATE requests SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL", id)
+			if err != nil {
+				return fmt.Errorf("failed to delete request %d: %w", id, err)
+			}
+			if affected, _ := deleted.RowsAffected(); affected == 0 {
+				return fmt.Errorf("request %d not found", id)
+			}
+			result.Matched++
+			result.Changed = append(result.Changed, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return BulkResult{}, err
+	}
+	return result, nil
+}
+
+// BulkSetHeader sets a header on every request in a collection, and with recursive set in its
+// sub-collections too. An existing header of the same name, compared case-insensitively, gets
+// the new value and is enabled; otherwise the header is added.
+func (s *RequestCRUDService) BulkSetHeader(collectionID string, key string, value string, recursive bool) (BulkResult, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return BulkResult{}, fmt.Errorf("header name is required")
+	}
+
+	return s.bulkUpdateRequests("set header", fmt.Sprintf("Set header %s", key), subtreeRequestsScope(collectionID, recursive), bulkRequestField{
+		column: "headers",
+		update: func(current sql.NullString) sql.NullString {
+			entries := []HeaderEntry{}
+			if strings.TrimSpace(current.String) != "" {
+				entries = parseHeaderEntries(current.String)
+			}
+			enabled := true
+			found := false
+			for i := range entries {
+				if strings.EqualFold(entries[i].Key, key) {
+					entries[i].Value, entries[i].Enabled, entries[i].Disabled = value, &enabled, false
+					found = true
+				}
+			}
+			if !found {
+				entries = append(entries, HeaderEntry{Key: key, Value: value, Enabled: &enabled})
+			}
+			encoded, err := json.Marshal(entries)
+			if err != nil {
+				return current
+			}
+			return sql.NullString{String: string(encoded), Valid: true}
+		},
+	})
+}
+
+// BulkReplaceInURLs replaces every occurrence of find in the URLs of the requests in a collection
+// and the collections below it, or of all requests when collectionID is nil. The replacement is
+// literal, so a hardcoded host can be swapped for a placeholder such as {{baseUrl}}.
+func (s *RequestCRUDService) BulkReplaceInURLs(collectionID *string, find string, replace string) (BulkResult, error) {
+	if find == "" {
+		return BulkResult{}, fmt.Errorf("search text is required")
+	}
+
+	scope := journalScope{table: "requests", condition: "deleted_at IS NULL"}
+	if collectionRef(collectionID) != nil {
+		scope = subtreeRequestsScope(*collectionID, true)
+	}
+	result, err := s.bulkUpdateRequests("replace in URLs", "Replace in URLs", scope, bulkRequestField{
+		column: "url",
+		update: func(current sql.NullString) sql.NullString {
+			return emptyStringToNullString(strings.ReplaceAll(current.String, find, replace))
+		},
+		// Query parameters and path variables follow the rewritten URLs.
+		changed: func(tx *sql.Tx, requestID int, url sql.NullString) error {
+			if err := rebuildRequestParams(tx, requestID, url.String); err != nil {
+				return fmt.Errorf("failed to sync params of request %d: %w", requestID, err)
+			}
+			if err := rebuildPathVariables(tx, requestID, url.String); err != nil {
+				return fmt.Errorf("failed to sync path variables of request %d: %w", requestID, err)
+			}
+			return nil
+		},
+	})
+	return result, err
+}
+
+// BulkSetAuth sets the auth of every request in a collection and the collections below it. An
+// empty auth makes them inherit the collection auth again; authNone sends none.
+func (s *RequestCRUDService) BulkSetAuth(collectionID string, auth string) (BulkResult, error) {
+	return s.bulkUpdateRequests("set auth", "Set auth", subtreeRequestsScope(collectionID, true), bulkRequestField{
+		column: "auth",
+		update: func(sql.NullString) sql.NullString {
+			return emptyStringToNullString(auth)
+		},
+	})
+}
+
+// bulkUpdateRequests rewrites one column of the requests in scope in a single journaled
+// transaction, writing only the rows whose value changes.
+func (s *RequestCRUDService) bulkUpdateRequests(operation string, description string, scope journalScope, field bulkRequestField) (BulkResult, error) {
+	result := BulkResult{Operation: operation, Changed: []int{}}
+	if s.db == nil {
+		return result, fmt.Errorf("database not initialized")
+	}
+
+	err := s.journaled(description, []journalScope{scope}, func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT id, "+field.column+" FROM requests WHERE "+scope.condition+" ORDER BY id", scope.args...)
+		if err != nil {
+			return fmt.Errorf("failed to load requests: %w", err)
+		}
+		type update struct {
+			id    int
+			value sql.NullString
+		}
+		var updates []update
+		for rows.Next() {
+			var id int
+			var current sql.NullString
+			if err := rows.Scan(&id, &current); err != nil {
+				rows.Close()
+				return err
+			}
+			result.Matched++
+			if value := field.update(current); value != current {
+				updates = append(updates, update{id: id, value: value})
+			}
+		}
+		rows.Close()
+
+		for _, u := range updates {
+			if _, err := tx.Exec("UPDATE requests SET "+field.column+" = ? WHERE id = ?", u.value, u.id); err != nil {
+				return fmt.Errorf("failed to update request %d: %w", u.id, err)
+			}
+			if field.changed != nil {
+				if err := field.changed(tx, u.id, u.value); err != nil {
+					return err
+				}
+			}
+			result.Changed = append(result.Changed, u.id)
+		}
+		return nil
+	})
+	if err != nil {
+		return BulkResult{}, err
+	}
+	return result, nil
+}
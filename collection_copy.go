@@ -0,0 +1,212 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CollectionCopyOptions selects what DuplicateCollection copies besides the collections and
+// their requests.
+type CollectionCopyOptions struct {
+	Variables      bool `json:"variables"`
+	LatestResponse bool `json:"latestResponse"`
+}
+
+// requestDetailCopies copy the rows that belong to a request, given the new and the original
+// request id.
+var requestDetailCopies = []struct {
+	table string
+	query string
+}{
+	{"request variables", `INSERT INTO request_variables (request_id, key, value, enabled, sort_order)
+		SELECT ?, key, value, enabled, sort_order FROM request_variables WHERE request_id = ?`},
+	{"request scripts", `INSERT INTO request_scripts (request_id, pre_request, post_response)
+		SELECT ?, pre_request, post_response FROM request_scripts WHERE request_id = ?`},
+	{"request params", `INSERT INTO request_params (request_id, key, value, enabled, description, sort_order)
+		SELECT ?, key, value, enabled, description, sort_order FROM request_params WHERE request_id = ? ORDER BY COALESCE(sort_order, 0), id`},
+	{"path variables", `INSERT INTO request_path_variables (request_id, key, value, description)
+		SELECT ?, key, value, description FROM request_path_variables WHERE request_id = ?`},
+	{"request tags", "INSERT INTO item_tags (tag, request_id) SELECT tag, ? FROM item_tags WHERE request_id = ?"},
+}
+
+func copyRequestDetails(tx *sql.Tx, from int, to int) error {
+	for _, statement := range requestDetailCopies {
+		if _, err := tx.Exec(statement.query, to, from); err != nil {
+			return fmt.Errorf("failed to duplicate %s: %w", statement.table, err)
+		}
+	}
+	return nil
+}
+
+// DuplicateCollection deep-copies a collection, its sub-collections and their requests below
+// newParentID, or to the top level when it is empty. Copies get new ids and keep the names and
+// sort order of the originals; the copied root is named "<name> (Copy)" and placed after its new
+// siblings. Items in the trash are left out.
+func (s *RequestCRUDService) DuplicateCollection(collectionID string, newParentID *string, options CollectionCopyOptions) (Collection, error) {
+	if s.db == nil {
+		return Collection{}, fmt.Errorf("database not initialized")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Collection{}, fmt.Errorf("failed to start collection duplication: %w", err)
+	}
+	defer tx.Rollback()
+
+	if active, err := activeCollection(tx, collectionID); err != nil {
+		return Collection{}, err
+	} else if !active {
+		return Collection{}, fmt.Errorf("collection %s not found", collectionID)
+	}
+	parent := collectionRef(newParentID)
+	if parent != nil {
+		if active, err := activeCollection(tx, *newParentID); err != nil {
+			return Collection{}, err
+		} else if !active {
+			return Collection{}, fmt.Errorf("parent collection %s does not exist", *newParentID)
+		}
+	}
+
+	// Parents come before their children so every copy can point at its copied parent. The
+	// subtree is read in full before anything is inserted, so copying into itself terminates.
+	rows, err := tx.Query(`
+		WITH RECURSIVE subtree(id, depth) AS (
+		    SELECT id, 0 FROM collections WHERE id = ?
+		    UNION SELECT c.id, subtree.depth + 1 FROM collections c JOIN subtree ON c.parent_collection = subtree.id
+		    WHERE c.deleted_at IS NULL
+		)
+		SELECT id FROM subtree ORDER BY depth`, collectionID)
+	if err != nil {
+		return Collection{}, fmt.Errorf("failed to load collection %s: %w", collectionID, err)
+	}
+	var sources []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return Collection{}, err
+		}
+		sources = append(sources, id)
+	}
+	rows.Close()
+
+	copies := make(map[string]string, len(sources))
+	for _, source := range sources {
+		copies[source] = uuid.New().String()
+	}
+
+	for _, source := range sources {
+		var target interface{} = parent
+		name, sortOrder := "name", nextCollectionSortOrder
+		sortArgs := []interface{}{parent, copies[source]}
+		if source != collectionID {
+			var originalParent string
+			if err := tx.QueryRow("SELECT parent_collection FROM collections WHERE id = ?", source).Scan(&originalParent); err != nil {
+				return Collection{}, fmt.Errorf("failed to load collection %s: %w", source, err)
+			}
+			target = copies[originalParent]
+			sortOrder, sortArgs = "sort_order", nil
+		} else {
+			name = "name || ' (Copy)'"
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO collections (id, name, description, schema, version_major, version_minor, version_patch, version_identifier, headers, auth, parent_collection, sort_order)
+			 SELECT ?, `+name+`, description, schema, version_major, version_minor, version_patch, version_identifier, headers, auth, ?, `+sortOrder+`
+			 FROM collections WHERE id = ?`,
+			append(append([]interface{}{copies[source], target}, sortArgs...), source)...,
+		); err != nil {
+			return Collection{}, fmt.Errorf("failed to duplicate collection %s: %w", source, err)
+		}
+
+		collectionCopies := []struct {
+			table string
+			query string
+		}{
+			{"collection retention", "INSERT INTO collection_retention (collection_id, max_entries, max_age_days) SELECT ?, max_entries, max_age_days FROM collection_retention WHERE collection_id = ?"},
+			{"collection scripts", "INSERT INTO collection_scripts (collection_id, pre_request, post_response) SELECT ?, pre_request, post_response FROM collection_scripts WHERE collection_id = ?"},
+			{"collection tags", "INSERT INTO item_tags (tag, collection_id) SELECT tag, ? FROM item_tags WHERE collection_id = ?"},
+		}
+		if options.Variables {
+			collectionCopies = append(collectionCopies, struct {
+				table string
+				query string
+			}{"collection variables", "INSERT INTO collection_variables (collection_id, key, value, enabled, sort_order) SELECT ?, key, value, enabled, sort_order FROM collection_variables WHERE collection_id = ?"})
+		}
+		for _, statement := range collectionCopies {
+			if _, err := tx.Exec(statement.query, copies[source], source); err != nil {
+				return Collection{}, fmt.Errorf("failed to duplicate %s: %w", statement.table, err)
+			}
+		}
+
+		if err := duplicateCollectionRequests(tx, source, copies[source], options.LatestResponse); err != nil {
+			return Collection{}, err
+		}
+	}
+
+	var duplicated Collection
+	var description, parentID sql.NullString
+	var sortOrder int
+	if err := tx.QueryRow("SELECT id, name, description, parent_collection, sort_order FROM collections WHERE id = ?", copies[collectionID]).
+		Scan(&duplicated.ID, &duplicated.Name, &description, &parentID, &sortOrder); err != nil {
+		return Collection{}, fmt.Errorf("failed to load duplicated collection: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return Collection{}, fmt.Errorf("failed to commit duplicated collection: %w", err)
+	}
+
+	duplicated.Description = description.String
+	duplicated.ParentCollectionId = nullStringToPointer(parentID)
+	duplicated.SortOrder = intPointer(sortOrder)
+	duplicated.Tags = s.GetCollectionTags(duplicated.ID)
+	return duplicated, nil
+}
+
+// duplicateCollectionRequests copies the requests of one collection into another, keeping
+// their sort order.
+func duplicateCollectionRequests(tx *sql.Tx, from string, to string, latestResponse bool) error {
+	rows, err := tx.Query("SELECT id FROM requests WHERE collection_id = ? AND deleted_at IS NULL ORDER BY sort_order, id", from)
+	if err != nil {
+		return fmt.Errorf("failed to load requests of collection %s: %w", from, err)
+	}
+	var requestIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		requestIDs = append(requestIDs, id)
+	}
+	rows.Close()
+
+	requestColumns := "name, description, method, url, headers, body, body_type, body_format, auth, sort_order"
+	responseColumns := "status_code, headers, body, runtime_ms, created_at, request_snapshot, method, url, host, environment"
+	for _, requestID := range requestIDs {
+		var newID int
+		err := tx.QueryRow(
+			"INSERT INTO requests (collection_id, "+requestColumns+") SELECT ?, "+requestColumns+" FROM requests WHERE id = ? RETURNING id",
+			to, requestID,
+		).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to duplicate request %d: %w", requestID, err)
+		}
+		if err := copyRequestDetails(tx, requestID, newID); err != nil {
+			return err
+		}
+		if !latestResponse {
+			continue
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO responses (request_id, `+responseColumns+`)
+			 SELECT ?, `+responseColumns+` FROM responses WHERE request_id = ?
+			 ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC
+			 LIMIT 1`,
+			newID, requestID,
+		); err != nil {
+			return fmt.Errorf("failed to duplicate the latest response of request %d: %w", requestID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// authNone is stored as the auth of a request or collection that sends no Authorization header,
+// as opposed to an empty auth, which inherits one from the enclosing collections.
+const authNone = "noauth"
+
+// CollectionDefaults are the headers and auth that the requests in a collection, and in the
+// collections below it, inherit unless they set their own.
+type CollectionDefaults struct {
+	Headers []HeaderEntry `json:"headers"`
+	Auth    string        `json:"auth"`
+}
+
+type InheritedHeader struct {
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	CollectionID   string `json:"collectionId"`
+	CollectionName string `json:"collectionName"`
+}
+
+// InheritedDefaults is what a request in a collection inherits. AuthCollectionID names the
+// collection the auth comes from and is empty when no collection sets one.
+type InheritedDefaults struct {
+	Headers            []InheritedHeader `json:"headers"`
+	Auth               string            `json:"auth"`
+	AuthCollectionID   string            `json:"authCollectionId"`
+	AuthCollectionName string            `json:"authCollectionName"`
+}
+
+func (s *RequestCRUDService) ensureCollectionDefaultColumns() {
+	if s.db == nil {
+		return
+	}
+	ensureColumn(s.db, "collections", "headers", "TEXT")
+	ensureColumn(s.db, "collections", "auth", "TEXT")
+	s.migratePostmanAuth()
+}
+
+// migratePostmanAuth converts the auth of requests imported before Postman auth was translated,
+// which held Postman's auth object, or "null" when there was none, instead of a header value.
+// Objects that translate to nothing are left as they are.
+func (s *RequestCRUDService) migratePostmanAuth() {
+	rows, err := s.db.Query("SELECT id, auth, headers FROM requests WHERE auth = 'null' OR auth LIKE '{%'")
+	if err != nil {
+		fmt.Println("Failed to load imported request auth:", err)
+		return
+	}
+	type migration struct {
+		id      int
+		auth    sql.NullString
+		headers sql.NullString
+	}
+	var migrations []migration
+	for rows.Next() {
+		var m migration
+		var raw string
+		if err := rows.Scan(&m.id, &raw, &m.headers); err != nil {
+			fmt.Println("Failed to scan imported request auth:", err)
+			continue
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+			continue
+		}
+		auth, header := postmanAuth(decoded)
+		if auth == "" && header == nil && decoded != nil && !isPostmanInheritAuth(decoded) {
+			// Auth that cannot be translated, such as digest or oauth1, keeps its settings.
+			continue
+		}
+		m.auth = emptyStringToNullString(auth)
+		if header != nil {
+			m.headers = sql.NullString{String: appendHeaderEntry(m.headers.String, *header), Valid: true}
+		}
+		migrations = append(migrations, m)
+	}
+	rows.Close()
+
+	for _, m := range migrations {
+		if _, err := s.db.Exec("UPDATE requests SET auth = ?, headers = ? WHERE id = ?", m.auth, m.headers, m.id); err != nil {
+			fmt.Println("Failed to migrate imported request auth:", err)
+		}
+	}
+}
+
+func isPostmanInheritAuth(raw interface{}) bool {
+	fields, _ := raw.(map[string]interface{})
+	authType, _ := fields["type"].(string)
+	return authType == "inherit"
+}
+
+func (s *RequestCRUDService) GetCollectionDefaults(collectionID string) (CollectionDefaults, error) {
+	defaults := CollectionDefaults{Headers: []HeaderEntry{}}
+	var headers, auth sql.NullString
+	err := s.db.QueryRow("SELECT headers, auth FROM collections WHERE id = ? AND deleted_at IS NULL", collectionID).Scan(&headers, &auth)
+	if err == sql.ErrNoRows {
+		return defaults, fmt.Errorf("collection %s not found", collectionID)
+	}
+	if err != nil {
+		return defaults, fmt.Errorf("failed to load collection %s: %w", collectionID, err)
+	}
+	if strings.TrimSpace(headers.String) != "" {
+		defaults.Headers = parseHeaderEntries(headers.String)
+	}
+	defaults.Auth = auth.String
+	return defaults, nil
+}
+
+func (s *RequestCRUDService) SetCollectionDefaults(collectionID string, defaults CollectionDefaults) error {
+	var headers sql.NullString
+	if len(defaults.Headers) > 0 {
+		encoded, err := json.Marshal(defaults.Headers)
+		if err != nil {
+			return fmt.Errorf("failed to encode headers: %w", err)
+		}
+		headers = sql.NullString{String: string(encoded), Valid: true}
+	}
+
+	scope := journalScope{table: "collections", condition: "id = ?", args: []interface{}{collectionID}}
+	return s.journaled("Edit collection defaults", []journalScope{scope}, func(tx *sql.Tx) error {
+		result, err := tx.Exec(
+			"UPDATE collections SET headers = ?, auth = ? WHERE id = ? AND deleted_at IS NULL",
+			headers,
+			emptyStringToNullString(strings.TrimSpace(defaults.Auth)),
+			collectionID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save collection defaults: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return fmt.Errorf("collection %s not found", collectionID)
+		}
+		return nil
+	})
+}
+
+// ResolveInheritedDefaults walks from the root collection down to collectionID. A header set
+// further down replaces one of the same name set above it, and a disabled header removes it.
+// The auth comes from the nearest collection that sets one.
+func (s *RequestCRUDService) ResolveInheritedDefaults(collectionID string) (InheritedDefaults, error) {
+	inherited := InheritedDefaults{Headers: []InheritedHeader{}}
+	if s.db == nil || collectionID == "" {
+		return inherited, nil
+	}
+
+	chain, err := s.collectionChain(collectionID)
+	if err != nil {
+		return inherited, err
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		var name string
+		var headers, auth sql.NullString
+		err := s.db.QueryRow("SELECT name, headers, auth FROM collections WHERE id = ?", chain[i]).Scan(&name, &headers, &auth)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return inherited, fmt.Errorf("failed to load collection %s: %w", chain[i], err)
+		}
+
+		if strings.TrimSpace(headers.String) != "" {
+			for _, h := range parseHeaderEntries(headers.String) {
+				if h.Key == "" {
+					continue
+				}
+				kept := inherited.Headers[:0]
+				for _, existing := range inherited.Headers {
+					if !strings.EqualFold(existing.Key, h.Key) {
+						kept = append(kept, existing)
+					}
+				}
+				inherited.Headers = kept
+				if h.isEnabled() {
+					inherited.Headers = append(inherited.Headers, InheritedHeader{Key: h.Key, Value: h.Value, CollectionID: chain[i], CollectionName: name})
+				}
+			}
+		}
+		if auth.String != "" {
+			inherited.Auth, inherited.AuthCollectionID, inherited.AuthCollectionName = auth.String, chain[i], name
+		}
+	}
+	if inherited.Auth == authNone {
+		inherited.Auth = ""
+	}
+	return inherited, nil
+}
+
+// applyCollectionDefaults adds the headers and auth a saved request inherits from its
+// collections. The request's own headers win, disabled ones included, and so does its own auth
+// or Authorization header.
+func (s *RequestCRUDService) applyCollectionDefaults(requestID int, entries []HeaderEntry, auth string) ([]HeaderEntry, string, error) {
+	if auth == authNone {
+		return entries, "", nil
+	}
+
+	var collectionID sql.NullString
+	err := s.db.QueryRow("SELECT collection_id FROM requests WHERE id = ?", requestID).Scan(&collectionID)
+	if err != nil && err != sql.ErrNoRows {
+		return entries, auth, fmt.Errorf("failed to load request %d: %w", requestID, err)
+	}
+	inherited, err := s.ResolveInheritedDefaults(collectionID.String)
+	if err != nil {
+		return entries, auth, err
+	}
+
+	own := make(map[string]bool, len(entries))
+	for _, h := range entries {
+		own[strings.ToLower(h.Key)] = true
+	}
+	merged := make([]HeaderEntry, 0, len(inherited.Headers)+len(entries))
+	for _, h := range inherited.Headers {
+		if !own[strings.ToLower(h.Key)] {
+			merged = append(merged, HeaderEntry{Key: h.Key, Value: h.Value})
+		}
+	}
+	merged = append(merged, entries...)
+
+	if auth == "" {
+		hasAuthorization := false
+		for _, h := range entries {
+			if strings.EqualFold(h.Key, "Authorization") && h.isEnabled() {
+				hasAuthorization = true
+			}
+		}
+		if !hasAuthorization {
+			auth = inherited.Auth
+		}
+	}
+	return merged, auth, nil
+}
+
+// appendHeaderEntry adds a header to a stored header list, replacing one of the same name.
+func appendHeaderEntry(headers string, header HeaderEntry) string {
+	entries := []HeaderEntry{}
+	if strings.TrimSpace(headers) != "" {
+		entries = parseHeaderEntries(headers)
+	}
+	kept := entries[:0]
+	for _, h := range entries {
+		if !strings.EqualFold(h.Key, header.Key) {
+			kept = append(kept, h)
+		}
+	}
+	encoded, _ := json.Marshal(append(kept, header))
+	return string(encoded)
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	CollectionDeleteCascade  = "cascade"
+	CollectionDeleteReparent = "reparent"
+)
+
+// CollectionDeleteSummary reports what DeleteCollection moved to the trash or moved up a level.
+// ResponsesTrashed counts the history of the trashed requests, which is kept until they are
+// purged from the trash.
+type CollectionDeleteSummary struct {
+	Mode               string `json:"mode"`
+	CollectionsDeleted int    `json:"collectionsDeleted"`
+	RequestsDeleted    int    `json:"requestsDeleted"`
+	ResponsesTrashed   int    `json:"responsesTrashed"`
+	CollectionsMoved   int    `json:"collectionsMoved"`
+	RequestsMoved      int    `json:"requestsMoved"`
+}
+
+// collectionSubtreeQuery selects a collection id, given as its only argument, and the ids of
+// all collections below it.
+const collectionSubtreeQuery = `WITH RECURSIVE subtree(id) AS (
+	SELECT ? UNION SELECT collections.id FROM collections JOIN subtree ON collections.parent_collection = subtree.id
+) SELECT id FROM subtree`
+
+// nextCollectionSortOrder places a collection after its siblings. It takes the parent id, or
+// nil for the top level, and the id of the collection being placed.
+const nextCollectionSortOrder = `(SELECT COALESCE(MAX(sort_order) + 1, 0) FROM collections
+	WHERE parent_collection IS ? AND id != ? AND deleted_at IS NULL)`
+
+// collectionOrder sorts sibling collections; ones without a position go last, by name.
+const collectionOrder = "CASE WHEN sort_order IS NULL THEN 1 ELSE 0 END, sort_order, name, id"
+
+// requestDependentTables hold rows keyed by request_id that go when their request is deleted.
+var requestDependentTables = []string{
+	"responses",
+	"request_failures",
+	"request_variables",
+	"request_scripts",
+	"request_params",
+	"request_path_variables",
+	"item_tags",
+}
+
+// deleteRequestsWhere deletes the requests matching condition with everything that references
+// them, and reports how many requests and responses were removed. The rows are deleted
+// explicitly rather than through ON DELETE rules so databases opened without foreign key
+// enforcement stay consistent too.
+func deleteRequestsWhere(tx *sql.Tx, condition string, args ...interface{}) (int, int, error) {
+	matching := "SELECT id FROM requests WHERE " + condition
+
+	var responses int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM responses WHERE request_id IN ("+matching+")", args...).Scan(&responses); err != nil {
+		return 0, 0, fmt.Errorf("failed to count responses: %w", err)
+	}
+	for _, table := range requestDependentTables {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE request_id IN ("+matching+")", args...); err != nil {
+			return 0, 0, fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+	result, err := tx.Exec("DELETE FROM requests WHERE "+condition, args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete requests: %w", err)
+	}
+	requests, _ := result.RowsAffected()
+	return int(requests), responses, nil
+}
+
+// moveCollectionContents moves the direct sub-collections and requests of a collection to
+// parent, placing them after the ones already there. Items in the trash stay put.
+func moveCollectionContents(tx *sql.Tx, collectionID string, parent sql.NullString) (int, int, error) {
+	var target interface{}
+	if parent.Valid {
+		target = parent.String
+	}
+
+	var maxSort sql.NullInt64
+	if err := tx.QueryRow("SELECT MAX(sort_order) FROM collections WHERE parent_collection IS ? AND deleted_at IS NULL", target).Scan(&maxSort); err != nil {
+		return 0, 0, fmt.Errorf("failed to determine sort order: %w", err)
+	}
+	offset := int64(0)
+	if maxSort.Valid {
+		offset = maxSort.Int64 + 1
+	}
+	result, err := tx.Exec(
+		"UPDATE collections SET parent_collection = ?, sort_order = ? + COALESCE(sort_order, 0) WHERE parent_collection = ? AND id != ? AND deleted_at IS NULL",
+		target,
+		offset,
+		collectionID,
+		collectionID,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to move sub-collections: %w", err)
+	}
+	collections, _ := result.RowsAffected()
+
+	if err := tx.QueryRow("SELECT MAX(sort_order) FROM requests WHERE collection_id IS ? AND deleted_at IS NULL", target).Scan(&maxSort); err != nil {
+		return 0, 0, fmt.Errorf("failed to determine sort order: %w", err)
+	}
+	offset = 0
+	if maxSort.Valid {
+		offset = maxSort.Int64 + 1
+	}
+	result, err = tx.Exec(
+		"UPDATE requests SET collection_id = ?, sort_order = ? + COALESCE(sort_order, 0) WHERE collection_id = ? AND deleted_at IS NULL",
+		target,
+		offset,
+		collectionID,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to move requests: %w", err)
+	}
+	requests, _ := result.RowsAffected()
+	return int(collections), int(requests), nil
+}
+
+// trashCollection moves a collection and the items below it that are not already in the trash
+// to the trash, and reports how many collections, requests and responses went with it.
+func trashCollection(tx *sql.Tx, collectionID string) (int, int, int, error) {
+	scope := "SELECT id FROM (" + collectionSubtreeQuery + ")"
+	trashedRequests := "collection_id IN (" + scope + ") AND deleted_at IS NULL"
+
+	var responses int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM responses WHERE request_id IN (SELECT id FROM requests WHERE "+trashedRequests+")", collectionID).Scan(&responses); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to count responses: %w", err)
+	}
+	result, err := tx.Exec("UPDATE requests SET deleted_at = CURRENT_TIMESTAMP, deleted_with = ? WHERE "+trashedRequests, collectionID, collectionID)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to delete requests: %w", err)
+	}
+	requests, _ := result.RowsAffected()
+
+	result, err = tx.Exec(
+		"UPDATE collections SET deleted_at = CURRENT_TIMESTAMP, deleted_with = ? WHERE id IN ("+scope+") AND id != ? AND deleted_at IS NULL",
+		collectionID, collectionID, collectionID,
+	)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to delete sub-collections: %w", err)
+	}
+	collections, _ := result.RowsAffected()
+
+	if _, err := tx.Exec("UPDATE collections SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?", collectionID); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to delete collection %s: %w", collectionID, err)
+	}
+	return int(collections) + 1, int(requests), responses, nil
+}
+
+// siblingRequestsScope journals a request together with the other requests in its collection,
+// whose sort order moves along with it.
+func siblingRequestsScope(requestID int) journalScope {
+	return journalScope{
+		table:     "requests",
+		condition: "id = ? OR (collection_id IS (SELECT collection_id FROM requests WHERE id = ?) AND deleted_at IS NULL)",
+		args:      []interface{}{requestID, requestID},
+	}
+}
+
+// closeSortOrderGap moves the requests after a request up by one, ahead of the request leaving
+// its collection.
+func closeSortOrderGap(tx *sql.Tx, requestID int) error {
+	_, err := tx.Exec(
+		`UPDATE requests SET sort_order = sort_order - 1
+		 WHERE id != ? AND deleted_at IS NULL
+		   AND collection_id IS (SELECT collection_id FROM requests WHERE id = ?)
+		   AND sort_order > (SELECT sort_order FROM requests WHERE id = ?)`,
+		requestID, requestID, requestID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reorder requests: %w", err)
+	}
+	return nil
+}
+
+func (s *RequestCRUDService) ensureCollectionSortColumn() {
+	if s.db == nil {
+		return
+	}
+	ensureColumn(s.db, "collections", "sort_order", "INTEGER")
+}
+
+// siblingCollectionsScope journals a collection together with the other collections under the
+// same parent.
+func siblingCollectionsScope(collectionID string) journalScope {
+	return journalScope{
+		table:     "collections",
+		condition: "id = ? OR (parent_collection IS (SELECT parent_collection FROM collections WHERE id = ?) AND deleted_at IS NULL)",
+		args:      []interface{}{collectionID, collectionID},
+	}
+}
+
+// SetCollectionSortOrder moves a collection to position sortOrder among the collections under
+// the same parent and renumbers them from zero.
+func (s *RequestCRUDService) SetCollectionSortOrder(collectionID string, sortOrder int) error {
+	return s.journaled("Reorder collections", []journalScope{siblingCollectionsScope(collectionID)}, func(tx *sql.Tx) error {
+		var parent sql.NullString
+		err := tx.QueryRow("SELECT parent_collection FROM collections WHERE id = ? AND deleted_at IS NULL", collectionID).Scan(&parent)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("collection %s not found", collectionID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load collection %s: %w", collectionID, err)
+		}
+
+		rows, err := tx.Query("SELECT id FROM collections WHERE parent_collection IS ? AND id != ? AND deleted_at IS NULL ORDER BY "+collectionOrder, parent, collectionID)
+		if err != nil {
+			return fmt.Errorf("failed to load sibling collections: %w", err)
+		}
+		var siblings []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			siblings = append(siblings, id)
+		}
+		rows.Close()
+
+		if sortOrder < 0 {
+			sortOrder = 0
+		}
+		if sortOrder > len(siblings) {
+			sortOrder = len(siblings)
+		}
+		ordered := append(append(append([]string{}, siblings[:sortOrder]...), collectionID), siblings[sortOrder:]...)
+		for i, id := range ordered {
+			if _, err := tx.Exec("UPDATE collections SET sort_order = ? WHERE id = ? AND sort_order IS NOT ?", i, id, i); err != nil {
+				return fmt.Errorf("failed to reorder collections: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// normalizeCollectionSortOrder renumbers the collections under each parent from zero, like
+// normalizeRequestSortOrder does for requests.
+func (s *RequestCRUDService) normalizeCollectionSortOrder() {
+	if s.db == nil {
+		return
+	}
+
+	rows, err := s.db.Query("SELECT id, parent_collection, sort_order FROM collections WHERE deleted_at IS NULL ORDER BY parent_collection, " + collectionOrder)
+	if err != nil {
+		fmt.Println("Failed to load collections for normalization:", err)
+		return
+	}
+
+	updates := make(map[string]int)
+	var (
+		previous    sql.NullString
+		index       int
+		initialized bool
+	)
+	for rows.Next() {
+		var id string
+		var parent sql.NullString
+		var sortOrder sql.NullInt64
+		if err := rows.Scan(&id, &parent, &sortOrder); err != nil {
+			fmt.Println("Failed to scan collection during normalization:", err)
+			continue
+		}
+		if !initialized || parent != previous {
+			previous, index, initialized = parent, 0, true
+		}
+		if !sortOrder.Valid || int(sortOrder.Int64) != index {
+			updates[id] = index
+		}
+		index++
+	}
+	rows.Close()
+
+	for id, sortOrder := range updates {
+		if _, err := s.db.Exec("UPDATE collections SET sort_order = ? WHERE id = ?", sortOrder, id); err != nil {
+			fmt.Println("Failed to normalize collection sort order:", err)
+		}
+	}
+}
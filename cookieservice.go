@@ -0,0 +1,323 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+type CookieService struct {
+	db *sql.DB
+}
+
+type Cookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Domain   string     `json:"domain"`
+	Path     string     `json:"path"`
+	Expires  *time.Time `json:"expires"`
+	Secure   bool       `json:"secure"`
+	HttpOnly bool       `json:"httpOnly"`
+	SameSite string     `json:"sameSite"`
+	// HostOnly cookies were set without a Domain attribute and are only sent to that exact host.
+	HostOnly bool `json:"hostOnly"`
+}
+
+// ListCookies returns the unexpired cookies stored for an environment; an empty environment
+// is the jar used when no environment is selected.
+func (s *CookieService) ListCookies(environment string) []Cookie {
+	cookies, err := loadCookies(s.db, environment)
+	if err != nil {
+		fmt.Println("Failed to load cookies:", err)
+		return []Cookie{}
+	}
+	return cookies
+}
+
+func (s *CookieService) SaveCookie(environment string, cookie Cookie) error {
+	cookie.Name = strings.TrimSpace(cookie.Name)
+	cookie.Domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(cookie.Domain), "."))
+	if cookie.Name == "" || cookie.Domain == "" {
+		return fmt.Errorf("cookie name and domain are required")
+	}
+	if !strings.HasPrefix(cookie.Path, "/") {
+		cookie.Path = "/"
+	}
+	return storeCookie(s.db, environment, cookie)
+}
+
+func (s *CookieService) DeleteCookie(environment string, domain string, path string, name string) error {
+	_, err := s.db.Exec("DELETE FROM cookies WHERE environment = ? AND domain = ? AND path = ? AND name = ?", environment, domain, path, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete cookie: %w", err)
+	}
+	return nil
+}
+
+// ClearCookies removes every cookie of an environment, or only those of one domain when
+// domain is set.
+func (s *CookieService) ClearCookies(environment string, domain string) error {
+	var err error
+	if domain == "" {
+		_, err = s.db.Exec("DELETE FROM cookies WHERE environment = ?", environment)
+	} else {
+		_, err = s.db.Exec("DELETE FROM cookies WHERE environment = ? AND domain = ?", environment, strings.ToLower(domain))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to clear cookies: %w", err)
+	}
+	return nil
+}
+
+func loadCookies(db *sql.DB, environment string) ([]Cookie, error) {
+	rows, err := db.Query(
+		`SELECT name, value, domain, path, expires, secure, http_only, same_site, host_only
+		 FROM cookies WHERE environment = ? ORDER BY domain, path, name`,
+		environment,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	cookies := []Cookie{}
+	for rows.Next() {
+		var (
+			c        Cookie
+			value    sql.NullString
+			expires  sql.NullInt64
+			sameSite sql.NullString
+		)
+		if err := rows.Scan(&c.Name, &value, &c.Domain, &c.Path, &expires, &c.Secure, &c.HttpOnly, &sameSite, &c.HostOnly); err != nil {
+			return nil, err
+		}
+		if expires.Valid {
+			t := time.Unix(expires.Int64, 0)
+			if !t.After(now) {
+				continue
+			}
+			c.Expires = &t
+		}
+		c.Value = value.String
+		c.SameSite = sameSite.String
+		cookies = append(cookies, c)
+	}
+	return cookies, rows.Err()
+}
+
+func storeCookie(db *sql.DB, environment string, c Cookie) error {
+	var expires sql.NullInt64
+	if c.Expires != nil {
+		expires = sql.NullInt64{Int64: c.Expires.Unix(), Valid: true}
+	}
+	_, err := db.Exec(
+		`INSERT INTO cookies (environment, domain, path, name, value, expires, secure, http_only, same_site, host_only, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(environment, domain, path, name) DO UPDATE SET
+		   value = excluded.value, expires = excluded.expires, secure = excluded.secure,
+		   http_only = excluded.http_only, same_site = excluded.same_site, host_only = excluded.host_only`,
+		environment,
+		c.Domain,
+		c.Path,
+		c.Name,
+		c.Value,
+		expires,
+		c.Secure,
+		c.HttpOnly,
+		c.SameSite,
+		c.HostOnly,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save cookie %s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// cookieJar is an http.CookieJar backed by the cookies table. It follows the RFC 6265 domain,
+// path and expiry rules and rejects Domain attributes that are public suffixes.
+type cookieJar struct {
+	db          *sql.DB
+	environment string
+}
+
+func newCookieJar(db *sql.DB, environment string) *cookieJar {
+	return &cookieJar{db: db, environment: environment}
+}
+
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	host, err := canonicalCookieHost(u.Host)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+
+	for _, hc := range cookies {
+		if hc.Name == "" {
+			continue
+		}
+
+		domain, hostOnly, ok := cookieDomain(host, hc.Domain)
+		if !ok {
+			fmt.Printf("Rejected cookie %s from %s: invalid domain %q\n", hc.Name, host, hc.Domain)
+			continue
+		}
+		path := hc.Path
+		if !strings.HasPrefix(path, "/") {
+			path = defaultCookiePath(u.Path)
+		}
+
+		var expires *time.Time
+		switch {
+		case hc.MaxAge < 0:
+			expires = &now
+		case hc.MaxAge > 0:
+			t := now.Add(time.Duration(hc.MaxAge) * time.Second)
+			expires = &t
+		case !hc.Expires.IsZero():
+			t := hc.Expires
+			expires = &t
+		}
+		if expires != nil && !expires.After(now) {
+			if _, err := j.db.Exec("DELETE FROM cookies WHERE environment = ? AND domain = ? AND path = ? AND name = ?", j.environment, domain, path, hc.Name); err != nil {
+				fmt.Println("Failed to remove expired cookie:", err)
+			}
+			continue
+		}
+
+		err := storeCookie(j.db, j.environment, Cookie{
+			Name:     hc.Name,
+			Value:    hc.Value,
+			Domain:   domain,
+			Path:     path,
+			Expires:  expires,
+			Secure:   hc.Secure,
+			HttpOnly: hc.HttpOnly,
+			SameSite: sameSiteName(hc.SameSite),
+			HostOnly: hostOnly,
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+func (j *cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	host, err := canonicalCookieHost(u.Host)
+	if err != nil {
+		return nil
+	}
+	stored, err := loadCookies(j.db, j.environment)
+	if err != nil {
+		fmt.Println("Failed to load cookies:", err)
+		return nil
+	}
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	secure := u.Scheme == "https"
+
+	var matched []Cookie
+	for _, c := range stored {
+		if c.Secure && !secure {
+			continue
+		}
+		if c.HostOnly && host != c.Domain {
+			continue
+		}
+		if !c.HostOnly && host != c.Domain && !(strings.HasSuffix(host, "."+c.Domain) && !isIPAddress(host)) {
+			continue
+		}
+		if !cookiePathMatch(path, c.Path) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+
+	// Longer paths first, as RFC 6265 section 5.4 recommends.
+	sort.SliceStable(matched, func(a, b int) bool { return len(matched[a].Path) > len(matched[b].Path) })
+
+	cookies := make([]*http.Cookie, 0, len(matched))
+	for _, c := range matched {
+		cookies = append(cookies, &http.Cookie{Name: c.Name, Value: c.Value})
+	}
+	return cookies
+}
+
+func canonicalCookieHost(host string) (string, error) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", fmt.Errorf("empty host")
+	}
+	return host, nil
+}
+
+// cookieDomain validates a Domain attribute against the request host and returns the domain
+// to store. A missing attribute makes the cookie host-only.
+func cookieDomain(host string, attribute string) (string, bool, bool) {
+	domain := strings.ToLower(strings.TrimPrefix(attribute, "."))
+	if domain == "" {
+		return host, true, true
+	}
+	if isIPAddress(host) {
+		return host, true, domain == host
+	}
+	if host != domain && !strings.HasSuffix(host, "."+domain) {
+		return "", false, false
+	}
+	if suffix, _ := publicsuffix.PublicSuffix(domain); suffix == domain {
+		// A public suffix is only acceptable as the exact host, and then only host-only.
+		return host, true, host == domain
+	}
+	return domain, false, true
+}
+
+func defaultCookiePath(requestPath string) string {
+	if requestPath == "" || requestPath[0] != '/' {
+		return "/"
+	}
+	i := strings.LastIndex(requestPath, "/")
+	if i == 0 {
+		return "/"
+	}
+	return requestPath[:i]
+}
+
+func cookiePathMatch(requestPath string, cookiePath string) bool {
+	if requestPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
+
+func isIPAddress(host string) bool {
+	return net.ParseIP(host) != nil
+}
+
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	}
+	return ""
+}
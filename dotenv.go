@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type EnvarWarning struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// dotenvEntry is a single assignment. Line and EndLine are 1-based and differ when a
+// quoted value spans several lines.
+type dotenvEntry struct {
+	Key      string
+	Value    string
+	Line     int
+	EndLine  int
+	Exported bool
+}
+
+type dotenvFile struct {
+	Entries  []dotenvEntry
+	Extends  string
+	Warnings []EnvarWarning
+}
+
+func (f dotenvFile) variables() map[string]string {
+	vars := make(map[string]string, len(f.Entries))
+	for _, entry := range f.Entries {
+		vars[entry.Key] = entry.Value
+	}
+	return vars
+}
+
+// parseDotenv parses env file content following the common dotenv conventions:
+// `#` comments, an optional `export` prefix, single, double and backtick quoting,
+// escape sequences inside double quotes and quoted values spanning multiple lines.
+// Malformed lines are reported as warnings and skipped; parsing continues after them.
+//
+// An `extends: name` line, bare or as a comment, declares the environment this file inherits from.
+func parseDotenv(content string) dotenvFile {
+	var (
+		entries  []dotenvEntry
+		warnings []EnvarWarning
+		extends  string
+	)
+	seen := make(map[string]int)
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		directive := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		if name, ok := strings.CutPrefix(directive, "extends:"); ok {
+			if extends != "" {
+				warnings = append(warnings, EnvarWarning{Line: lineNo, Message: "only one extends declaration is allowed, ignoring this one"})
+			} else if extends = strings.TrimSpace(name); extends == "" {
+				warnings = append(warnings, EnvarWarning{Line: lineNo, Message: "extends declaration is missing an environment name"})
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		exported := false
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			exported = true
+			line = strings.TrimSpace(rest)
+		}
+
+		eq := strings.Index(line, "=")
+		if eq == -1 {
+			warnings = append(warnings, EnvarWarning{Line: lineNo, Message: fmt.Sprintf("expected KEY=VALUE, got %q", line)})
+			continue
+		}
+
+		key := strings.TrimSpace(line[:eq])
+		if !isValidDotenvKey(key) {
+			warnings = append(warnings, EnvarWarning{Line: lineNo, Message: fmt.Sprintf("invalid variable name %q", key)})
+			continue
+		}
+
+		raw := strings.TrimLeft(line[eq+1:], " \t")
+		value, endIndex, err := parseDotenvValue(raw, lines, i)
+		if err != nil {
+			warnings = append(warnings, EnvarWarning{Line: lineNo, Message: fmt.Sprintf("%s: %v", key, err)})
+			continue
+		}
+
+		if previous, ok := seen[key]; ok {
+			warnings = append(warnings, EnvarWarning{Line: lineNo, Message: fmt.Sprintf("%s is already defined on line %d and is overridden", key, previous)})
+		}
+		seen[key] = lineNo
+
+		entries = append(entries, dotenvEntry{
+			Key:      key,
+			Value:    value,
+			Line:     lineNo,
+			EndLine:  endIndex + 1,
+			Exported: exported,
+		})
+		i = endIndex
+	}
+
+	return dotenvFile{Entries: entries, Extends: extends, Warnings: warnings}
+}
+
+// parseDotenvValue parses the value that starts on lines[index] and returns it together with
+// the index of the line the value ends on.
+func parseDotenvValue(raw string, lines []string, index int) (string, int, error) {
+	if raw == "" {
+		return "", index, nil
+	}
+
+	quote := raw[0]
+	if quote != '"' && quote != '\'' && quote != '`' {
+		// Unquoted values end at an inline comment, which must be preceded by whitespace.
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '#' && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+				raw = raw[:i]
+				break
+			}
+		}
+		return strings.TrimSpace(raw), index, nil
+	}
+
+	var b strings.Builder
+	current := raw[1:]
+	end := index
+	for {
+		for i := 0; i < len(current); i++ {
+			c := current[i]
+			if c == quote {
+				trailing := strings.TrimSpace(current[i+1:])
+				if trailing != "" && !strings.HasPrefix(trailing, "#") {
+					return "", end, fmt.Errorf("unexpected characters after closing quote: %q", trailing)
+				}
+				return b.String(), end, nil
+			}
+			if quote == '"' && c == '\\' && i+1 < len(current) {
+				i++
+				switch current[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				case '"', '\\', '$':
+					b.WriteByte(current[i])
+				default:
+					b.WriteByte('\\')
+					b.WriteByte(current[i])
+				}
+				continue
+			}
+			b.WriteByte(c)
+		}
+
+		end++
+		if end >= len(lines) {
+			return "", index, fmt.Errorf("unterminated %c quoted value", quote)
+		}
+		b.WriteByte('\n')
+		current = lines[end]
+	}
+}
+
+func isValidDotenvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case i > 0 && ((r >= '0' && r <= '9') || r == '.' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// formatDotenvValue quotes a value when writing it back would otherwise change its meaning.
+func formatDotenvValue(value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t\n\r#\"'`\\") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
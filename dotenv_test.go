@@ -0,0 +1,175 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		entries  []dotenvEntry
+		extends  string
+		warnings []EnvarWarning
+	}{
+		{
+			name:    "plain assignments",
+			content: "A=1\nB = two words \nC=\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "1", Line: 1, EndLine: 1},
+				{Key: "B", Value: "two words", Line: 2, EndLine: 2},
+				{Key: "C", Value: "", Line: 3, EndLine: 3},
+			},
+		},
+		{
+			name:    "comments and blank lines",
+			content: "# heading\n\n  # indented\nA=1 # trailing\nB=a#b\nC=#not a comment\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "1", Line: 4, EndLine: 4},
+				{Key: "B", Value: "a#b", Line: 5, EndLine: 5},
+				{Key: "C", Value: "#not a comment", Line: 6, EndLine: 6},
+			},
+		},
+		{
+			name:    "export prefix",
+			content: "export A=1\nexport\tB=2\nexporter=3\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "1", Line: 1, EndLine: 1, Exported: true},
+				{Key: "B", Value: "2", Line: 2, EndLine: 2, Exported: true},
+				{Key: "exporter", Value: "3", Line: 3, EndLine: 3},
+			},
+		},
+		{
+			name:    "key characters",
+			content: "api.base-url=x\n_private=y\nV2=z\n",
+			entries: []dotenvEntry{
+				{Key: "api.base-url", Value: "x", Line: 1, EndLine: 1},
+				{Key: "_private", Value: "y", Line: 2, EndLine: 2},
+				{Key: "V2", Value: "z", Line: 3, EndLine: 3},
+			},
+		},
+		{
+			name:    "single and backtick quotes are literal",
+			content: "A='x # y \\n'\nB=`say \"hi\"` # comment\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: `x # y \n`, Line: 1, EndLine: 1},
+				{Key: "B", Value: `say "hi"`, Line: 2, EndLine: 2},
+			},
+		},
+		{
+			name:    "double quote escapes",
+			content: `A="l1\nl2\t\"q\" \\ \$HOME \x"` + "\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "l1\nl2\t\"q\" \\ $HOME \\x", Line: 1, EndLine: 1},
+			},
+		},
+		{
+			name:    "multiline values",
+			content: "A=\"first\nsecond\"\nB='one\n\nthree'\nC=after\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "first\nsecond", Line: 1, EndLine: 2},
+				{Key: "B", Value: "one\n\nthree", Line: 3, EndLine: 5},
+				{Key: "C", Value: "after", Line: 6, EndLine: 6},
+			},
+		},
+		{
+			name:    "windows line endings",
+			content: "A=1\r\nB=\"x\r\ny\"\r\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "1", Line: 1, EndLine: 1},
+				{Key: "B", Value: "x\ny", Line: 2, EndLine: 3},
+			},
+		},
+		{
+			name:    "extends directive",
+			content: "extends: base\nA=1\n",
+			entries: []dotenvEntry{{Key: "A", Value: "1", Line: 2, EndLine: 2}},
+			extends: "base",
+		},
+		{
+			name:    "extends as a comment",
+			content: "# extends: shared \nA=1\n",
+			entries: []dotenvEntry{{Key: "A", Value: "1", Line: 2, EndLine: 2}},
+			extends: "shared",
+		},
+		{
+			name:     "second extends is ignored",
+			content:  "extends: base\nextends: other\n",
+			extends:  "base",
+			warnings: []EnvarWarning{{Line: 2, Message: "only one extends declaration is allowed"}},
+		},
+		{
+			name:     "extends without a name",
+			content:  "extends:\n",
+			warnings: []EnvarWarning{{Line: 1, Message: "missing an environment name"}},
+		},
+		{
+			name:    "missing equals sign",
+			content: "A=1\nJUSTAKEY\nB=2\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "1", Line: 1, EndLine: 1},
+				{Key: "B", Value: "2", Line: 3, EndLine: 3},
+			},
+			warnings: []EnvarWarning{{Line: 2, Message: `expected KEY=VALUE, got "JUSTAKEY"`}},
+		},
+		{
+			name:     "invalid keys",
+			content:  "1X=2\n=3\nA B=4\n",
+			warnings: []EnvarWarning{{Line: 1, Message: `invalid variable name "1X"`}, {Line: 2, Message: `invalid variable name ""`}, {Line: 3, Message: `invalid variable name "A B"`}},
+		},
+		{
+			name:     "text after closing quote",
+			content:  "A=\"x\" junk\nB=2\n",
+			entries:  []dotenvEntry{{Key: "B", Value: "2", Line: 2, EndLine: 2}},
+			warnings: []EnvarWarning{{Line: 1, Message: `A: unexpected characters after closing quote: "junk"`}},
+		},
+		{
+			name:     "unterminated quote",
+			content:  "A='open\nB=2\n",
+			entries:  []dotenvEntry{{Key: "B", Value: "2", Line: 2, EndLine: 2}},
+			warnings: []EnvarWarning{{Line: 1, Message: "A: unterminated ' quoted value"}},
+		},
+		{
+			name:    "duplicate keys",
+			content: "A=1\nA=2\n",
+			entries: []dotenvEntry{
+				{Key: "A", Value: "1", Line: 1, EndLine: 1},
+				{Key: "A", Value: "2", Line: 2, EndLine: 2},
+			},
+			warnings: []EnvarWarning{{Line: 2, Message: "A is already defined on line 1 and is overridden"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDotenv(tt.content)
+			if !reflect.DeepEqual(got.Entries, tt.entries) {
+				t.Errorf("entries = %+v, want %+v", got.Entries, tt.entries)
+			}
+			if got.Extends != tt.extends {
+				t.Errorf("extends = %q, want %q", got.Extends, tt.extends)
+			}
+			if len(got.Warnings) != len(tt.warnings) {
+				t.Fatalf("warnings = %+v, want %+v", got.Warnings, tt.warnings)
+			}
+			for i, want := range tt.warnings {
+				if got.Warnings[i].Line != want.Line || !strings.Contains(got.Warnings[i].Message, want.Message) {
+					t.Errorf("warning %d = %+v, want %+v", i, got.Warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatDotenvValueRoundTrip(t *testing.T) {
+	for _, value := range []string{"", "plain", "two words", "a#b", "line\nbreak", `quote " and \ slash`, "tab\there", "it's", "`tick`"} {
+		t.Run(value, func(t *testing.T) {
+			parsed := parseDotenv("A=" + formatDotenvValue(value))
+			if len(parsed.Warnings) > 0 || len(parsed.Entries) != 1 || parsed.Entries[0].Value != value {
+				t.Errorf("formatDotenvValue(%q) read back as %+v", value, parsed)
+			}
+		})
+	}
+}
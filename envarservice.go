@@ -1,8 +1,8 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"github.com/fsnotify/fsnotify"
@@ -11,17 +11,32 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 type EnvarService struct {
 	app *application.App
+
+	secretMu  sync.Mutex
+	secretKey []byte
 }
 
+const globalEnvironmentName = "global"
+
 type EnvarJSON struct {
-	Env       string            `json:"env"`
+	Env     string `json:"env"`
+	Extends string `json:"extends,omitempty"`
+	Global  bool   `json:"global"`
+	// Variables holds the file's own assignments, Effective the merged result after
+	// inheritance and Sources the file each effective value came from.
 	Variables map[string]string `json:"variables"`
+	Effective map[string]string `json:"effective"`
+	Sources   map[string]string `json:"sources"`
+	Secrets   []string          `json:"secrets"`
+	Warnings  []EnvarWarning    `json:"warnings"`
 }
 
 func (s *EnvarService) InitEnvarWatch(ctx context.Context) {
@@ -69,8 +84,6 @@ func (s *EnvarService) ScanEnvars() json.RawMessage {
 
 	envarList := []EnvarJSON{}
 
-	envarMap := make(map[string]map[string]string)
-
 	entries, err := os.ReadDir("./data/environments")
 	if err != nil {
 		fmt.Print(err)
@@ -80,36 +93,40 @@ func (s *EnvarService) ScanEnvars() json.RawMessage {
 		fileNames = append(fileNames, entry.Name())
 
 	}
-	for index, fileName := range fileNames {
-		vars := make(map[string]string)
-		fmt.Println(index)
-		file, err := os.Open("./data/environments/" + fileName)
+	for _, fileName := range fileNames {
+		file, err := readEnvFile(fileName)
 		if err != nil {
 			fmt.Println(err)
+			file.Warnings = append(file.Warnings, EnvarWarning{Message: err.Error()})
 		}
-		defer file.Close()
+		vars := file.variables()
 
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
+		resolved := resolveEnvironment(fileName)
+		warnings := append(file.Warnings, resolved.warnings...)
 
-			splitIndex := strings.Index(line, "=")
-			if splitIndex == -1 {
-				continue
+		var secrets []string
+		for key, value := range resolved.vars {
+			if isSecretValue(value) {
+				resolved.vars[key] = secretMask
+				secrets = append(secrets, key)
+			}
+		}
+		sort.Strings(secrets)
+		for key, value := range vars {
+			if isSecretValue(value) {
+				vars[key] = secretMask
 			}
-			lineLen := len(line)
-			key := line[0:splitIndex]
-			value := line[splitIndex+1 : lineLen]
-			vars[key] = value
 		}
-		envarMap[fileName] = vars
+
 		envarList = append(envarList, EnvarJSON{
 			Env:       fileName,
+			Extends:   file.Extends,
+			Global:    isGlobalEnvironment(fileName),
 			Variables: vars,
+			Effective: resolved.vars,
+			Sources:   resolved.sources,
+			Secrets:   secrets,
+			Warnings:  warnings,
 		})
 	}
 
@@ -118,6 +135,149 @@ func (s *EnvarService) ScanEnvars() json.RawMessage {
 	return jsonBytes
 }
 
+func readEnvFile(fileName string) (dotenvFile, error) {
+	content, err := os.ReadFile(filepath.Join("./data/environments", filepath.Base(fileName)))
+	if err != nil {
+		return dotenvFile{}, err
+	}
+	return parseDotenv(string(content)), nil
+}
+
+func isGlobalEnvironment(fileName string) bool {
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) == globalEnvironmentName
+}
+
+// findEnvironmentFile maps an environment name to its file, accepting the name with or
+// without the extension used by the file that refers to it.
+func findEnvironmentFile(name string, referrer string) (string, bool) {
+	candidates := []string{name}
+	if ext := filepath.Ext(referrer); ext != "" && filepath.Ext(name) == "" {
+		candidates = append(candidates, name+ext)
+	}
+	for _, candidate := range candidates {
+		if candidate != filepath.Base(candidate) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join("./data/environments", candidate)); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+type resolvedEnvironment struct {
+	vars     map[string]string
+	sources  map[string]string
+	warnings []EnvarWarning
+}
+
+// resolveEnvironment merges an environment with everything it inherits from. Precedence from
+// lowest to highest: the global environment, the extends chain starting at its root, and the
+// environment's own file. An empty name resolves to the global environment alone.
+func resolveEnvironment(fileName string) resolvedEnvironment {
+	resolved := resolvedEnvironment{
+		vars:    map[string]string{},
+		sources: map[string]string{},
+	}
+
+	var chain []string
+	visited := map[string]bool{}
+	current := fileName
+	for current != "" {
+		if visited[current] {
+			resolved.warnings = append(resolved.warnings, EnvarWarning{Message: fmt.Sprintf("circular extends declaration involving %s", current)})
+			break
+		}
+		visited[current] = true
+		chain = append(chain, current)
+
+		file, err := readEnvFile(current)
+		if err != nil {
+			resolved.warnings = append(resolved.warnings, EnvarWarning{Message: fmt.Sprintf("failed to read %s: %v", current, err)})
+			break
+		}
+		if file.Extends == "" {
+			break
+		}
+		parent, ok := findEnvironmentFile(file.Extends, current)
+		if !ok {
+			resolved.warnings = append(resolved.warnings, EnvarWarning{Message: fmt.Sprintf("%s extends %s, which does not exist", current, file.Extends)})
+			break
+		}
+		current = parent
+	}
+
+	if global, ok := findGlobalEnvironment(); ok && !visited[global] {
+		chain = append(chain, global)
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		file, err := readEnvFile(chain[i])
+		if err != nil {
+			continue
+		}
+		for _, entry := range file.Entries {
+			resolved.vars[entry.Key] = entry.Value
+			resolved.sources[entry.Key] = chain[i]
+		}
+	}
+
+	return resolved
+}
+
+func findGlobalEnvironment() (string, bool) {
+	entries, err := os.ReadDir("./data/environments")
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && isGlobalEnvironment(entry.Name()) {
+			return entry.Name(), true
+		}
+	}
+	return "", false
+}
+
+// setEnvFileValue replaces the last assignment of key in an env file, which is the one that
+// takes effect, or appends a new one. Other lines, comments included, are kept as they are.
+func setEnvFileValue(content string, key string, value string) string {
+	entries := parseDotenv(content).Entries
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Key != key {
+			continue
+		}
+
+		lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+		assignment := key + "=" + formatDotenvValue(value)
+		if entry.Exported {
+			assignment = "export " + assignment
+		}
+		updated := append([]string{}, lines[:entry.Line-1]...)
+		updated = append(updated, assignment)
+		updated = append(updated, lines[entry.EndLine:]...)
+		return strings.Join(updated, "\n")
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + key + "=" + formatDotenvValue(value) + "\n"
+}
+
+// removeEnvFileValue drops every assignment of key from an env file.
+func removeEnvFileValue(content string, key string) string {
+	entries := parseDotenv(content).Entries
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Key == key {
+			lines = append(lines[:entry.Line-1], lines[entry.EndLine:]...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (s *EnvarService) ReadEnvFile(filename string) (string, error) {
 	path := filepath.Join("./data/environments", filename)
 	data, err := os.ReadFile(path)
@@ -148,3 +308,300 @@ func (s *EnvarService) CreateEnvFile(filename string) error {
 	}
 	return f.Close()
 }
+
+func (s *EnvarService) SetSecretVariable(filename string, key string, value string) error {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, "=\n") {
+		return fmt.Errorf("invalid variable name %q", key)
+	}
+
+	secretKey, err := s.currentSecretKey()
+	if err != nil {
+		return err
+	}
+	encrypted, err := encryptSecret(secretKey, value)
+	if err != nil {
+		return err
+	}
+
+	content, err := s.ReadEnvFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.SaveEnvFile(filename, setEnvFileValue(content, key, encrypted))
+}
+
+func (s *EnvarService) RevealSecretVariable(filename string, key string) (string, error) {
+	if _, err := os.Stat(filepath.Join("./data/environments", filepath.Base(filename))); err != nil {
+		return "", err
+	}
+	value, ok := resolveEnvironment(filename).vars[key]
+	if !ok {
+		return "", fmt.Errorf("variable %s not found in %s", key, filename)
+	}
+	return s.revealValue(value)
+}
+
+func (s *EnvarService) GetSecretsStatus() (SecretsStatus, error) {
+	params, err := loadSecretKDFParams()
+	if err != nil {
+		return SecretsStatus{}, err
+	}
+
+	s.secretMu.Lock()
+	defer s.secretMu.Unlock()
+	if params == nil {
+		return SecretsStatus{Mode: SecretModeKeyFile, Unlocked: true}, nil
+	}
+	return SecretsStatus{Mode: SecretModePassphrase, Unlocked: s.secretKey != nil}, nil
+}
+
+func (s *EnvarService) UnlockSecrets(passphrase string) error {
+	params, err := loadSecretKDFParams()
+	if err != nil {
+		return err
+	}
+	if params == nil {
+		return fmt.Errorf("secrets are not protected by a passphrase")
+	}
+
+	key, err := deriveSecretKey(passphrase, params)
+	if err != nil {
+		return err
+	}
+	if !verifySecretKey(key, params) {
+		return fmt.Errorf("incorrect passphrase")
+	}
+
+	s.secretMu.Lock()
+	s.secretKey = key
+	s.secretMu.Unlock()
+	return nil
+}
+
+func (s *EnvarService) LockSecrets() {
+	s.secretMu.Lock()
+	s.secretKey = nil
+	s.secretMu.Unlock()
+}
+
+// SetSecretPassphrase re-encrypts every stored secret under a key derived from passphrase.
+// An empty passphrase switches back to a locally generated key file.
+func (s *EnvarService) SetSecretPassphrase(passphrase string) error {
+	oldKey, err := s.currentSecretKey()
+	if err != nil {
+		return err
+	}
+
+	var (
+		newKey []byte
+		params *secretKDFParams
+	)
+	if passphrase == "" {
+		newKey = make([]byte, secretKeySize)
+		if _, err := rand.Read(newKey); err != nil {
+			return fmt.Errorf("failed to generate secret key: %w", err)
+		}
+	} else if params, newKey, err = newSecretKDFParams(passphrase); err != nil {
+		return err
+	}
+
+	// Everything is re-encrypted into temporary files first and only then swapped in. Each file
+	// that is replaced is moved aside, and if a rename fails part way the ones already done are
+	// put back, so the environments stay readable with the key that is on disk.
+	type replacement struct {
+		// temp is renamed over target, or target is removed when temp is empty.
+		temp   string
+		target string
+		backup string
+		placed bool
+	}
+	var replacements []replacement
+	// Temporary files that were not renamed into place are removed; the rest are gone already.
+	defer func() {
+		for _, r := range replacements {
+			if r.temp != "" && !r.placed {
+				os.Remove(r.temp)
+			}
+		}
+	}()
+	restore := func(done []replacement) {
+		for i := len(done) - 1; i >= 0; i-- {
+			if done[i].placed {
+				os.Remove(done[i].target)
+			}
+			if done[i].backup != "" {
+				os.Rename(done[i].backup, done[i].target)
+			}
+		}
+	}
+	stage := func(target string, content []byte, perm os.FileMode) error {
+		temp, err := writeSecretTempFile(content, perm)
+		if err != nil {
+			return err
+		}
+		replacements = append(replacements, replacement{temp: temp, target: target})
+		return nil
+	}
+
+	entries, err := os.ReadDir("./data/environments")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := s.ReadEnvFile(entry.Name())
+		if err != nil {
+			return err
+		}
+		content, changed, err := reencryptSecrets(content, oldKey, newKey)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt %s: %w", entry.Name(), err)
+		}
+		if changed {
+			if err := stage(filepath.Join("./data/environments", entry.Name()), []byte(content), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	obsolete := secretKDFFile
+	if params != nil {
+		raw, err := json.MarshalIndent(params, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := stage(secretKDFFile, raw, 0o600); err != nil {
+			return err
+		}
+		obsolete = secretKeyFile
+	} else if err := stage(secretKeyFile, encodeSecretKey(newKey), 0o600); err != nil {
+		return err
+	}
+
+	// The key goes in first, then the environments follow it and the other kind of key file goes.
+	replacements = append(replacements[len(replacements)-1:], replacements[:len(replacements)-1]...)
+	replacements = append(replacements, replacement{target: obsolete})
+	for i := range replacements {
+		r := &replacements[i]
+		backup := fmt.Sprintf("./data/.rekey-backup-%d", i)
+		if err := os.Rename(r.target, backup); err == nil {
+			r.backup = backup
+		} else if !os.IsNotExist(err) {
+			restore(replacements[:i])
+			return fmt.Errorf("failed to replace %s: %w", r.target, err)
+		}
+		if r.temp == "" {
+			continue
+		}
+		if err := os.Rename(r.temp, r.target); err != nil {
+			restore(replacements[:i+1])
+			return fmt.Errorf("failed to replace %s: %w", r.target, err)
+		}
+		r.placed = true
+	}
+	for _, r := range replacements {
+		if r.backup != "" {
+			os.Remove(r.backup)
+		}
+	}
+
+	s.secretMu.Lock()
+	s.secretKey = newKey
+	s.secretMu.Unlock()
+	return nil
+}
+
+// setVariable writes a single variable into an environment file. A variable that is currently
+// a secret stays encrypted with the new value.
+func (s *EnvarService) setVariable(filename string, key string, value string) error {
+	if !isValidDotenvKey(key) {
+		return fmt.Errorf("invalid variable name %q", key)
+	}
+	file, err := readEnvFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if isSecretValue(file.variables()[key]) {
+		return s.SetSecretVariable(filename, key, value)
+	}
+
+	content, err := s.ReadEnvFile(filepath.Base(filename))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.SaveEnvFile(filepath.Base(filename), setEnvFileValue(content, key, value))
+}
+
+func (s *EnvarService) unsetVariable(filename string, key string) error {
+	content, err := s.ReadEnvFile(filepath.Base(filename))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.SaveEnvFile(filepath.Base(filename), removeEnvFileValue(content, key))
+}
+
+// globalEnvironmentFile returns the global environment's file name, defaulting to a new
+// global.env when there is none yet.
+func globalEnvironmentFile() string {
+	if name, ok := findGlobalEnvironment(); ok {
+		return name
+	}
+	return globalEnvironmentName + ".env"
+}
+
+// environmentVariables returns the effective raw variables of an environment, inherited ones
+// included; secrets stay encrypted until revealValue is called for the ones a request uses.
+func (s *EnvarService) environmentVariables(environment string) (map[string]string, error) {
+	resolved, err := s.resolvedEnvironment(environment)
+	return resolved.vars, err
+}
+
+func (s *EnvarService) resolvedEnvironment(environment string) (resolvedEnvironment, error) {
+	environment = strings.TrimSpace(environment)
+	if environment != "" {
+		if _, err := os.Stat(filepath.Join("./data/environments", filepath.Base(environment))); err != nil {
+			return resolveEnvironment(""), err
+		}
+	}
+	return resolveEnvironment(environment), nil
+}
+
+func (s *EnvarService) revealValue(value string) (string, error) {
+	if !isSecretValue(value) {
+		return value, nil
+	}
+	key, err := s.currentSecretKey()
+	if err != nil {
+		return "", err
+	}
+	return decryptSecret(key, value)
+}
+
+func (s *EnvarService) currentSecretKey() ([]byte, error) {
+	s.secretMu.Lock()
+	defer s.secretMu.Unlock()
+	if s.secretKey != nil {
+		return s.secretKey, nil
+	}
+
+	params, err := loadSecretKDFParams()
+	if err != nil {
+		return nil, err
+	}
+	if params != nil {
+		return nil, fmt.Errorf("secrets are locked, unlock them with your passphrase first")
+	}
+
+	key, err := loadOrCreateSecretKeyFile()
+	if err != nil {
+		return nil, err
+	}
+	s.secretKey = key
+	return key, nil
+}
@@ -2,9 +2,12 @@ package main
 
 import (
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
+	"strconv"
+	"strings"
 )
 
 type FileService struct {
@@ -35,17 +38,37 @@ type PostmanRequest struct {
 	Auth   interface{} `json:"auth"`
 }
 
+type PostmanVariable struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled"`
+}
+
 type PostmanItem struct {
-	ID          string          `json:"ID"`
-	Name        string          `json:"name"`
-	Description interface{}     `json:"description"`
-	Request     *PostmanRequest `json:"request,omitempty"`
-	Items       []PostmanItem   `json:"item,omitempty"`
+	ID          string            `json:"ID"`
+	Name        string            `json:"name"`
+	Description interface{}       `json:"description"`
+	Request     *PostmanRequest   `json:"request,omitempty"`
+	Items       []PostmanItem     `json:"item,omitempty"`
+	Variables   []PostmanVariable `json:"variable,omitempty"`
+	Events      []PostmanEvent    `json:"event,omitempty"`
+	Auth        interface{}       `json:"auth,omitempty"`
+}
+
+// PostmanEvent is a script attached to an item; Listen is "prerequest" or "test".
+type PostmanEvent struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec interface{} `json:"exec"`
+	} `json:"script"`
 }
 
 type PostmanCollection struct {
-	Info  PostmanInfo   `json:"info"`
-	Items []PostmanItem `json:"item"`
+	Info      PostmanInfo       `json:"info"`
+	Items     []PostmanItem     `json:"item"`
+	Variables []PostmanVariable `json:"variable,omitempty"`
+	Events    []PostmanEvent    `json:"event,omitempty"`
+	Auth      interface{}       `json:"auth,omitempty"`
 }
 
 func (s *FileService) ParsePostmanV21Collection(rawExportJSON string) error {
@@ -66,9 +89,10 @@ func (s *FileService) ParsePostmanV21Collection(rawExportJSON string) error {
 		identifier = collection.Info.Version.Identifier
 	}
 
+	headers, auth := postmanCollectionDefaults(collection.Auth)
 	_, err := s.db.Exec(`
-		INSERT INTO collections (id, name, description, schema, version_major, version_minor, version_patch, version_identifier, parent_collection)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		INSERT INTO collections (id, name, description, schema, version_major, version_minor, version_patch, version_identifier, parent_collection, headers, auth, sort_order)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, `+nextCollectionSortOrder+`)`,
 		rootCollectionID,
 		collection.Info.Name,
 		collection.Info.Description,
@@ -78,11 +102,23 @@ func (s *FileService) ParsePostmanV21Collection(rawExportJSON string) error {
 		patch,
 		identifier,
 		nil, // Root collection has no parent
+		headers,
+		auth,
+		nil,
+		rootCollectionID,
 	)
 	if err != nil {
 		return fmt.Errorf("error inserting root collection: %w", err)
 	}
 
+	if err := insertVariables(s.db, "collection_variables", "collection_id", rootCollectionID, postmanVariables(collection.Variables), false); err != nil {
+		return fmt.Errorf("error inserting collection variables: %w", err)
+	}
+
+	if err := insertCollectionScripts(s.db, rootCollectionID, collection.Events); err != nil {
+		return fmt.Errorf("error inserting collection scripts: %w", err)
+	}
+
 	if err := s.processItems(rootCollectionID, collection.Items, 0); err != nil {
 		return err
 	}
@@ -91,8 +127,11 @@ func (s *FileService) ParsePostmanV21Collection(rawExportJSON string) error {
 	return nil
 }
 
+// processItems imports folders and requests in the order Postman lists them. Folders and
+// requests are numbered separately since collections list their folders first.
 func (s *FileService) processItems(parentCollectionID string, items []PostmanItem, sortOrder int) error {
 	currentSortOrder := sortOrder
+	folderSortOrder := 0
 
 	for _, item := range items {
 		// If Postman item has no request but has items, it's a folder (subcollection)
@@ -101,47 +140,46 @@ func (s *FileService) processItems(parentCollectionID string, items []PostmanIte
 
 			folderID := uuid.New().String()
 
-			descStr := ""
-			switch d := item.Description.(type) {
-			case string:
-				descStr = d
-			case map[string]interface{}:
-				if content, ok := d["content"].(string); ok {
-					descStr = content
-				}
-			}
+			descStr := postmanDescription(item.Description)
+			headers, auth := postmanCollectionDefaults(item.Auth)
 
 			_, err := s.db.Exec(`
-				INSERT INTO collections (id, name, description, schema, version_major, version_minor, version_patch, version_identifier, parent_collection)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				INSERT INTO collections (id, name, description, schema, version_major, version_minor, version_patch, version_identifier, parent_collection, headers, auth, sort_order)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 				folderID,
 				item.Name,
 				descStr,
 				"",
 				0, 0, 0, "",
 				parentCollectionID,
+				headers,
+				auth,
+				folderSortOrder,
 			)
 			if err != nil {
 				return fmt.Errorf("error inserting folder '%s': %w", item.Name, err)
 			}
 
+			if err := insertVariables(s.db, "collection_variables", "collection_id", folderID, postmanVariables(item.Variables), false); err != nil {
+				return fmt.Errorf("error inserting variables for folder '%s': %w", item.Name, err)
+			}
+
+			if err := insertCollectionScripts(s.db, folderID, item.Events); err != nil {
+				return fmt.Errorf("error inserting scripts for folder '%s': %w", item.Name, err)
+			}
+
 			if err := s.processItems(folderID, item.Items, 0); err != nil {
 				return err
 			}
+			folderSortOrder++
 		} else if item.Request != nil {
 			fmt.Printf("Processing request: %s %s\n", item.Request.Method, item.Name)
 
-			descStr := ""
-			switch d := item.Description.(type) {
-			case string:
-				descStr = d
-			case map[string]interface{}:
-				if content, ok := d["content"].(string); ok {
-					descStr = content
-				}
-			}
+			descStr := postmanDescription(item.Description)
 
 			urlStr := ""
+			var queryParams []QueryParam
+			var pathVariables []PathVariable
 			switch u := item.Request.URL.(type) {
 			case string:
 				urlStr = u
@@ -149,35 +187,293 @@ func (s *FileService) processItems(parentCollectionID string, items []PostmanIte
 				if raw, ok := u["raw"].(string); ok {
 					urlStr = raw
 				}
+				queryParams = postmanQueryParams(u["query"])
+				pathVariables = postmanPathVariables(u["variable"])
 			}
 
-			headerJSON, _ := json.Marshal(item.Request.Header)
+			headerJSON := postmanHeaders(item.Request.Header)
 			bodyJSON, _ := json.Marshal(item.Request.Body)
-			authJSON, _ := json.Marshal(item.Request.Auth)
+			auth, authHeader := postmanAuth(item.Request.Auth)
+			if authHeader != nil {
+				headerJSON = appendHeaderEntry(headerJSON, *authHeader)
+			}
 
-			_, err := s.db.Exec(`
+			var requestID int
+			err := s.db.QueryRow(`
 				INSERT INTO requests (collection_id, name, description, method, url, headers, body, auth, sort_order)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+				RETURNING id`,
 				parentCollectionID,
 				item.Name,
 				descStr,
 				item.Request.Method,
 				urlStr,
-				string(headerJSON),
+				headerJSON,
 				string(bodyJSON),
-				string(authJSON),
+				emptyStringToNullString(auth),
 				currentSortOrder,
-			)
+			).Scan(&requestID)
 			if err != nil {
 				return fmt.Errorf("error inserting request '%s': %w", item.Name, err)
 			}
 
+			if err := insertVariables(s.db, "request_variables", "request_id", requestID, postmanVariables(item.Variables), false); err != nil {
+				return fmt.Errorf("error inserting variables for request '%s': %w", item.Name, err)
+			}
+
+			if queryParams == nil {
+				_, query, _ := splitURLQuery(urlStr)
+				queryParams = parseQueryParams(query)
+			}
+			if err := writeRequestParams(s.db, requestID, queryParams); err != nil {
+				return fmt.Errorf("error inserting params for request '%s': %w", item.Name, err)
+			}
+
+			if err := insertPathVariables(s.db, requestID, pathVariables); err != nil {
+				return fmt.Errorf("error inserting path variables for request '%s': %w", item.Name, err)
+			}
+
+			if preRequest, postResponse := postmanScripts(item.Events); preRequest != "" || postResponse != "" {
+				if _, err := s.db.Exec(
+					"INSERT INTO request_scripts (request_id, pre_request, post_response) VALUES (?, ?, ?)",
+					requestID,
+					preRequest,
+					postResponse,
+				); err != nil {
+					return fmt.Errorf("error inserting scripts for request '%s': %w", item.Name, err)
+				}
+			}
+
 			currentSortOrder++
 		}
 	}
 	return nil
 }
 
+func postmanQueryParams(raw interface{}) []QueryParam {
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+	params := make([]QueryParam, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := QueryParam{Enabled: true}
+		p.Key, _ = fields["key"].(string)
+		p.Value, _ = fields["value"].(string)
+		if disabled, ok := fields["disabled"].(bool); ok && disabled {
+			p.Enabled = false
+		}
+		p.Description = postmanDescription(fields["description"])
+		params = append(params, p)
+	}
+	return params
+}
+
+func postmanPathVariables(raw interface{}) []PathVariable {
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+	variables := make([]PathVariable, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v := PathVariable{Description: postmanDescription(fields["description"])}
+		v.Key, _ = fields["key"].(string)
+		switch value := fields["value"].(type) {
+		case string:
+			v.Value = value
+		case float64:
+			v.Value = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		variables = append(variables, v)
+	}
+	return variables
+}
+
+func postmanDescription(raw interface{}) string {
+	switch d := raw.(type) {
+	case string:
+		return d
+	case map[string]interface{}:
+		if content, ok := d["content"].(string); ok {
+			return content
+		}
+	}
+	return ""
+}
+
+// insertCollectionScripts stores the scripts of a Postman collection or folder, which run
+// before and after the scripts of every request below it.
+func insertCollectionScripts(db *sql.DB, collectionID string, events []PostmanEvent) error {
+	preRequest, postResponse := postmanScripts(events)
+	if preRequest == "" && postResponse == "" {
+		return nil
+	}
+	_, err := db.Exec(
+		"INSERT INTO collection_scripts (collection_id, pre_request, post_response) VALUES (?, ?, ?)",
+		collectionID,
+		preRequest,
+		postResponse,
+	)
+	return err
+}
+
+// postmanScripts joins the exec lines of an item's prerequest and test events.
+func postmanScripts(events []PostmanEvent) (string, string) {
+	var preRequest, postResponse []string
+	for _, event := range events {
+		var script string
+		switch exec := event.Script.Exec.(type) {
+		case string:
+			script = exec
+		case []interface{}:
+			lines := make([]string, 0, len(exec))
+			for _, line := range exec {
+				lines = append(lines, fmt.Sprint(line))
+			}
+			script = strings.Join(lines, "\n")
+		}
+		if strings.TrimSpace(script) == "" {
+			continue
+		}
+		switch event.Listen {
+		case "prerequest":
+			preRequest = append(preRequest, script)
+		case "test":
+			postResponse = append(postResponse, script)
+		}
+	}
+	return strings.Join(preRequest, "\n"), strings.Join(postResponse, "\n")
+}
+
+// postmanAuth converts a Postman auth object into the value sent as the Authorization header.
+// An API key sent as a header comes back as a header instead. Postman's inherit type, or no
+// auth at all, gives an empty auth so the request inherits from its folders; noauth gives
+// authNone.
+func postmanAuth(raw interface{}) (string, *HeaderEntry) {
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	authType, _ := fields["type"].(string)
+	params := postmanAuthParams(fields[authType])
+
+	switch authType {
+	case "", "inherit":
+		return "", nil
+	case "noauth":
+		return authNone, nil
+	case "bearer":
+		return "Bearer " + params["token"], nil
+	case "basic":
+		return basicAuthValue(params["username"], params["password"]), nil
+	case "oauth2":
+		if params["accessToken"] == "" {
+			break
+		}
+		prefix := params["headerPrefix"]
+		if prefix == "" {
+			prefix = "Bearer"
+		}
+		return prefix + " " + params["accessToken"], nil
+	case "apikey":
+		if params["in"] == "" || params["in"] == "header" {
+			enabled := true
+			return "", &HeaderEntry{Key: params["key"], Value: params["value"], Enabled: &enabled}
+		}
+	}
+	fmt.Printf("Skipping unsupported Postman auth %q\n", authType)
+	return "", nil
+}
+
+// postmanAuthParams reads the settings of a Postman auth type, a list of key/value entries in
+// v2.1 and a plain object in v2.0.
+func postmanAuthParams(raw interface{}) map[string]string {
+	params := make(map[string]string)
+	switch entries := raw.(type) {
+	case []interface{}:
+		for _, entry := range entries {
+			if fields, ok := entry.(map[string]interface{}); ok {
+				if key, ok := fields["key"].(string); ok && fields["value"] != nil {
+					params[key] = fmt.Sprint(fields["value"])
+				}
+			}
+		}
+	case map[string]interface{}:
+		for key, value := range entries {
+			if value != nil {
+				params[key] = fmt.Sprint(value)
+			}
+		}
+	}
+	return params
+}
+
+// postmanCollectionDefaults converts the auth of a Postman collection or folder into the
+// default headers and auth of the imported collection.
+func postmanCollectionDefaults(raw interface{}) (sql.NullString, sql.NullString) {
+	auth, header := postmanAuth(raw)
+	var headers sql.NullString
+	if header != nil {
+		headers = sql.NullString{String: appendHeaderEntry("", *header), Valid: true}
+	}
+	return headers, emptyStringToNullString(auth)
+}
+
+// basicAuthValue encodes basic credentials. Credentials containing placeholders are encoded
+// when the request is sent, through the base64 template function.
+func basicAuthValue(username string, password string) string {
+	credentials := username + ":" + password
+	if !strings.Contains(credentials, "{{") {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	var args []string
+	rest := credentials
+	for rest != "" {
+		loc := placeholderPattern.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			args = append(args, strconv.Quote(rest))
+			break
+		}
+		if loc[0] > 0 {
+			args = append(args, strconv.Quote(rest[:loc[0]]))
+		}
+		args = append(args, "("+strings.TrimSpace(rest[loc[2]:loc[3]])+")")
+		rest = rest[loc[1]:]
+	}
+	expression := "base64 (concat " + strings.Join(args, " ") + ")"
+	if strings.ContainsAny(expression, "{}") {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+	return "Basic {{" + expression + "}}"
+}
+
+func postmanVariables(vars []PostmanVariable) []Variable {
+	variables := make([]Variable, 0, len(vars))
+	for _, v := range vars {
+		value := ""
+		switch val := v.Value.(type) {
+		case nil:
+		case string:
+			value = val
+		case float64:
+			value = strconv.FormatFloat(val, 'f', -1, 64)
+		default:
+			value = fmt.Sprint(val)
+		}
+		variables = append(variables, Variable{Key: v.Key, Value: value, Enabled: !v.Disabled})
+	}
+	return variables
+}
+
 func (s *FileService) ImportPostmanCollection(jsonContent string) error {
 	return s.ParsePostmanV21Collection(jsonContent)
 }
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// databaseDSN opens the workspace database with foreign key enforcement, which SQLite leaves
+// off unless every connection asks for it.
+const databaseDSN = "./curlew_db.db?_pragma=foreign_keys(1)"
+
+// foreignKeyMigration is a reference that databases created by older versions lack. A table
+// missing it is rebuilt from its definition in file; triggers re-creates the triggers that are
+// dropped with the old table.
+type foreignKeyMigration struct {
+	table    string
+	column   string
+	parent   string
+	file     string
+	triggers string
+}
+
+var foreignKeyMigrations = []foreignKeyMigration{
+	{table: "collections", column: "parent_collection", parent: "collections", file: "sql/collections.sql"},
+	{table: "requests", column: "collection_id", parent: "collections", file: "sql/requests.sql", triggers: "sql/requests_fts.sql"},
+	{table: "responses", column: "request_id", parent: "requests", file: "sql/responses.sql", triggers: "sql/responses_fts.sql"},
+}
+
+// orphanCleanup detaches rows whose parent is already gone, so the rebuilt tables satisfy
+// their constraints. Requests and collections are kept; history is removed.
+var orphanCleanup = []string{
+	`UPDATE collections SET parent_collection = NULL
+	 WHERE parent_collection IS NOT NULL AND (parent_collection = id OR parent_collection NOT IN (SELECT id FROM collections))`,
+	`UPDATE requests SET collection_id = NULL
+	 WHERE collection_id IS NOT NULL AND collection_id NOT IN (SELECT id FROM collections)`,
+	`DELETE FROM responses WHERE request_id IS NOT NULL AND request_id NOT IN (SELECT id FROM requests)`,
+}
+
+// migrateForeignKeys rebuilds the tables created without ON DELETE rules and removes rows that
+// reference missing parents. It runs once per outdated table; up-to-date databases only pay
+// for the schema inspection.
+func migrateForeignKeys(db *sql.DB) error {
+	var pending []foreignKeyMigration
+	for _, migration := range foreignKeyMigrations {
+		ok, err := hasCascadingReference(db, migration)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	// Tables cannot be swapped while their references are enforced, and the pragma has no
+	// effect inside a transaction.
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start foreign key migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range orphanCleanup {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to clean up orphaned rows: %w", err)
+		}
+	}
+	for _, migration := range pending {
+		if err := rebuildTable(tx, migration); err != nil {
+			return err
+		}
+	}
+	if err := deleteForeignKeyViolations(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit foreign key migration: %w", err)
+	}
+	for _, migration := range pending {
+		fmt.Printf("Rebuilt %s with foreign key constraints\n", migration.table)
+	}
+	return nil
+}
+
+func hasCascadingReference(db *sql.DB, migration foreignKeyMigration) (bool, error) {
+	rows, err := db.Query("PRAGMA foreign_key_list(" + migration.table + ")")
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect %s foreign keys: %w", migration.table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id, seq                  int
+			parent, from             string
+			to                       sql.NullString
+			onUpdate, onDelete, kind string
+		)
+		if err := rows.Scan(&id, &seq, &parent, &from, &to, &onUpdate, &onDelete, &kind); err != nil {
+			return false, err
+		}
+		if strings.EqualFold(parent, migration.parent) && strings.EqualFold(from, migration.column) && onDelete == "CASCADE" {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// rebuildTable follows SQLite's recipe for changing constraints: create the new table, copy
+// the rows, drop the old table and rename the new one into place.
+func rebuildTable(tx *sql.Tx, migration foreignKeyMigration) error {
+	content, err := os.ReadFile(migration.file)
+	if err != nil {
+		return err
+	}
+	statements := strings.SplitN(string(content), ";", 2)
+	definition := "CREATE TABLE IF NOT EXISTS " + migration.table + " ("
+	if !strings.Contains(statements[0], definition) {
+		return fmt.Errorf("%s does not define %s", migration.file, migration.table)
+	}
+	rebuilt := migration.table + "_rebuild"
+	if _, err := tx.Exec(strings.Replace(statements[0], definition, "CREATE TABLE "+rebuilt+" (", 1)); err != nil {
+		return fmt.Errorf("failed to create %s: %w", rebuilt, err)
+	}
+
+	oldColumns, err := workspaceTableColumns(tx, migration.table)
+	if err != nil {
+		return err
+	}
+	newColumns, err := workspaceTableColumns(tx, rebuilt)
+	if err != nil {
+		return err
+	}
+	var shared []string
+	for column := range newColumns {
+		if _, ok := oldColumns[column]; ok {
+			shared = append(shared, column)
+		}
+	}
+	sort.Strings(shared)
+	columnList := strings.Join(shared, ", ")
+
+	for _, statement := range []string{
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", rebuilt, columnList, columnList, migration.table),
+		"DROP TABLE " + migration.table,
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", rebuilt, migration.table),
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to rebuild %s: %w", migration.table, err)
+		}
+	}
+
+	// Indexes follow the table definition in the same file.
+	if len(statements) > 1 && strings.TrimSpace(statements[1]) != "" {
+		if _, err := tx.Exec(statements[1]); err != nil {
+			return fmt.Errorf("failed to restore %s indexes: %w", migration.table, err)
+		}
+	}
+	if migration.triggers != "" {
+		triggers, err := os.ReadFile(migration.triggers)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(string(triggers)); err != nil {
+			return fmt.Errorf("failed to restore %s triggers: %w", migration.table, err)
+		}
+	}
+	return nil
+}
+
+// deleteForeignKeyViolations removes the remaining rows that point at missing requests or
+// collections, such as variables and scripts of requests deleted before enforcement.
+func deleteForeignKeyViolations(tx *sql.Tx) error {
+	rows, err := tx.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	type violation struct {
+		table string
+		rowid int64
+	}
+	var violations []violation
+	for rows.Next() {
+		var (
+			v      violation
+			rowid  sql.NullInt64
+			parent string
+			fkid   int
+		)
+		if err := rows.Scan(&v.table, &rowid, &parent, &fkid); err != nil {
+			rows.Close()
+			return err
+		}
+		if rowid.Valid {
+			v.rowid = rowid.Int64
+			violations = append(violations, v)
+		}
+	}
+	rows.Close()
+
+	for _, v := range violations {
+		if _, err := tx.Exec("DELETE FROM "+v.table+" WHERE rowid = ?", v.rowid); err != nil {
+			return fmt.Errorf("failed to remove orphaned %s row: %w", v.table, err)
+		}
+	}
+	if len(violations) > 0 {
+		fmt.Printf("Removed %d orphaned rows\n", len(violations))
+	}
+	return nil
+}
@@ -10,6 +10,10 @@ import {Call as $Call, Create as $Create} from "@wailsio/runtime";
 // @ts-ignore: Unused imports
 import * as json$0 from "../../../encoding/json/models.js";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
 /**
  * @param {string} filename
  * @returns {Promise<void> & { cancel(): void }}
@@ -19,6 +23,18 @@ export function CreateEnvFile(filename) {
     return $resultPromise;
 }
 
+/**
+ * @returns {Promise<$models.SecretsStatus> & { cancel(): void }}
+ */
+export function GetSecretsStatus() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3906488518));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
 /**
  * @returns {Promise<void> & { cancel(): void }}
  */
@@ -27,6 +43,14 @@ export function InitEnvarWatch() {
     return $resultPromise;
 }
 
+/**
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function LockSecrets() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3836149819));
+    return $resultPromise;
+}
+
 /**
  * @param {string} filename
  * @returns {Promise<string> & { cancel(): void }}
@@ -36,6 +60,16 @@ export function ReadEnvFile(filename) {
     return $resultPromise;
 }
 
+/**
+ * @param {string} filename
+ * @param {string} key
+ * @returns {Promise<string> & { cancel(): void }}
+ */
+export function RevealSecretVariable(filename, key) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2324306796, filename, key));
+    return $resultPromise;
+}
+
 /**
  * @param {string} filename
  * @param {string} content
@@ -53,3 +87,37 @@ export function ScanEnvars() {
     let $resultPromise = /** @type {any} */($Call.ByID(1532249697));
     return $resultPromise;
 }
+
+/**
+ * SetSecretPassphrase re-encrypts every stored secret under a key derived from passphrase.
+ * An empty passphrase switches back to a locally generated key file.
+ * @param {string} passphrase
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetSecretPassphrase(passphrase) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2754999291, passphrase));
+    return $resultPromise;
+}
+
+/**
+ * @param {string} filename
+ * @param {string} key
+ * @param {string} value
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetSecretVariable(filename, key, value) {
+    let $resultPromise = /** @type {any} */($Call.ByID(483473671, filename, key, value));
+    return $resultPromise;
+}
+
+/**
+ * @param {string} passphrase
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function UnlockSecrets(passphrase) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3271912622, passphrase));
+    return $resultPromise;
+}
+
+// Private type creation functions
+const $$createType0 = $models.SecretsStatus.createFrom;
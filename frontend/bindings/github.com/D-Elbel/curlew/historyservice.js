@@ -0,0 +1,48 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+/**
+ * HistoryService exposes a timeline of every execution: recorded responses and attempts that
+ * failed before a response arrived.
+ * @module
+ */
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import {Call as $Call, Create as $Create} from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * GetExecutionHosts lists the hosts seen in the timeline for the host filter.
+ * @returns {Promise<string[]> & { cancel(): void }}
+ */
+export function GetExecutionHosts() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3930747166));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * GetExecutionLog returns one page of the timeline, newest first.
+ * @param {$models.ExecutionLogFilter} filter
+ * @returns {Promise<$models.ExecutionLogPage> & { cancel(): void }}
+ */
+export function GetExecutionLog(filter) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3542298783, filter));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType1($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = $models.ExecutionLogPage.createFrom;
@@ -3,16 +3,24 @@
 // This file is automatically generated. DO NOT EDIT
 
 import * as AppStateService from "./appstateservice.js";
+import * as CookieService from "./cookieservice.js";
 import * as EnvarService from "./envarservice.js";
 import * as FileService from "./fileservice.js";
+import * as HistoryService from "./historyservice.js";
 import * as RequestCRUDService from "./requestcrudservice.js";
+import * as TrashService from "./trashservice.js";
 import * as UserService from "./userservice.js";
+import * as WorkspaceService from "./workspaceservice.js";
 export {
     AppStateService,
+    CookieService,
     EnvarService,
     FileService,
+    HistoryService,
     RequestCRUDService,
-    UserService
+    TrashService,
+    UserService,
+    WorkspaceService
 };
 
 export * from "./models.js";
@@ -6,10 +6,63 @@
 // @ts-ignore: Unused imports
 import {Create as $Create} from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as http$0 from "../../../net/http/models.js";
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import * as time$0 from "../../../time/models.js";
 
+/**
+ * BulkResult reports what a bulk operation did. Matched counts the requests it applied to and
+ * Changed lists the ones it actually modified; the rest already had the requested value.
+ */
+export class BulkResult {
+    /**
+     * Creates a new BulkResult instance.
+     * @param {Partial<BulkResult>} [$$source = {}] - The source object to create the BulkResult.
+     */
+    constructor($$source = {}) {
+        if (!("operation" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["operation"] = "";
+        }
+        if (!("matched" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["matched"] = 0;
+        }
+        if (!("changed" in $$source)) {
+            /**
+             * @member
+             * @type {number[]}
+             */
+            this["changed"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BulkResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {BulkResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("changed" in $$parsedSource) {
+            $$parsedSource["changed"] = $$createField2_0($$parsedSource["changed"]);
+        }
+        return new BulkResult(/** @type {Partial<BulkResult>} */($$parsedSource));
+    }
+}
+
 export class Collection {
     /**
      * Creates a new Collection instance.
@@ -19,128 +72,1802 @@ export class Collection {
         if (!("id" in $$source)) {
             /**
              * @member
-             * @type {string}
+             * @type {string}
+             */
+            this["id"] = "";
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("description" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["description"] = "";
+        }
+        if (!("parentCollectionId" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["parentCollectionId"] = null;
+        }
+        if (!("sortOrder" in $$source)) {
+            /**
+             * @member
+             * @type {number | null}
+             */
+            this["sortOrder"] = null;
+        }
+        if (!("favorite" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["favorite"] = false;
+        }
+        if (!("tags" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["tags"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Collection instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Collection}
+     */
+    static createFrom($$source = {}) {
+        const $$createField6_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField6_0($$parsedSource["tags"]);
+        }
+        return new Collection(/** @type {Partial<Collection>} */($$parsedSource));
+    }
+}
+
+/**
+ * CollectionCopyOptions selects what DuplicateCollection copies besides the collections and
+ * their requests.
+ */
+export class CollectionCopyOptions {
+    /**
+     * Creates a new CollectionCopyOptions instance.
+     * @param {Partial<CollectionCopyOptions>} [$$source = {}] - The source object to create the CollectionCopyOptions.
+     */
+    constructor($$source = {}) {
+        if (!("variables" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["variables"] = false;
+        }
+        if (!("latestResponse" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["latestResponse"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CollectionCopyOptions instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {CollectionCopyOptions}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new CollectionCopyOptions(/** @type {Partial<CollectionCopyOptions>} */($$parsedSource));
+    }
+}
+
+/**
+ * CollectionDefaults are the headers and auth that the requests in a collection, and in the
+ * collections below it, inherit unless they set their own.
+ */
+export class CollectionDefaults {
+    /**
+     * Creates a new CollectionDefaults instance.
+     * @param {Partial<CollectionDefaults>} [$$source = {}] - The source object to create the CollectionDefaults.
+     */
+    constructor($$source = {}) {
+        if (!("headers" in $$source)) {
+            /**
+             * @member
+             * @type {HeaderEntry[]}
+             */
+            this["headers"] = [];
+        }
+        if (!("auth" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["auth"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CollectionDefaults instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {CollectionDefaults}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("headers" in $$parsedSource) {
+            $$parsedSource["headers"] = $$createField0_0($$parsedSource["headers"]);
+        }
+        return new CollectionDefaults(/** @type {Partial<CollectionDefaults>} */($$parsedSource));
+    }
+}
+
+/**
+ * CollectionDeleteSummary reports what DeleteCollection moved to the trash or moved up a level.
+ * ResponsesTrashed counts the history of the trashed requests, which is kept until they are
+ * purged from the trash.
+ */
+export class CollectionDeleteSummary {
+    /**
+     * Creates a new CollectionDeleteSummary instance.
+     * @param {Partial<CollectionDeleteSummary>} [$$source = {}] - The source object to create the CollectionDeleteSummary.
+     */
+    constructor($$source = {}) {
+        if (!("mode" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["mode"] = "";
+        }
+        if (!("collectionsDeleted" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["collectionsDeleted"] = 0;
+        }
+        if (!("requestsDeleted" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["requestsDeleted"] = 0;
+        }
+        if (!("responsesTrashed" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["responsesTrashed"] = 0;
+        }
+        if (!("collectionsMoved" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["collectionsMoved"] = 0;
+        }
+        if (!("requestsMoved" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["requestsMoved"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CollectionDeleteSummary instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {CollectionDeleteSummary}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new CollectionDeleteSummary(/** @type {Partial<CollectionDeleteSummary>} */($$parsedSource));
+    }
+}
+
+export class Cookie {
+    /**
+     * Creates a new Cookie instance.
+     * @param {Partial<Cookie>} [$$source = {}] - The source object to create the Cookie.
+     */
+    constructor($$source = {}) {
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("value" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["value"] = "";
+        }
+        if (!("domain" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["domain"] = "";
+        }
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("expires" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time | null}
+             */
+            this["expires"] = null;
+        }
+        if (!("secure" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["secure"] = false;
+        }
+        if (!("httpOnly" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["httpOnly"] = false;
+        }
+        if (!("sameSite" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["sameSite"] = "";
+        }
+        if (!("hostOnly" in $$source)) {
+            /**
+             * HostOnly cookies were set without a Domain attribute and are only sent to that exact host.
+             * @member
+             * @type {boolean}
+             */
+            this["hostOnly"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Cookie instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Cookie}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Cookie(/** @type {Partial<Cookie>} */($$parsedSource));
+    }
+}
+
+/**
+ * DiffEntry describes one difference. For JSON bodies Path is a JSONPath-like location and
+ * Before/After hold JSON-encoded values; for text bodies Path is the line number.
+ */
+export class DiffEntry {
+    /**
+     * Creates a new DiffEntry instance.
+     * @param {Partial<DiffEntry>} [$$source = {}] - The source object to create the DiffEntry.
+     */
+    constructor($$source = {}) {
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("kind" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["kind"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["before"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["after"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DiffEntry instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DiffEntry}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new DiffEntry(/** @type {Partial<DiffEntry>} */($$parsedSource));
+    }
+}
+
+/**
+ * ExecutionLogEntry is either a recorded response or a failed attempt, as Kind tells.
+ */
+export class ExecutionLogEntry {
+    /**
+     * Creates a new ExecutionLogEntry instance.
+     * @param {Partial<ExecutionLogEntry>} [$$source = {}] - The source object to create the ExecutionLogEntry.
+     */
+    constructor($$source = {}) {
+        if (!("kind" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["kind"] = "";
+        }
+        if (!("id" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["id"] = 0;
+        }
+        if (!("requestId" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["requestId"] = 0;
+        }
+        if (!("requestName" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["requestName"] = null;
+        }
+        if (!("collectionId" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["collectionId"] = null;
+        }
+        if (!("collectionName" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["collectionName"] = null;
+        }
+        if (!("method" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["method"] = "";
+        }
+        if (!("url" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["url"] = "";
+        }
+        if (!("host" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["host"] = "";
+        }
+        if (!("environment" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["environment"] = "";
+        }
+        if (!("statusCode" in $$source)) {
+            /**
+             * @member
+             * @type {number | null}
+             */
+            this["statusCode"] = null;
+        }
+        if (!("runtimeMS" in $$source)) {
+            /**
+             * @member
+             * @type {number | null}
+             */
+            this["runtimeMS"] = null;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["error"] = "";
+        }
+        if (!("createdAt" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time | null}
+             */
+            this["createdAt"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ExecutionLogEntry instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ExecutionLogEntry}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ExecutionLogEntry(/** @type {Partial<ExecutionLogEntry>} */($$parsedSource));
+    }
+}
+
+/**
+ * ExecutionLogFilter narrows the timeline. Empty fields do not filter. StatusClasses holds
+ * values such as "2xx" or "5xx", and "error" for failed attempts. A collection includes its
+ * sub-collections. Page is 1-based.
+ */
+export class ExecutionLogFilter {
+    /**
+     * Creates a new ExecutionLogFilter instance.
+     * @param {Partial<ExecutionLogFilter>} [$$source = {}] - The source object to create the ExecutionLogFilter.
+     */
+    constructor($$source = {}) {
+        if (!("from" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time | null}
+             */
+            this["from"] = null;
+        }
+        if (!("to" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time | null}
+             */
+            this["to"] = null;
+        }
+        if (!("statusClasses" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["statusClasses"] = [];
+        }
+        if (!("host" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["host"] = "";
+        }
+        if (!("collectionId" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["collectionId"] = "";
+        }
+        if (!("environment" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["environment"] = "";
+        }
+        if (!("page" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["page"] = 0;
+        }
+        if (!("pageSize" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["pageSize"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ExecutionLogFilter instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ExecutionLogFilter}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("statusClasses" in $$parsedSource) {
+            $$parsedSource["statusClasses"] = $$createField2_0($$parsedSource["statusClasses"]);
+        }
+        return new ExecutionLogFilter(/** @type {Partial<ExecutionLogFilter>} */($$parsedSource));
+    }
+}
+
+export class ExecutionLogPage {
+    /**
+     * Creates a new ExecutionLogPage instance.
+     * @param {Partial<ExecutionLogPage>} [$$source = {}] - The source object to create the ExecutionLogPage.
+     */
+    constructor($$source = {}) {
+        if (!("entries" in $$source)) {
+            /**
+             * @member
+             * @type {ExecutionLogEntry[]}
+             */
+            this["entries"] = [];
+        }
+        if (!("total" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["total"] = 0;
+        }
+        if (!("page" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["page"] = 0;
+        }
+        if (!("pageSize" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["pageSize"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ExecutionLogPage instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ExecutionLogPage}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType5;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("entries" in $$parsedSource) {
+            $$parsedSource["entries"] = $$createField0_0($$parsedSource["entries"]);
+        }
+        return new ExecutionLogPage(/** @type {Partial<ExecutionLogPage>} */($$parsedSource));
+    }
+}
+
+export class Favorites {
+    /**
+     * Creates a new Favorites instance.
+     * @param {Partial<Favorites>} [$$source = {}] - The source object to create the Favorites.
+     */
+    constructor($$source = {}) {
+        if (!("requests" in $$source)) {
+            /**
+             * @member
+             * @type {Request[]}
+             */
+            this["requests"] = [];
+        }
+        if (!("collections" in $$source)) {
+            /**
+             * @member
+             * @type {Collection[]}
+             */
+            this["collections"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Favorites instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Favorites}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType7;
+        const $$createField1_0 = $$createType9;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("requests" in $$parsedSource) {
+            $$parsedSource["requests"] = $$createField0_0($$parsedSource["requests"]);
+        }
+        if ("collections" in $$parsedSource) {
+            $$parsedSource["collections"] = $$createField1_0($$parsedSource["collections"]);
+        }
+        return new Favorites(/** @type {Partial<Favorites>} */($$parsedSource));
+    }
+}
+
+/**
+ * HeaderEntry is one header of a request. Enabled is a pointer so entries saved before the flag
+ * existed count as enabled; Disabled is Postman's spelling and is honoured as well.
+ */
+export class HeaderEntry {
+    /**
+     * Creates a new HeaderEntry instance.
+     * @param {Partial<HeaderEntry>} [$$source = {}] - The source object to create the HeaderEntry.
+     */
+    constructor($$source = {}) {
+        if (!("key" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["key"] = "";
+        }
+        if (!("value" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["value"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {boolean | null | undefined}
+             */
+            this["enabled"] = null;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["disabled"] = false;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["description"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new HeaderEntry instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {HeaderEntry}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new HeaderEntry(/** @type {Partial<HeaderEntry>} */($$parsedSource));
+    }
+}
+
+/**
+ * InheritedDefaults is what a request in a collection inherits. AuthCollectionID names the
+ * collection the auth comes from and is empty when no collection sets one.
+ */
+export class InheritedDefaults {
+    /**
+     * Creates a new InheritedDefaults instance.
+     * @param {Partial<InheritedDefaults>} [$$source = {}] - The source object to create the InheritedDefaults.
+     */
+    constructor($$source = {}) {
+        if (!("headers" in $$source)) {
+            /**
+             * @member
+             * @type {InheritedHeader[]}
+             */
+            this["headers"] = [];
+        }
+        if (!("auth" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["auth"] = "";
+        }
+        if (!("authCollectionId" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["authCollectionId"] = "";
+        }
+        if (!("authCollectionName" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["authCollectionName"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new InheritedDefaults instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {InheritedDefaults}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType11;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("headers" in $$parsedSource) {
+            $$parsedSource["headers"] = $$createField0_0($$parsedSource["headers"]);
+        }
+        return new InheritedDefaults(/** @type {Partial<InheritedDefaults>} */($$parsedSource));
+    }
+}
+
+export class InheritedHeader {
+    /**
+     * Creates a new InheritedHeader instance.
+     * @param {Partial<InheritedHeader>} [$$source = {}] - The source object to create the InheritedHeader.
+     */
+    constructor($$source = {}) {
+        if (!("key" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["key"] = "";
+        }
+        if (!("value" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["value"] = "";
+        }
+        if (!("collectionId" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["collectionId"] = "";
+        }
+        if (!("collectionName" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["collectionName"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new InheritedHeader instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {InheritedHeader}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new InheritedHeader(/** @type {Partial<InheritedHeader>} */($$parsedSource));
+    }
+}
+
+export class JournalOperation {
+    /**
+     * Creates a new JournalOperation instance.
+     * @param {Partial<JournalOperation>} [$$source = {}] - The source object to create the JournalOperation.
+     */
+    constructor($$source = {}) {
+        if (!("id" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["id"] = 0;
+        }
+        if (!("description" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["description"] = "";
+        }
+        if (!("createdAt" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["createdAt"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new JournalOperation instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {JournalOperation}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new JournalOperation(/** @type {Partial<JournalOperation>} */($$parsedSource));
+    }
+}
+
+/**
+ * JournalState names the operations Undo and Redo would apply next; either is nil when there is
+ * nothing to apply.
+ */
+export class JournalState {
+    /**
+     * Creates a new JournalState instance.
+     * @param {Partial<JournalState>} [$$source = {}] - The source object to create the JournalState.
+     */
+    constructor($$source = {}) {
+        if (!("undo" in $$source)) {
+            /**
+             * @member
+             * @type {JournalOperation | null}
+             */
+            this["undo"] = null;
+        }
+        if (!("redo" in $$source)) {
+            /**
+             * @member
+             * @type {JournalOperation | null}
+             */
+            this["redo"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new JournalState instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {JournalState}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType13;
+        const $$createField1_0 = $$createType13;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("undo" in $$parsedSource) {
+            $$parsedSource["undo"] = $$createField0_0($$parsedSource["undo"]);
+        }
+        if ("redo" in $$parsedSource) {
+            $$parsedSource["redo"] = $$createField1_0($$parsedSource["redo"]);
+        }
+        return new JournalState(/** @type {Partial<JournalState>} */($$parsedSource));
+    }
+}
+
+export class Keybind {
+    /**
+     * Creates a new Keybind instance.
+     * @param {Partial<Keybind>} [$$source = {}] - The source object to create the Keybind.
+     */
+    constructor($$source = {}) {
+        if (!("command" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["command"] = null;
+        }
+        if (!("bind" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["bind"] = null;
+        }
+        if (!("prettyName" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["prettyName"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Keybind instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Keybind}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Keybind(/** @type {Partial<Keybind>} */($$parsedSource));
+    }
+}
+
+export class PathVariable {
+    /**
+     * Creates a new PathVariable instance.
+     * @param {Partial<PathVariable>} [$$source = {}] - The source object to create the PathVariable.
+     */
+    constructor($$source = {}) {
+        if (!("key" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["key"] = "";
+        }
+        if (!("value" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["value"] = "";
+        }
+        if (!("description" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["description"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PathVariable instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {PathVariable}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PathVariable(/** @type {Partial<PathVariable>} */($$parsedSource));
+    }
+}
+
+export class QueryParam {
+    /**
+     * Creates a new QueryParam instance.
+     * @param {Partial<QueryParam>} [$$source = {}] - The source object to create the QueryParam.
+     */
+    constructor($$source = {}) {
+        if (!("key" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["key"] = "";
+        }
+        if (!("value" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["value"] = "";
+        }
+        if (!("enabled" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["enabled"] = false;
+        }
+        if (!("description" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["description"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new QueryParam instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {QueryParam}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new QueryParam(/** @type {Partial<QueryParam>} */($$parsedSource));
+    }
+}
+
+export class Request {
+    /**
+     * Creates a new Request instance.
+     * @param {Partial<Request>} [$$source = {}] - The source object to create the Request.
+     */
+    constructor($$source = {}) {
+        if (!("id" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["id"] = 0;
+        }
+        if (!("collectionId" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["collectionId"] = null;
+        }
+        if (!("collectionName" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["collectionName"] = null;
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["name"] = null;
+        }
+        if (!("description" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["description"] = null;
+        }
+        if (!("method" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["method"] = null;
+        }
+        if (!("url" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["url"] = null;
+        }
+        if (!("headers" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["headers"] = null;
+        }
+        if (!("body" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["body"] = null;
+        }
+        if (!("bodyType" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["bodyType"] = null;
+        }
+        if (!("bodyFormat" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["bodyFormat"] = null;
+        }
+        if (!("auth" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["auth"] = null;
+        }
+        if (!("sortOrder" in $$source)) {
+            /**
+             * @member
+             * @type {number | null}
+             */
+            this["sortOrder"] = null;
+        }
+        if (!("favorite" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["favorite"] = false;
+        }
+        if (!("tags" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["tags"] = [];
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {Response | null | undefined}
+             */
+            this["response"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Request instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Request}
+     */
+    static createFrom($$source = {}) {
+        const $$createField14_0 = $$createType1;
+        const $$createField15_0 = $$createType15;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField14_0($$parsedSource["tags"]);
+        }
+        if ("response" in $$parsedSource) {
+            $$parsedSource["response"] = $$createField15_0($$parsedSource["response"]);
+        }
+        return new Request(/** @type {Partial<Request>} */($$parsedSource));
+    }
+}
+
+export class RequestScripts {
+    /**
+     * Creates a new RequestScripts instance.
+     * @param {Partial<RequestScripts>} [$$source = {}] - The source object to create the RequestScripts.
+     */
+    constructor($$source = {}) {
+        if (!("preRequest" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["preRequest"] = "";
+        }
+        if (!("postResponse" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["postResponse"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RequestScripts instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RequestScripts}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RequestScripts(/** @type {Partial<RequestScripts>} */($$parsedSource));
+    }
+}
+
+/**
+ * RequestSnapshot is the fully resolved request as it was sent: placeholders, path variables,
+ * params, scripts and auth are all applied. Cookies added from the jar are not included.
+ */
+export class RequestSnapshot {
+    /**
+     * Creates a new RequestSnapshot instance.
+     * @param {Partial<RequestSnapshot>} [$$source = {}] - The source object to create the RequestSnapshot.
+     */
+    constructor($$source = {}) {
+        if (!("method" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["method"] = "";
+        }
+        if (!("url" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["url"] = "";
+        }
+        if (!("headers" in $$source)) {
+            /**
+             * @member
+             * @type {http$0.Header}
+             */
+            this["headers"] = (/** @type {http$0.Header} */({}));
+        }
+        if (!("body" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["body"] = "";
+        }
+        if (!("environment" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["environment"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * MaskedSecrets names the secret variables, and the expressions derived from them, whose
+             * values are masked in this copy.
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["maskedSecrets"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RequestSnapshot instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RequestSnapshot}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType16;
+        const $$createField5_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("headers" in $$parsedSource) {
+            $$parsedSource["headers"] = $$createField2_0($$parsedSource["headers"]);
+        }
+        if ("maskedSecrets" in $$parsedSource) {
+            $$parsedSource["maskedSecrets"] = $$createField5_0($$parsedSource["maskedSecrets"]);
+        }
+        return new RequestSnapshot(/** @type {Partial<RequestSnapshot>} */($$parsedSource));
+    }
+}
+
+export class ResolvedVariable {
+    /**
+     * Creates a new ResolvedVariable instance.
+     * @param {Partial<ResolvedVariable>} [$$source = {}] - The source object to create the ResolvedVariable.
+     */
+    constructor($$source = {}) {
+        if (!("key" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["key"] = "";
+        }
+        if (!("value" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["value"] = "";
+        }
+        if (!("scope" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["scope"] = "";
+        }
+        if (!("source" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["source"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ResolvedVariable instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ResolvedVariable}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ResolvedVariable(/** @type {Partial<ResolvedVariable>} */($$parsedSource));
+    }
+}
+
+export class Response {
+    /**
+     * Creates a new Response instance.
+     * @param {Partial<Response>} [$$source = {}] - The source object to create the Response.
+     */
+    constructor($$source = {}) {
+        if (!("id" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["id"] = 0;
+        }
+        if (!("statusCode" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["statusCode"] = 0;
+        }
+        if (!("headers" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["headers"] = "";
+        }
+        if (!("body" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["body"] = "";
+        }
+        if (!("runtimeMS" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["runtimeMS"] = 0;
+        }
+        if (!("requestID" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["requestID"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {time$0.Time | null | undefined}
+             */
+            this["createdAt"] = null;
+        }
+        if (!("pinned" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["pinned"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Response instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Response}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Response(/** @type {Partial<Response>} */($$parsedSource));
+    }
+}
+
+export class ResponseDiff {
+    /**
+     * Creates a new ResponseDiff instance.
+     * @param {Partial<ResponseDiff>} [$$source = {}] - The source object to create the ResponseDiff.
+     */
+    constructor($$source = {}) {
+        if (!("statusBefore" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["statusBefore"] = 0;
+        }
+        if (!("statusAfter" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["statusAfter"] = 0;
+        }
+        if (!("statusChanged" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["statusChanged"] = false;
+        }
+        if (!("headers" in $$source)) {
+            /**
+             * @member
+             * @type {DiffEntry[]}
+             */
+            this["headers"] = [];
+        }
+        if (!("bodyFormat" in $$source)) {
+            /**
+             * BodyFormat is "json" when both bodies parsed as JSON and "text" otherwise.
+             * @member
+             * @type {string}
+             */
+            this["bodyFormat"] = "";
+        }
+        if (!("body" in $$source)) {
+            /**
+             * @member
+             * @type {DiffEntry[]}
+             */
+            this["body"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ResponseDiff instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ResponseDiff}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType19;
+        const $$createField5_0 = $$createType19;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("headers" in $$parsedSource) {
+            $$parsedSource["headers"] = $$createField3_0($$parsedSource["headers"]);
+        }
+        if ("body" in $$parsedSource) {
+            $$parsedSource["body"] = $$createField5_0($$parsedSource["body"]);
+        }
+        return new ResponseDiff(/** @type {Partial<ResponseDiff>} */($$parsedSource));
+    }
+}
+
+/**
+ * RetentionPolicy overrides the global response history limits for a collection and its
+ * sub-collections. A nil field inherits from the parent collection or the global setting;
+ * zero disables that limit.
+ */
+export class RetentionPolicy {
+    /**
+     * Creates a new RetentionPolicy instance.
+     * @param {Partial<RetentionPolicy>} [$$source = {}] - The source object to create the RetentionPolicy.
+     */
+    constructor($$source = {}) {
+        if (!("maxEntries" in $$source)) {
+            /**
+             * @member
+             * @type {number | null}
+             */
+            this["maxEntries"] = null;
+        }
+        if (!("maxAgeDays" in $$source)) {
+            /**
+             * @member
+             * @type {number | null}
+             */
+            this["maxAgeDays"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RetentionPolicy instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RetentionPolicy}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RetentionPolicy(/** @type {Partial<RetentionPolicy>} */($$parsedSource));
+    }
+}
+
+export class RetentionResult {
+    /**
+     * Creates a new RetentionResult instance.
+     * @param {Partial<RetentionResult>} [$$source = {}] - The source object to create the RetentionResult.
+     */
+    constructor($$source = {}) {
+        if (!("deleted" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["deleted"] = 0;
+        }
+        if (!("freedBytes" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["freedBytes"] = 0;
+        }
+        if (!("vacuumed" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["vacuumed"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RetentionResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RetentionResult}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RetentionResult(/** @type {Partial<RetentionResult>} */($$parsedSource));
+    }
+}
+
+export class SearchResult {
+    /**
+     * Creates a new SearchResult instance.
+     * @param {Partial<SearchResult>} [$$source = {}] - The source object to create the SearchResult.
+     */
+    constructor($$source = {}) {
+        if (!("kind" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["kind"] = "";
+        }
+        if (!("requestId" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["requestId"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {number | null | undefined}
+             */
+            this["responseId"] = null;
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["name"] = null;
+        }
+        if (!("method" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["method"] = null;
+        }
+        if (!("url" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
              */
-            this["id"] = "";
+            this["url"] = null;
         }
-        if (!("name" in $$source)) {
+        if (!("collectionId" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["collectionId"] = null;
+        }
+        if (!("collectionName" in $$source)) {
+            /**
+             * @member
+             * @type {string | null}
+             */
+            this["collectionName"] = null;
+        }
+        if (!("snippet" in $$source)) {
             /**
              * @member
              * @type {string}
              */
-            this["name"] = "";
+            this["snippet"] = "";
         }
-        if (!("description" in $$source)) {
+        if (!("rank" in $$source)) {
+            /**
+             * Rank orders results, lower being better. When responses are included it is relative to
+             * the best match of the same kind.
+             * @member
+             * @type {number}
+             */
+            this["rank"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SearchResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {SearchResult}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SearchResult(/** @type {Partial<SearchResult>} */($$parsedSource));
+    }
+}
+
+export class SecretsStatus {
+    /**
+     * Creates a new SecretsStatus instance.
+     * @param {Partial<SecretsStatus>} [$$source = {}] - The source object to create the SecretsStatus.
+     */
+    constructor($$source = {}) {
+        if (!("mode" in $$source)) {
             /**
              * @member
              * @type {string}
              */
-            this["description"] = "";
+            this["mode"] = "";
         }
-        if (!("parentCollectionId" in $$source)) {
+        if (!("unlocked" in $$source)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {boolean}
              */
-            this["parentCollectionId"] = null;
+            this["unlocked"] = false;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new Collection instance from a string or object.
+     * Creates a new SecretsStatus instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {Collection}
+     * @returns {SecretsStatus}
      */
     static createFrom($$source = {}) {
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new Collection(/** @type {Partial<Collection>} */($$parsedSource));
+        return new SecretsStatus(/** @type {Partial<SecretsStatus>} */($$parsedSource));
     }
 }
 
-export class Keybind {
+/**
+ * Tag is a label shared by requests and collections. A request also carries the tags of the
+ * collections it is in when listing or searching by tag.
+ */
+export class Tag {
     /**
-     * Creates a new Keybind instance.
-     * @param {Partial<Keybind>} [$$source = {}] - The source object to create the Keybind.
+     * Creates a new Tag instance.
+     * @param {Partial<Tag>} [$$source = {}] - The source object to create the Tag.
      */
     constructor($$source = {}) {
-        if (!("command" in $$source)) {
+        if (!("name" in $$source)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {string}
              */
-            this["command"] = null;
+            this["name"] = "";
         }
-        if (!("bind" in $$source)) {
+        if (!("color" in $$source)) {
             /**
              * @member
              * @type {string | null}
              */
-            this["bind"] = null;
+            this["color"] = null;
         }
-        if (!("prettyName" in $$source)) {
+        if (!("requestCount" in $$source)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {number}
              */
-            this["prettyName"] = null;
+            this["requestCount"] = 0;
+        }
+        if (!("collectionCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["collectionCount"] = 0;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new Keybind instance from a string or object.
+     * Creates a new Tag instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {Keybind}
+     * @returns {Tag}
      */
     static createFrom($$source = {}) {
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new Keybind(/** @type {Partial<Keybind>} */($$parsedSource));
+        return new Tag(/** @type {Partial<Tag>} */($$parsedSource));
     }
 }
 
-export class Request {
+/**
+ * TrashItem is an entry in the trash. RequestID is set for requests and CollectionID for
+ * collections; Location names the collection the item was in, if it still exists.
+ */
+export class TrashItem {
     /**
-     * Creates a new Request instance.
-     * @param {Partial<Request>} [$$source = {}] - The source object to create the Request.
+     * Creates a new TrashItem instance.
+     * @param {Partial<TrashItem>} [$$source = {}] - The source object to create the TrashItem.
      */
     constructor($$source = {}) {
-        if (!("id" in $$source)) {
+        if (!("kind" in $$source)) {
             /**
              * @member
-             * @type {number}
+             * @type {string}
              */
-            this["id"] = 0;
+            this["kind"] = "";
         }
-        if (!("collectionId" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {number | undefined}
              */
-            this["collectionId"] = null;
+            this["requestId"] = 0;
         }
-        if (!("collectionName" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {string | undefined}
              */
-            this["collectionName"] = null;
+            this["collectionId"] = "";
         }
         if (!("name" in $$source)) {
             /**
              * @member
-             * @type {string | null}
-             */
-            this["name"] = null;
-        }
-        if (!("description" in $$source)) {
-            /**
-             * @member
-             * @type {string | null}
+             * @type {string}
              */
-            this["description"] = null;
+            this["name"] = "";
         }
         if (!("method" in $$source)) {
             /**
@@ -156,193 +1883,305 @@ export class Request {
              */
             this["url"] = null;
         }
-        if (!("headers" in $$source)) {
-            /**
-             * @member
-             * @type {string | null}
-             */
-            this["headers"] = null;
-        }
-        if (!("body" in $$source)) {
+        if (!("location" in $$source)) {
             /**
              * @member
              * @type {string | null}
              */
-            this["body"] = null;
+            this["location"] = null;
         }
-        if (!("bodyType" in $$source)) {
+        if (!("requestCount" in $$source)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {number}
              */
-            this["bodyType"] = null;
+            this["requestCount"] = 0;
         }
-        if (!("bodyFormat" in $$source)) {
+        if (!("deletedAt" in $$source)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {time$0.Time}
              */
-            this["bodyFormat"] = null;
+            this["deletedAt"] = null;
         }
-        if (!("auth" in $$source)) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrashItem instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {TrashItem}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TrashItem(/** @type {Partial<TrashItem>} */($$parsedSource));
+    }
+}
+
+export class TrashPurgeResult {
+    /**
+     * Creates a new TrashPurgeResult instance.
+     * @param {Partial<TrashPurgeResult>} [$$source = {}] - The source object to create the TrashPurgeResult.
+     */
+    constructor($$source = {}) {
+        if (!("requests" in $$source)) {
             /**
              * @member
-             * @type {string | null}
+             * @type {number}
              */
-            this["auth"] = null;
+            this["requests"] = 0;
         }
-        if (!("sortOrder" in $$source)) {
+        if (!("collections" in $$source)) {
             /**
              * @member
-             * @type {number | null}
+             * @type {number}
              */
-            this["sortOrder"] = null;
+            this["collections"] = 0;
         }
-        if (/** @type {any} */(false)) {
+        if (!("responses" in $$source)) {
             /**
              * @member
-             * @type {Response | null | undefined}
+             * @type {number}
              */
-            this["response"] = null;
+            this["responses"] = 0;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new Request instance from a string or object.
+     * Creates a new TrashPurgeResult instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {Request}
+     * @returns {TrashPurgeResult}
      */
     static createFrom($$source = {}) {
-        const $$createField13_0 = $$createType1;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("response" in $$parsedSource) {
-            $$parsedSource["response"] = $$createField13_0($$parsedSource["response"]);
-        }
-        return new Request(/** @type {Partial<Request>} */($$parsedSource));
+        return new TrashPurgeResult(/** @type {Partial<TrashPurgeResult>} */($$parsedSource));
     }
 }
 
-export class Response {
+export class UserSettings {
     /**
-     * Creates a new Response instance.
-     * @param {Partial<Response>} [$$source = {}] - The source object to create the Response.
+     * Creates a new UserSettings instance.
+     * @param {Partial<UserSettings>} [$$source = {}] - The source object to create the UserSettings.
      */
     constructor($$source = {}) {
-        if (!("id" in $$source)) {
+        if (!("theme" in $$source)) {
             /**
              * @member
-             * @type {number}
+             * @type {string}
              */
-            this["id"] = 0;
+            this["theme"] = "";
         }
-        if (!("statusCode" in $$source)) {
+        if (!("defaultEnv" in $$source)) {
             /**
              * @member
-             * @type {number}
+             * @type {string}
              */
-            this["statusCode"] = 0;
+            this["defaultEnv"] = "";
         }
-        if (!("headers" in $$source)) {
+        if (!("enableAnimations" in $$source)) {
             /**
              * @member
-             * @type {string}
+             * @type {boolean}
              */
-            this["headers"] = "";
+            this["enableAnimations"] = false;
         }
-        if (!("body" in $$source)) {
+        if (!("responseHistoryTTL" in $$source)) {
             /**
              * @member
-             * @type {string}
+             * @type {number}
              */
-            this["body"] = "";
+            this["responseHistoryTTL"] = 0;
         }
-        if (!("runtimeMS" in $$source)) {
+        if (!("responseHistoryMaxAgeDays" in $$source)) {
             /**
+             * ResponseHistoryMaxAgeDays and ResponseHistoryMaxSizeMB are disabled when zero.
              * @member
              * @type {number}
              */
-            this["runtimeMS"] = 0;
+            this["responseHistoryMaxAgeDays"] = 0;
         }
-        if (!("requestID" in $$source)) {
+        if (!("responseHistoryMaxSizeMB" in $$source)) {
             /**
              * @member
              * @type {number}
              */
-            this["requestID"] = 0;
+            this["responseHistoryMaxSizeMB"] = 0;
         }
-        if (/** @type {any} */(false)) {
+        if (!("trashRetentionDays" in $$source)) {
             /**
+             * TrashRetentionDays keeps deleted items forever when zero.
              * @member
-             * @type {time$0.Time | null | undefined}
+             * @type {number}
              */
-            this["createdAt"] = null;
+            this["trashRetentionDays"] = 0;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new Response instance from a string or object.
+     * Creates a new UserSettings instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {Response}
+     * @returns {UserSettings}
      */
     static createFrom($$source = {}) {
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new Response(/** @type {Partial<Response>} */($$parsedSource));
+        return new UserSettings(/** @type {Partial<UserSettings>} */($$parsedSource));
     }
 }
 
-export class UserSettings {
+export class Variable {
     /**
-     * Creates a new UserSettings instance.
-     * @param {Partial<UserSettings>} [$$source = {}] - The source object to create the UserSettings.
+     * Creates a new Variable instance.
+     * @param {Partial<Variable>} [$$source = {}] - The source object to create the Variable.
      */
     constructor($$source = {}) {
-        if (!("theme" in $$source)) {
+        if (!("key" in $$source)) {
             /**
              * @member
              * @type {string}
              */
-            this["theme"] = "";
+            this["key"] = "";
         }
-        if (!("defaultEnv" in $$source)) {
+        if (!("value" in $$source)) {
             /**
              * @member
              * @type {string}
              */
-            this["defaultEnv"] = "";
+            this["value"] = "";
         }
-        if (!("enableAnimations" in $$source)) {
+        if (!("enabled" in $$source)) {
             /**
              * @member
              * @type {boolean}
              */
-            this["enableAnimations"] = false;
+            this["enabled"] = false;
         }
-        if (!("responseHistoryTTL" in $$source)) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Variable instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Variable}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Variable(/** @type {Partial<Variable>} */($$parsedSource));
+    }
+}
+
+export class WorkspaceImportSummary {
+    /**
+     * Creates a new WorkspaceImportSummary instance.
+     * @param {Partial<WorkspaceImportSummary>} [$$source = {}] - The source object to create the WorkspaceImportSummary.
+     */
+    constructor($$source = {}) {
+        if (!("mode" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["mode"] = "";
+        }
+        if (!("imported" in $$source)) {
+            /**
+             * @member
+             * @type {{ [_: string]: number }}
+             */
+            this["imported"] = {};
+        }
+        if (!("skipped" in $$source)) {
+            /**
+             * @member
+             * @type {{ [_: string]: number }}
+             */
+            this["skipped"] = {};
+        }
+        if (!("envImported" in $$source)) {
             /**
              * @member
              * @type {number}
              */
-            this["responseHistoryTTL"] = 0;
+            this["envImported"] = 0;
+        }
+        if (!("envSkipped" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["envSkipped"] = 0;
+        }
+        if (!("remappedIds" in $$source)) {
+            /**
+             * @member
+             * @type {{ [_: `${number}`]: number }}
+             */
+            this["remappedIds"] = {};
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {time$0.Time | null | undefined}
+             */
+            this["exportedAt"] = null;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new UserSettings instance from a string or object.
+     * Creates a new WorkspaceImportSummary instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {UserSettings}
+     * @returns {WorkspaceImportSummary}
      */
     static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType20;
+        const $$createField2_0 = $$createType20;
+        const $$createField5_0 = $$createType21;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new UserSettings(/** @type {Partial<UserSettings>} */($$parsedSource));
+        if ("imported" in $$parsedSource) {
+            $$parsedSource["imported"] = $$createField1_0($$parsedSource["imported"]);
+        }
+        if ("skipped" in $$parsedSource) {
+            $$parsedSource["skipped"] = $$createField2_0($$parsedSource["skipped"]);
+        }
+        if ("remappedIds" in $$parsedSource) {
+            $$parsedSource["remappedIds"] = $$createField5_0($$parsedSource["remappedIds"]);
+        }
+        return new WorkspaceImportSummary(/** @type {Partial<WorkspaceImportSummary>} */($$parsedSource));
     }
 }
 
 // Private type creation functions
-const $$createType0 = Response.createFrom;
-const $$createType1 = $Create.Nullable($$createType0);
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = $Create.Array($Create.Any);
+const $$createType2 = HeaderEntry.createFrom;
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = ExecutionLogEntry.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = Request.createFrom;
+const $$createType7 = $Create.Array($$createType6);
+const $$createType8 = Collection.createFrom;
+const $$createType9 = $Create.Array($$createType8);
+const $$createType10 = InheritedHeader.createFrom;
+const $$createType11 = $Create.Array($$createType10);
+const $$createType12 = JournalOperation.createFrom;
+const $$createType13 = $Create.Nullable($$createType12);
+const $$createType14 = Response.createFrom;
+const $$createType15 = $Create.Nullable($$createType14);
+var $$createType16 = /** @type {(...args: any[]) => any} */(function $$initCreateType16(...args) {
+    if ($$createType16 === $$initCreateType16) {
+        $$createType16 = $$createType17;
+    }
+    return $$createType16(...args);
+});
+const $$createType17 = $Create.Map($Create.Any, $$createType1);
+const $$createType18 = DiffEntry.createFrom;
+const $$createType19 = $Create.Array($$createType18);
+const $$createType20 = $Create.Map($Create.Any, $Create.Any);
+const $$createType21 = $Create.Map($Create.Any, $Create.Any);
@@ -14,6 +14,117 @@ import * as json$0 from "../../../encoding/json/models.js";
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
+/**
+ * @param {string} collectionID
+ * @param {string} tag
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function AddCollectionTag(collectionID, tag) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1109481841, collectionID, tag));
+    return $resultPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @param {string} tag
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function AddRequestTag(requestID, tag) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2409907404, requestID, tag));
+    return $resultPromise;
+}
+
+/**
+ * BulkDeleteRequests moves requests to the trash. Either all of them go or none do.
+ * @param {number[]} requestIDs
+ * @returns {Promise<$models.BulkResult> & { cancel(): void }}
+ */
+export function BulkDeleteRequests(requestIDs) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1257837145, requestIDs));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * BulkMoveRequests moves requests to a collection, or out of any collection when collectionID is
+ * nil, appending them in the order given. Either all of them move or none do.
+ * @param {number[]} requestIDs
+ * @param {string | null} collectionID
+ * @returns {Promise<$models.BulkResult> & { cancel(): void }}
+ */
+export function BulkMoveRequests(requestIDs, collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1115723727, requestIDs, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * BulkReplaceInURLs replaces every occurrence of find in the URLs of the requests in a collection
+ * and the collections below it, or of all requests when collectionID is nil. The replacement is
+ * literal, so a hardcoded host can be swapped for a placeholder such as {{baseUrl}}.
+ * @param {string | null} collectionID
+ * @param {string} find
+ * @param {string} replace
+ * @returns {Promise<$models.BulkResult> & { cancel(): void }}
+ */
+export function BulkReplaceInURLs(collectionID, find, replace) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1620896821, collectionID, find, replace));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * BulkSetAuth sets the auth of every request in a collection and the collections below it. An
+ * empty auth makes them inherit the collection auth again; authNone sends none.
+ * @param {string} collectionID
+ * @param {string} auth
+ * @returns {Promise<$models.BulkResult> & { cancel(): void }}
+ */
+export function BulkSetAuth(collectionID, auth) {
+    let $resultPromise = /** @type {any} */($Call.ByID(689057658, collectionID, auth));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * BulkSetHeader sets a header on every request in a collection, and with recursive set in its
+ * sub-collections too. An existing header of the same name, compared case-insensitively, gets
+ * the new value and is enabled; otherwise the header is added.
+ * @param {string} collectionID
+ * @param {string} key
+ * @param {string} value
+ * @param {boolean} recursive
+ * @returns {Promise<$models.BulkResult> & { cancel(): void }}
+ */
+export function BulkSetHeader(collectionID, key, value, recursive) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3405906713, collectionID, key, value, recursive));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function ClearRuntimeVariables() {
+    let $resultPromise = /** @type {any} */($Call.ByID(1003378364));
+    return $resultPromise;
+}
+
 /**
  * @param {string} name
  * @param {string} description
@@ -23,22 +134,31 @@ import * as $models from "./models.js";
 export function CreateCollection(name, description, parentId) {
     let $resultPromise = /** @type {any} */($Call.ByID(1554286192, name, description, parentId));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType0($result);
+        return $$createType1($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
 }
 
 /**
+ * DeleteCollection moves a collection to the trash in one transaction. In
+ * CollectionDeleteCascade mode its sub-collections and requests go with it; in
+ * CollectionDeleteReparent mode they move up to the collection's parent first.
  * @param {string} collectionId
- * @returns {Promise<void> & { cancel(): void }}
+ * @param {string} mode
+ * @returns {Promise<$models.CollectionDeleteSummary> & { cancel(): void }}
  */
-export function DeleteCollection(collectionId) {
-    let $resultPromise = /** @type {any} */($Call.ByID(3206062955, collectionId));
-    return $resultPromise;
+export function DeleteCollection(collectionId, mode) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3206062955, collectionId, mode));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType2($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
 }
 
 /**
+ * DeleteRequest moves a request to the trash; TrashService restores or purges it.
  * @param {number} id
  * @returns {Promise<void> & { cancel(): void }}
  */
@@ -47,6 +167,49 @@ export function DeleteRequest(id) {
     return $resultPromise;
 }
 
+/**
+ * @param {string} name
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function DeleteTag(name) {
+    let $resultPromise = /** @type {any} */($Call.ByID(4135512657, name));
+    return $resultPromise;
+}
+
+/**
+ * DiffResponses compares two recorded responses, a being the older one.
+ * @param {number} a
+ * @param {number} b
+ * @returns {Promise<$models.ResponseDiff> & { cancel(): void }}
+ */
+export function DiffResponses(a, b) {
+    let $resultPromise = /** @type {any} */($Call.ByID(7935213, a, b));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType3($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * DuplicateCollection deep-copies a collection, its sub-collections and their requests below
+ * newParentID, or to the top level when it is empty. Copies get new ids and keep the names and
+ * sort order of the originals; the copied root is named "<name> (Copy)" and placed after its new
+ * siblings. Items in the trash are left out.
+ * @param {string} collectionID
+ * @param {string | null} newParentID
+ * @param {$models.CollectionCopyOptions} options
+ * @returns {Promise<$models.Collection> & { cancel(): void }}
+ */
+export function DuplicateCollection(collectionID, newParentID, options) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1973012877, collectionID, newParentID, options));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType1($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
 /**
  * @param {number} requestID
  * @returns {Promise<$models.Request> & { cancel(): void }}
@@ -54,7 +217,7 @@ export function DeleteRequest(id) {
 export function DuplicateRequest(requestID) {
     let $resultPromise = /** @type {any} */($Call.ByID(456732318, requestID));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType1($result);
+        return $$createType4($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
@@ -69,33 +232,146 @@ export function DuplicateRequest(requestID) {
  * @param {string} bodyType
  * @param {string} bodyFormat
  * @param {string} auth
+ * @param {string} environment
  * @returns {Promise<json$0.RawMessage> & { cancel(): void }}
  */
-export function ExecuteRequest(requestID, method, requestUrl, headersIn, body, bodyType, bodyFormat, auth) {
-    let $resultPromise = /** @type {any} */($Call.ByID(1005662952, requestID, method, requestUrl, headersIn, body, bodyType, bodyFormat, auth));
+export function ExecuteRequest(requestID, method, requestUrl, headersIn, body, bodyType, bodyFormat, auth, environment) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1005662952, requestID, method, requestUrl, headersIn, body, bodyType, bodyFormat, auth, environment));
     return $resultPromise;
 }
 
+/**
+ * FullTextSearch ranks requests, and response bodies when includeResponses is set, against a
+ * query. Bare words match whole terms, word* matches a prefix and "quoted words" match a phrase.
+ * Filters such as method:POST or status:5xx narrow the results as described in parseSearchQuery.
+ * @param {string} query
+ * @param {boolean} includeResponses
+ * @param {number} limit
+ * @returns {Promise<$models.SearchResult[]> & { cancel(): void }}
+ */
+export function FullTextSearch(query, includeResponses, limit) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1770231318, query, includeResponses, limit));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType6($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
 /**
  * @returns {Promise<$models.Collection[]> & { cancel(): void }}
  */
 export function GetAllCollections() {
     let $resultPromise = /** @type {any} */($Call.ByID(668722804));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType2($result);
+        return $$createType7($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
 }
 
 /**
+ * GetAllRequestsList lists every request, or with tags only those carrying all of them,
+ * directly or through one of their collections.
  * TODO: lock down response object, replace "" with nulls etc
+ * @param {string[]} tags
  * @returns {Promise<$models.Request[]> & { cancel(): void }}
  */
-export function GetAllRequestsList() {
-    let $resultPromise = /** @type {any} */($Call.ByID(1997938213));
+export function GetAllRequestsList(tags) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1997938213, tags));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType3($result);
+        return $$createType8($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @returns {Promise<$models.CollectionDefaults> & { cancel(): void }}
+ */
+export function GetCollectionDefaults(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3806876754, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType9($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @returns {Promise<$models.RetentionPolicy> & { cancel(): void }}
+ */
+export function GetCollectionRetention(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(399607866, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType10($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * GetCollectionScripts returns the scripts that run before and after every request in a
+ * collection and the collections below it.
+ * @param {string} collectionID
+ * @returns {Promise<$models.RequestScripts> & { cancel(): void }}
+ */
+export function GetCollectionScripts(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(660855464, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType11($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @returns {Promise<string[]> & { cancel(): void }}
+ */
+export function GetCollectionTags(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3612424513, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType12($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @returns {Promise<$models.Variable[]> & { cancel(): void }}
+ */
+export function GetCollectionVariables(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1789420113, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType14($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @returns {Promise<$models.Favorites> & { cancel(): void }}
+ */
+export function GetFavorites() {
+    let $resultPromise = /** @type {any} */($Call.ByID(1247746529));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType15($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @returns {Promise<$models.JournalState> & { cancel(): void }}
+ */
+export function GetJournalState() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3149345972));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType16($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
@@ -108,7 +384,86 @@ export function GetAllRequestsList() {
 export function GetRequest(id) {
     let $resultPromise = /** @type {any} */($Call.ByID(1989088877, id));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType1($result);
+        return $$createType4($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @returns {Promise<$models.QueryParam[]> & { cancel(): void }}
+ */
+export function GetRequestParams(requestID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3220890443, requestID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType18($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @returns {Promise<$models.PathVariable[]> & { cancel(): void }}
+ */
+export function GetRequestPathVariables(requestID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(693751403, requestID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType20($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @returns {Promise<$models.RequestScripts> & { cancel(): void }}
+ */
+export function GetRequestScripts(requestID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3316262979, requestID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType11($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @returns {Promise<string[]> & { cancel(): void }}
+ */
+export function GetRequestTags(requestID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(83270984, requestID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType12($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @returns {Promise<$models.Variable[]> & { cancel(): void }}
+ */
+export function GetRequestVariables(requestID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(640784826, requestID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType14($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * GetRequestsByTag lists the requests tagged directly or through one of their collections.
+ * @param {string} tag
+ * @returns {Promise<$models.Request[]> & { cancel(): void }}
+ */
+export function GetRequestsByTag(tag) {
+    let $resultPromise = /** @type {any} */($Call.ByID(110729793, tag));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType8($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
@@ -121,7 +476,33 @@ export function GetRequest(id) {
 export function GetResponseHistory(requestID) {
     let $resultPromise = /** @type {any} */($Call.ByID(3419080141, requestID));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType5($result);
+        return $$createType22($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * GetResponseSnapshot returns the request that produced a response, with secrets masked.
+ * @param {number} responseID
+ * @returns {Promise<$models.RequestSnapshot> & { cancel(): void }}
+ */
+export function GetResponseSnapshot(responseID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2551569107, responseID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType23($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @returns {Promise<$models.Tag[]> & { cancel(): void }}
+ */
+export function GetTags() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3284484221));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType25($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
@@ -135,6 +516,118 @@ export function Init() {
     return $resultPromise;
 }
 
+/**
+ * @param {number} id
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function PinResponse(id) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1793811412, id));
+    return $resultPromise;
+}
+
+/**
+ * PruneResponseHistory applies the retention policies to the responses and recorded failures
+ * of every request, then trims the oldest responses until the stored bodies fit the global size
+ * limit. Pinned responses are never removed. The database is vacuumed when anything was deleted.
+ * @returns {Promise<$models.RetentionResult> & { cancel(): void }}
+ */
+export function PruneResponseHistory() {
+    let $resultPromise = /** @type {any} */($Call.ByID(4191932633));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType26($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * Redo reapplies the most recently undone operation and returns it, or nil when there is
+ * nothing to redo.
+ * @returns {Promise<$models.JournalOperation | null> & { cancel(): void }}
+ */
+export function Redo() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3636572192));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType28($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @param {string} tag
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function RemoveCollectionTag(collectionID, tag) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2189720452, collectionID, tag));
+    return $resultPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @param {string} tag
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function RemoveRequestTag(requestID, tag) {
+    let $resultPromise = /** @type {any} */($Call.ByID(4006690759, requestID, tag));
+    return $resultPromise;
+}
+
+/**
+ * RenameTag renames a tag everywhere it is used. Renaming onto an existing tag merges the two.
+ * @param {string} oldName
+ * @param {string} newName
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function RenameTag(oldName, newName) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1630306802, oldName, newName));
+    return $resultPromise;
+}
+
+/**
+ * RerunResponse sends the exact request recorded with a response again and records the result
+ * as a new history entry. Scripts are not run, since the snapshot already contains their effects.
+ * @param {number} responseID
+ * @returns {Promise<json$0.RawMessage> & { cancel(): void }}
+ */
+export function RerunResponse(responseID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(687002433, responseID));
+    return $resultPromise;
+}
+
+/**
+ * ResolveInheritedDefaults walks from the root collection down to collectionID. A header set
+ * further down replaces one of the same name set above it, and a disabled header removes it.
+ * The auth comes from the nearest collection that sets one.
+ * @param {string} collectionID
+ * @returns {Promise<$models.InheritedDefaults> & { cancel(): void }}
+ */
+export function ResolveInheritedDefaults(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2350695440, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType29($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * ResolveVariables returns every variable visible to a request together with the scope that
+ * supplied it. Secret values stay masked.
+ * @param {number} requestID
+ * @param {string} environment
+ * @returns {Promise<$models.ResolvedVariable[]> & { cancel(): void }}
+ */
+export function ResolveVariables(requestID, environment) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2350907421, requestID, environment));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType31($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
 /**
  * @param {string | null} collectionId
  * @param {string} name
@@ -152,26 +645,104 @@ export function Init() {
 export function SaveRequest(collectionId, name, description, method, url, headers, body, bodyType, bodyFormat, auth, response) {
     let $resultPromise = /** @type {any} */($Call.ByID(1341307122, collectionId, name, description, method, url, headers, body, bodyType, bodyFormat, auth, response));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType1($result);
+        return $$createType4($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
 }
 
 /**
+ * SearchRequests ranks requests through the full-text index, treating every word as a prefix
+ * so results update while typing. Filters such as method:POST, host:, status:5xx, in:"Billing"
+ * and has:auth narrow the results; see parseSearchQuery. FullTextSearch accepts the explicit
+ * query syntax.
  * @param {string} searchTerm
  * @returns {Promise<$models.Request[]> & { cancel(): void }}
  */
 export function SearchRequests(searchTerm) {
     let $resultPromise = /** @type {any} */($Call.ByID(2775248826, searchTerm));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType3($result);
+        return $$createType8($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
 }
 
 /**
+ * @param {string} collectionID
+ * @param {$models.CollectionDefaults} defaults
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetCollectionDefaults(collectionID, defaults) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2428384894, collectionID, defaults));
+    return $resultPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @param {boolean} favorite
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetCollectionFavorite(collectionID, favorite) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1833704632, collectionID, favorite));
+    return $resultPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @param {$models.RetentionPolicy} policy
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetCollectionRetention(collectionID, policy) {
+    let $resultPromise = /** @type {any} */($Call.ByID(690020678, collectionID, policy));
+    return $resultPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @param {$models.RequestScripts} scripts
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetCollectionScripts(collectionID, scripts) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3608808260, collectionID, scripts));
+    return $resultPromise;
+}
+
+/**
+ * SetCollectionSortOrder moves a collection to position sortOrder among the collections under
+ * the same parent and renumbers them from zero.
+ * @param {string} collectionID
+ * @param {number} sortOrder
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetCollectionSortOrder(collectionID, sortOrder) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3991013592, collectionID, sortOrder));
+    return $resultPromise;
+}
+
+/**
+ * SetCollectionTags replaces the tags of a collection.
+ * @param {string} collectionID
+ * @param {string[]} tags
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetCollectionTags(collectionID, tags) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1394025165, collectionID, tags));
+    return $resultPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @param {$models.Variable[]} variables
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetCollectionVariables(collectionID, variables) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2535040861, collectionID, variables));
+    return $resultPromise;
+}
+
+/**
+ * SetRequestCollection moves a request to the end of another collection.
  * @param {number} requestId
  * @param {string} collectionId
  * @returns {Promise<void> & { cancel(): void }}
@@ -181,6 +752,48 @@ export function SetRequestCollection(requestId, collectionId) {
     return $resultPromise;
 }
 
+/**
+ * @param {number} requestID
+ * @param {boolean} favorite
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetRequestFavorite(requestID, favorite) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3640840393, requestID, favorite));
+    return $resultPromise;
+}
+
+/**
+ * SetRequestParams replaces a request's params and rewrites the query string of its stored URL
+ * from the enabled ones. The updated URL is returned so the editor can show it.
+ * @param {number} requestID
+ * @param {$models.QueryParam[]} params
+ * @returns {Promise<string> & { cancel(): void }}
+ */
+export function SetRequestParams(requestID, params) {
+    let $resultPromise = /** @type {any} */($Call.ByID(343038079, requestID, params));
+    return $resultPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @param {$models.PathVariable[]} variables
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetRequestPathVariables(requestID, variables) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3388156367, requestID, variables));
+    return $resultPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @param {$models.RequestScripts} scripts
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetRequestScripts(requestID, scripts) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2014407423, requestID, scripts));
+    return $resultPromise;
+}
+
 /**
  * TODO: Implement this
  * @param {number} id
@@ -192,6 +805,60 @@ export function SetRequestSortOrder(id, sortOrder) {
     return $resultPromise;
 }
 
+/**
+ * SetRequestTags replaces the tags of a request.
+ * @param {number} requestID
+ * @param {string[]} tags
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetRequestTags(requestID, tags) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1925428324, requestID, tags));
+    return $resultPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @param {$models.Variable[]} variables
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetRequestVariables(requestID, variables) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1000269910, requestID, variables));
+    return $resultPromise;
+}
+
+/**
+ * @param {string} name
+ * @param {string} color
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function SetTagColor(name, color) {
+    let $resultPromise = /** @type {any} */($Call.ByID(3073261359, name, color));
+    return $resultPromise;
+}
+
+/**
+ * Undo reverts the most recent operation that has not been undone and returns it, or nil when
+ * there is nothing to undo.
+ * @returns {Promise<$models.JournalOperation | null> & { cancel(): void }}
+ */
+export function Undo() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3595155606));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType28($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {number} id
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function UnpinResponse(id) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2505623765, id));
+    return $resultPromise;
+}
+
 /**
  * @param {string} collectionId
  * @param {string | null} parentId
@@ -220,16 +887,42 @@ export function UpdateCollectionParent(collectionId, parentId) {
 export function UpdateRequest(id, collectionId, name, description, method, requestUrl, headers, body, bodyType, bodyFormat, auth, response) {
     let $resultPromise = /** @type {any} */($Call.ByID(1380900686, id, collectionId, name, description, method, requestUrl, headers, body, bodyType, bodyFormat, auth, response));
     let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
-        return $$createType1($result);
+        return $$createType4($result);
     }));
     $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
     return $typingPromise;
 }
 
 // Private type creation functions
-const $$createType0 = $models.Collection.createFrom;
-const $$createType1 = $models.Request.createFrom;
-const $$createType2 = $Create.Array($$createType0);
-const $$createType3 = $Create.Array($$createType1);
-const $$createType4 = $models.Response.createFrom;
-const $$createType5 = $Create.Array($$createType4);
+const $$createType0 = $models.BulkResult.createFrom;
+const $$createType1 = $models.Collection.createFrom;
+const $$createType2 = $models.CollectionDeleteSummary.createFrom;
+const $$createType3 = $models.ResponseDiff.createFrom;
+const $$createType4 = $models.Request.createFrom;
+const $$createType5 = $models.SearchResult.createFrom;
+const $$createType6 = $Create.Array($$createType5);
+const $$createType7 = $Create.Array($$createType1);
+const $$createType8 = $Create.Array($$createType4);
+const $$createType9 = $models.CollectionDefaults.createFrom;
+const $$createType10 = $models.RetentionPolicy.createFrom;
+const $$createType11 = $models.RequestScripts.createFrom;
+const $$createType12 = $Create.Array($Create.Any);
+const $$createType13 = $models.Variable.createFrom;
+const $$createType14 = $Create.Array($$createType13);
+const $$createType15 = $models.Favorites.createFrom;
+const $$createType16 = $models.JournalState.createFrom;
+const $$createType17 = $models.QueryParam.createFrom;
+const $$createType18 = $Create.Array($$createType17);
+const $$createType19 = $models.PathVariable.createFrom;
+const $$createType20 = $Create.Array($$createType19);
+const $$createType21 = $models.Response.createFrom;
+const $$createType22 = $Create.Array($$createType21);
+const $$createType23 = $models.RequestSnapshot.createFrom;
+const $$createType24 = $models.Tag.createFrom;
+const $$createType25 = $Create.Array($$createType24);
+const $$createType26 = $models.RetentionResult.createFrom;
+const $$createType27 = $models.JournalOperation.createFrom;
+const $$createType28 = $Create.Nullable($$createType27);
+const $$createType29 = $models.InheritedDefaults.createFrom;
+const $$createType30 = $models.ResolvedVariable.createFrom;
+const $$createType31 = $Create.Array($$createType30);
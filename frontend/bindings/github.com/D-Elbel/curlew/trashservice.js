@@ -0,0 +1,104 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import {Call as $Call, Create as $Create} from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * EmptyTrash permanently deletes everything in the trash.
+ * @returns {Promise<$models.TrashPurgeResult> & { cancel(): void }}
+ */
+export function EmptyTrash() {
+    let $resultPromise = /** @type {any} */($Call.ByID(2672970998));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @returns {Promise<$models.TrashItem[]> & { cancel(): void }}
+ */
+export function GetTrash() {
+    let $resultPromise = /** @type {any} */($Call.ByID(1316431481));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType2($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {string} collectionID
+ * @returns {Promise<$models.TrashPurgeResult> & { cancel(): void }}
+ */
+export function PurgeCollection(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1483509098, collectionID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * PurgeExpiredTrash permanently deletes items that have been in the trash longer than the
+ * configured number of days.
+ * @returns {Promise<$models.TrashPurgeResult> & { cancel(): void }}
+ */
+export function PurgeExpiredTrash() {
+    let $resultPromise = /** @type {any} */($Call.ByID(3387470855));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * @param {number} requestID
+ * @returns {Promise<$models.TrashPurgeResult> & { cancel(): void }}
+ */
+export function PurgeRequest(requestID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(6372791, requestID));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+/**
+ * RestoreCollection restores a deleted collection with everything deleted along with it. It
+ * returns to its original parent and position when the parent still exists, and to the end of
+ * the top level otherwise.
+ * @param {string} collectionID
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function RestoreCollection(collectionID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(2510493831, collectionID));
+    return $resultPromise;
+}
+
+/**
+ * RestoreRequest puts a deleted request back at its sort position in its collection. A request
+ * whose collection is gone or deleted is restored to the end of the uncategorized requests.
+ * @param {number} requestID
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function RestoreRequest(requestID) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1535046840, requestID));
+    return $resultPromise;
+}
+
+// Private type creation functions
+const $$createType0 = $models.TrashPurgeResult.createFrom;
+const $$createType1 = $models.TrashItem.createFrom;
+const $$createType2 = $Create.Array($$createType1);
@@ -0,0 +1,44 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import {Call as $Call, Create as $Create} from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * ExportWorkspace writes the workspace to a single archive. Secret variables are encrypted with
+ * this machine's key, so they are re-encrypted under secretsPassphrase, which ImportWorkspace
+ * then asks for; exporting a workspace that has secrets requires one.
+ * @param {string} archivePath
+ * @param {string} secretsPassphrase
+ * @returns {Promise<void> & { cancel(): void }}
+ */
+export function ExportWorkspace(archivePath, secretsPassphrase) {
+    let $resultPromise = /** @type {any} */($Call.ByID(1512671679, archivePath, secretsPassphrase));
+    return $resultPromise;
+}
+
+/**
+ * ImportWorkspace restores an archive written by ExportWorkspace. secretsPassphrase is the
+ * export passphrase and is only needed when the archive carries secret variables.
+ * @param {string} archivePath
+ * @param {string} mode
+ * @param {string} secretsPassphrase
+ * @returns {Promise<$models.WorkspaceImportSummary> & { cancel(): void }}
+ */
+export function ImportWorkspace(archivePath, mode, secretsPassphrase) {
+    let $resultPromise = /** @type {any} */($Call.ByID(284800390, archivePath, mode, secretsPassphrase));
+    let $typingPromise = /** @type {any} */($resultPromise.then(($result) => {
+        return $$createType0($result);
+    }));
+    $typingPromise.cancel = $resultPromise.cancel.bind($resultPromise);
+    return $typingPromise;
+}
+
+// Private type creation functions
+const $$createType0 = $models.WorkspaceImportSummary.createFrom;
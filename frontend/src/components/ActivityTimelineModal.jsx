@@ -0,0 +1,256 @@
+import React, { useCallback, useEffect, useState } from "react";
+import { Dialog, DialogContent } from "@/components/ui/dialog";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import {
+    GetExecutionHosts,
+    GetExecutionLog,
+} from "../../bindings/github.com/D-Elbel/curlew/historyservice.js";
+import { GetAllCollections } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js";
+import { useEnvarStore } from "@/stores/envarStore";
+
+const PAGE_SIZE = 50;
+const STATUS_CLASSES = ["2xx", "3xx", "4xx", "5xx", "error"];
+
+const emptyFilters = {
+    from: "",
+    to: "",
+    statusClasses: [],
+    host: "",
+    collectionId: "",
+    environment: "",
+};
+
+const toISOString = (value) => (value ? new Date(value).toISOString() : null);
+
+export default function ActivityTimelineModal({ open, onOpenChange }) {
+    const envs = useEnvarStore((state) => state.environmentVariables);
+    const [filters, setFilters] = useState(emptyFilters);
+    const [page, setPage] = useState(1);
+    const [result, setResult] = useState({ entries: [], total: 0 });
+    const [hosts, setHosts] = useState([]);
+    const [collections, setCollections] = useState([]);
+    const [error, setError] = useState("");
+
+    const loadTimeline = useCallback(async () => {
+        try {
+            const data = await GetExecutionLog({
+                from: toISOString(filters.from),
+                to: toISOString(filters.to),
+                statusClasses: filters.statusClasses,
+                host: filters.host,
+                collectionId: filters.collectionId,
+                environment: filters.environment,
+                page,
+                pageSize: PAGE_SIZE,
+            });
+            setResult({ entries: data?.entries || [], total: data?.total || 0 });
+            setError("");
+        } catch (err) {
+            console.error("Failed to load activity timeline", err);
+            setError(String(err));
+        }
+    }, [filters, page]);
+
+    useEffect(() => {
+        if (!open) {
+            return;
+        }
+        GetExecutionHosts().then((list) => setHosts(list || [])).catch(() => setHosts([]));
+        GetAllCollections().then((list) => setCollections(list || [])).catch(() => setCollections([]));
+    }, [open]);
+
+    useEffect(() => {
+        if (open) {
+            loadTimeline();
+        }
+    }, [open, loadTimeline]);
+
+    const updateFilter = (key, value) => {
+        setPage(1);
+        setFilters((prev) => ({ ...prev, [key]: value }));
+    };
+
+    const toggleStatusClass = (statusClass) => {
+        updateFilter(
+            "statusClasses",
+            filters.statusClasses.includes(statusClass)
+                ? filters.statusClasses.filter((c) => c !== statusClass)
+                : [...filters.statusClasses, statusClass]
+        );
+    };
+
+    const pageCount = Math.max(1, Math.ceil(result.total / PAGE_SIZE));
+    const selectClass = "bg-black/30 border border-gray-700 rounded px-2 py-1 text-sm";
+
+    return (
+        <Dialog open={open} onOpenChange={onOpenChange}>
+            <DialogContent className="min-w-[80vw] h-[80vh] p-0 overflow-hidden">
+                <div className="flex flex-col h-full p-4 gap-3">
+                    <h2 className="text-lg font-semibold">Activity</h2>
+                    <div className="flex flex-wrap items-center gap-3 text-sm">
+                        <label className="flex items-center gap-1">
+                            From
+                            <Input
+                                type="datetime-local"
+                                value={filters.from}
+                                onChange={(e) => updateFilter("from", e.target.value)}
+                                className="w-52"
+                            />
+                        </label>
+                        <label className="flex items-center gap-1">
+                            To
+                            <Input
+                                type="datetime-local"
+                                value={filters.to}
+                                onChange={(e) => updateFilter("to", e.target.value)}
+                                className="w-52"
+                            />
+                        </label>
+                        <select
+                            className={selectClass}
+                            value={filters.host}
+                            onChange={(e) => updateFilter("host", e.target.value)}
+                        >
+                            <option value="">All hosts</option>
+                            {hosts.map((host) => (
+                                <option key={host} value={host}>
+                                    {host}
+                                </option>
+                            ))}
+                        </select>
+                        <select
+                            className={selectClass}
+                            value={filters.collectionId}
+                            onChange={(e) => updateFilter("collectionId", e.target.value)}
+                        >
+                            <option value="">All collections</option>
+                            {collections.map((collection) => (
+                                <option key={collection.id} value={collection.id}>
+                                    {collection.name}
+                                </option>
+                            ))}
+                        </select>
+                        <select
+                            className={selectClass}
+                            value={filters.environment}
+                            onChange={(e) => updateFilter("environment", e.target.value)}
+                        >
+                            <option value="">All environments</option>
+                            {envs.map((env) => (
+                                <option key={env.env} value={env.env}>
+                                    {env.env}
+                                </option>
+                            ))}
+                        </select>
+                        {STATUS_CLASSES.map((statusClass) => (
+                            <label key={statusClass} className="flex items-center gap-1">
+                                <input
+                                    type="checkbox"
+                                    checked={filters.statusClasses.includes(statusClass)}
+                                    onChange={() => toggleStatusClass(statusClass)}
+                                />
+                                {statusClass}
+                            </label>
+                        ))}
+                        <Button
+                            variant="outline"
+                            size="sm"
+                            onClick={() => {
+                                setPage(1);
+                                setFilters(emptyFilters);
+                            }}
+                        >
+                            Reset
+                        </Button>
+                    </div>
+
+                    {error && <div className="text-sm text-red-400">{error}</div>}
+
+                    <div className="flex-1 overflow-auto">
+                        <table className="w-full text-sm border-collapse">
+                            <thead>
+                                <tr className="text-xs uppercase text-gray-400">
+                                    <th className="text-left font-normal border-b border-gray-700 pb-2">Time</th>
+                                    <th className="text-left font-normal border-b border-gray-700 pb-2">Status</th>
+                                    <th className="text-left font-normal border-b border-gray-700 pb-2">Request</th>
+                                    <th className="text-left font-normal border-b border-gray-700 pb-2">URL</th>
+                                    <th className="text-left font-normal border-b border-gray-700 pb-2">Environment</th>
+                                    <th className="text-left font-normal border-b border-gray-700 pb-2">Duration</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {result.entries.map((entry) => (
+                                    <tr key={`${entry.kind}-${entry.id}`} className="border-b border-gray-800/60">
+                                        <td className="py-2 pr-4 text-gray-200 whitespace-nowrap">
+                                            {entry.createdAt ? new Date(entry.createdAt).toLocaleString() : "Unknown"}
+                                        </td>
+                                        <td className="py-2 pr-4">
+                                            {entry.kind === "failure" ? (
+                                                <span
+                                                    className="px-2 py-1 rounded text-xs bg-red-500/20 text-red-200"
+                                                    title={entry.error}
+                                                >
+                                                    Error
+                                                </span>
+                                            ) : (
+                                                <span
+                                                    className={`px-2 py-1 rounded text-xs ${
+                                                        entry.statusCode >= 400
+                                                            ? "bg-red-500/20 text-red-200"
+                                                            : "bg-green-500/20 text-green-200"
+                                                    }`}
+                                                >
+                                                    {entry.statusCode}
+                                                </span>
+                                            )}
+                                        </td>
+                                        <td className="py-2 pr-4 text-gray-200">
+                                            {entry.requestName || `#${entry.requestId}`}
+                                            {entry.collectionName && (
+                                                <span className="text-gray-500"> · {entry.collectionName}</span>
+                                            )}
+                                        </td>
+                                        <td className="py-2 pr-4 font-mono text-xs text-gray-300 break-all">
+                                            {entry.method} {entry.url}
+                                            {entry.kind === "failure" && (
+                                                <div className="text-red-300">{entry.error}</div>
+                                            )}
+                                        </td>
+                                        <td className="py-2 pr-4 text-gray-300">{entry.environment || "—"}</td>
+                                        <td className="py-2 pr-4 text-gray-300">
+                                            {entry.runtimeMS != null ? `${entry.runtimeMS} ms` : "—"}
+                                        </td>
+                                    </tr>
+                                ))}
+                            </tbody>
+                        </table>
+                        {result.entries.length === 0 && !error && (
+                            <div className="text-sm text-gray-400 mt-4">No executions match these filters.</div>
+                        )}
+                    </div>
+
+                    <div className="flex items-center justify-between text-sm text-gray-400">
+                        <span>{result.total} executions</span>
+                        <div className="flex items-center gap-2">
+                            <Button variant="outline" size="sm" disabled={page <= 1} onClick={() => setPage(page - 1)}>
+                                Previous
+                            </Button>
+                            <span>
+                                Page {page} of {pageCount}
+                            </span>
+                            <Button
+                                variant="outline"
+                                size="sm"
+                                disabled={page >= pageCount}
+                                onClick={() => setPage(page + 1)}
+                            >
+                                Next
+                            </Button>
+                        </div>
+                    </div>
+                </div>
+            </DialogContent>
+        </Dialog>
+    );
+}
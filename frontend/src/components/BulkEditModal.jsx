@@ -0,0 +1,249 @@
+import React, { useEffect, useMemo, useState } from "react";
+import { Dialog, DialogContent } from "@/components/ui/dialog";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Checkbox } from "@/components/ui/checkbox";
+import { Switch } from "@/components/ui/switch";
+import {
+    Select,
+    SelectTrigger,
+    SelectValue,
+    SelectContent,
+    SelectItem,
+} from "@/components/ui/select";
+import {
+    BulkDeleteRequests,
+    BulkMoveRequests,
+    BulkReplaceInURLs,
+    BulkSetAuth,
+    BulkSetHeader,
+} from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js";
+import { useRequestStore } from "@/stores/requestStore.js";
+import { methodColourMap } from "@/utils/constants.js";
+
+const UNCATEGORIZED_VALUE = "__UNCATEGORIZED__";
+
+// Bulk edits for the requests in a collection and the collections below it. Every action runs
+// in one transaction and can be undone as a single step.
+export default function BulkEditModal({ open, onOpenChange, collection }) {
+    const requests = useRequestStore((state) => state.requests);
+    const collections = useRequestStore((state) => state.collections);
+    const loadAll = useRequestStore((state) => state.loadAll);
+
+    const [selected, setSelected] = useState([]);
+    const [moveTarget, setMoveTarget] = useState(UNCATEGORIZED_VALUE);
+    const [header, setHeader] = useState({ key: "", value: "", recursive: true });
+    const [replace, setReplace] = useState({ find: "", replace: "" });
+    const [auth, setAuth] = useState("");
+    const [report, setReport] = useState("");
+    const [error, setError] = useState("");
+
+    useEffect(() => {
+        if (open) {
+            setSelected([]);
+            setReport("");
+            setError("");
+        }
+    }, [open, collection?.id]);
+
+    const subtreeRequests = useMemo(() => {
+        if (!collection) {
+            return [];
+        }
+        const ids = new Set([collection.id]);
+        let grown = true;
+        while (grown) {
+            grown = false;
+            collections.forEach((c) => {
+                if (c.parentCollectionId && ids.has(c.parentCollectionId) && !ids.has(c.id)) {
+                    ids.add(c.id);
+                    grown = true;
+                }
+            });
+        }
+        const names = new Map(collections.map((c) => [c.id, c.name]));
+        return requests
+            .filter((r) => ids.has(r.collectionId))
+            .map((r) => ({ ...r, collectionName: names.get(r.collectionId) }));
+    }, [collection, collections, requests]);
+
+    const run = async (action) => {
+        try {
+            const result = await action();
+            await loadAll();
+            setReport(`${result.operation}: changed ${result.changed.length} of ${result.matched} requests`);
+            setError("");
+        } catch (err) {
+            console.error("Bulk edit failed", err);
+            setError(String(err));
+        }
+    };
+
+    const toggle = (id, checked) =>
+        setSelected((current) => (checked ? [...current, id] : current.filter((s) => s !== id)));
+
+    const moveSelected = () =>
+        run(async () => {
+            const result = await BulkMoveRequests(selected, moveTarget === UNCATEGORIZED_VALUE ? null : moveTarget);
+            setSelected([]);
+            return result;
+        });
+
+    const deleteSelected = () => {
+        if (!window.confirm(`Move ${selected.length} requests to the trash?`)) {
+            return;
+        }
+        run(async () => {
+            const result = await BulkDeleteRequests(selected);
+            setSelected([]);
+            return result;
+        });
+    };
+
+    if (!collection) {
+        return null;
+    }
+
+    return (
+        <Dialog open={open} onOpenChange={onOpenChange}>
+            <DialogContent className="min-w-[60vw] h-[80vh] p-0 overflow-hidden">
+                <div className="flex flex-col h-full p-4 gap-3 overflow-auto">
+                    <h2 className="text-lg font-semibold pr-8">Bulk edit "{collection.name}"</h2>
+                    {report && <div className="text-sm text-green-400">{report}</div>}
+                    {error && <div className="text-sm text-red-400">{error}</div>}
+
+                    <section className="space-y-2">
+                        <h3 className="text-sm font-medium">Requests</h3>
+                        <div className="max-h-56 overflow-auto border border-gray-800 rounded">
+                            {subtreeRequests.length === 0 ? (
+                                <div className="text-sm text-gray-400 p-2">No requests in this collection.</div>
+                            ) : (
+                                subtreeRequests.map((req) => (
+                                    <label key={req.id} className="flex items-center gap-2 px-2 py-1 text-sm">
+                                        <Checkbox
+                                            checked={selected.includes(req.id)}
+                                            onCheckedChange={(checked) => toggle(req.id, checked === true)}
+                                        />
+                                        <span className={`text-xs font-medium ${methodColourMap.get(req.method)}`}>
+                                            {req.method}
+                                        </span>
+                                        <span className="truncate">{req.name || req.url || "Untitled"}</span>
+                                        <span className="text-xs text-gray-500 ml-auto">{req.collectionName}</span>
+                                    </label>
+                                ))
+                            )}
+                        </div>
+                        <div className="flex items-center gap-2">
+                            <Button
+                                variant="outline"
+                                size="sm"
+                                onClick={() =>
+                                    setSelected(
+                                        selected.length === subtreeRequests.length ? [] : subtreeRequests.map((r) => r.id)
+                                    )
+                                }
+                            >
+                                {selected.length === subtreeRequests.length && selected.length > 0 ? "Select none" : "Select all"}
+                            </Button>
+                            <Select value={moveTarget} onValueChange={setMoveTarget}>
+                                <SelectTrigger className="w-56">
+                                    <SelectValue placeholder="Move to" />
+                                </SelectTrigger>
+                                <SelectContent>
+                                    <SelectItem value={UNCATEGORIZED_VALUE}>Uncategorized</SelectItem>
+                                    {collections.map((c) => (
+                                        <SelectItem key={c.id} value={c.id}>
+                                            {c.name}
+                                        </SelectItem>
+                                    ))}
+                                </SelectContent>
+                            </Select>
+                            <Button size="sm" onClick={moveSelected} disabled={selected.length === 0}>
+                                Move {selected.length || ""}
+                            </Button>
+                            <Button variant="outline" size="sm" onClick={deleteSelected} disabled={selected.length === 0}>
+                                Delete {selected.length || ""}
+                            </Button>
+                        </div>
+                    </section>
+
+                    <section className="space-y-2">
+                        <h3 className="text-sm font-medium">Set header</h3>
+                        <div className="flex items-center gap-2">
+                            <Input
+                                placeholder="Name"
+                                value={header.key}
+                                onChange={(e) => setHeader({ ...header, key: e.target.value })}
+                                className="w-48"
+                            />
+                            <Input
+                                placeholder="Value"
+                                value={header.value}
+                                onChange={(e) => setHeader({ ...header, value: e.target.value })}
+                                className="flex-1"
+                            />
+                            <label className="flex items-center gap-2 text-xs text-gray-400 whitespace-nowrap">
+                                <Switch
+                                    checked={header.recursive}
+                                    onCheckedChange={(checked) => setHeader({ ...header, recursive: checked })}
+                                />
+                                Sub-collections
+                            </label>
+                            <Button
+                                size="sm"
+                                disabled={!header.key.trim()}
+                                onClick={() => run(() => BulkSetHeader(collection.id, header.key, header.value, header.recursive))}
+                            >
+                                Apply
+                            </Button>
+                        </div>
+                    </section>
+
+                    <section className="space-y-2">
+                        <h3 className="text-sm font-medium">Replace in URLs</h3>
+                        <div className="flex items-center gap-2">
+                            <Input
+                                placeholder="Find, e.g. https://api.example.com"
+                                value={replace.find}
+                                onChange={(e) => setReplace({ ...replace, find: e.target.value })}
+                                className="flex-1"
+                            />
+                            <Input
+                                placeholder="Replace, e.g. {{baseUrl}}"
+                                value={replace.replace}
+                                onChange={(e) => setReplace({ ...replace, replace: e.target.value })}
+                                className="flex-1"
+                            />
+                            <Button
+                                size="sm"
+                                disabled={!replace.find}
+                                onClick={() => run(() => BulkReplaceInURLs(collection.id, replace.find, replace.replace))}
+                            >
+                                Replace
+                            </Button>
+                        </div>
+                    </section>
+
+                    <section className="space-y-2">
+                        <h3 className="text-sm font-medium">Set auth</h3>
+                        <p className="text-xs text-gray-400">
+                            Applies to every request in this collection and its sub-collections. Leave empty to inherit the
+                            collection auth, or enter noauth to send none.
+                        </p>
+                        <div className="flex items-center gap-2">
+                            <Input
+                                placeholder="e.g. Bearer {{token}}"
+                                value={auth}
+                                onChange={(e) => setAuth(e.target.value)}
+                                className="flex-1"
+                            />
+                            <Button size="sm" onClick={() => run(() => BulkSetAuth(collection.id, auth))}>
+                                Apply
+                            </Button>
+                        </div>
+                    </section>
+                </div>
+            </DialogContent>
+        </Dialog>
+    );
+}
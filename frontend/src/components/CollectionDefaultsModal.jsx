@@ -0,0 +1,205 @@
+import React, { useEffect, useState } from "react";
+import CodeMirror from "@uiw/react-codemirror";
+import { javascript } from "@codemirror/lang-javascript";
+import { copilot } from "@uiw/codemirror-theme-copilot";
+import { Dialog, DialogContent } from "@/components/ui/dialog";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Switch } from "@/components/ui/switch";
+import {
+    GetCollectionDefaults,
+    GetCollectionScripts,
+    ResolveInheritedDefaults,
+    SetCollectionDefaults,
+    SetCollectionScripts,
+} from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js";
+import { useRequestStore } from "@/stores/requestStore.js";
+
+// Stored as the auth of a collection that sends no Authorization header; an empty auth inherits.
+const AUTH_NONE = "noauth";
+
+const authModeOf = (auth) => (!auth ? "inherit" : auth === AUTH_NONE ? "none" : "custom");
+
+// Default headers, auth and scripts for the requests in a collection and the collections below it.
+export default function CollectionDefaultsModal({ open, onOpenChange, collection }) {
+    const loadAll = useRequestStore((state) => state.loadAll);
+    const [headers, setHeaders] = useState([]);
+    const [authMode, setAuthMode] = useState("inherit");
+    const [auth, setAuth] = useState("");
+    const [inherited, setInherited] = useState(null);
+    const [scripts, setScripts] = useState({ preRequest: "", postResponse: "" });
+    const [error, setError] = useState("");
+
+    useEffect(() => {
+        if (!open || !collection) {
+            return;
+        }
+        const load = async () => {
+            try {
+                const defaults = await GetCollectionDefaults(collection.id);
+                setHeaders(
+                    (defaults.headers || []).map((h) => ({
+                        key: h.key,
+                        value: h.value,
+                        enabled: !h.disabled && h.enabled !== false,
+                    }))
+                );
+                setAuthMode(authModeOf(defaults.auth));
+                setAuth(defaults.auth === AUTH_NONE ? "" : defaults.auth || "");
+                const collectionScripts = await GetCollectionScripts(collection.id);
+                setScripts({
+                    preRequest: collectionScripts?.preRequest || "",
+                    postResponse: collectionScripts?.postResponse || "",
+                });
+                setInherited(
+                    collection.parentCollectionId
+                        ? await ResolveInheritedDefaults(collection.parentCollectionId)
+                        : null
+                );
+                setError("");
+            } catch (err) {
+                console.error("Failed to load collection defaults", err);
+                setError(String(err));
+            }
+        };
+        load();
+    }, [open, collection]);
+
+    const updateHeader = (index, changes) =>
+        setHeaders((current) => current.map((h, i) => (i === index ? { ...h, ...changes } : h)));
+
+    const save = async () => {
+        try {
+            await SetCollectionDefaults(collection.id, {
+                headers: headers.filter((h) => h.key.trim()),
+                auth: authMode === "inherit" ? "" : authMode === "none" ? AUTH_NONE : auth,
+            });
+            await SetCollectionScripts(collection.id, scripts);
+            await loadAll();
+            onOpenChange(false);
+        } catch (err) {
+            console.error("Failed to save collection defaults", err);
+            setError(String(err));
+        }
+    };
+
+    if (!collection) {
+        return null;
+    }
+
+    return (
+        <Dialog open={open} onOpenChange={onOpenChange}>
+            <DialogContent className="min-w-[50vw] p-0 overflow-hidden">
+                <div className="flex flex-col p-4 gap-4 max-h-[85vh] overflow-auto">
+                    <h2 className="text-lg font-semibold pr-8">Defaults for "{collection.name}"</h2>
+                    <p className="text-xs text-gray-400">
+                        Requests in this collection and its sub-collections inherit these headers and auth unless
+                        they set their own. A disabled header stops one of the same name being inherited. Scripts
+                        run before the scripts of sub-collections and requests.
+                    </p>
+                    {error && <div className="text-sm text-red-400">{error}</div>}
+
+                    <section className="space-y-2">
+                        <h3 className="text-sm font-medium">Headers</h3>
+                        {headers.map((header, index) => (
+                            <div key={index} className="flex items-center gap-2">
+                                <Switch
+                                    checked={header.enabled}
+                                    onCheckedChange={(checked) => updateHeader(index, { enabled: checked })}
+                                />
+                                <Input
+                                    placeholder="Name"
+                                    value={header.key}
+                                    onChange={(e) => updateHeader(index, { key: e.target.value })}
+                                    className="w-48"
+                                />
+                                <Input
+                                    placeholder="Value"
+                                    value={header.value}
+                                    onChange={(e) => updateHeader(index, { value: e.target.value })}
+                                    className="flex-1"
+                                />
+                                <Button
+                                    variant="ghost"
+                                    size="sm"
+                                    onClick={() => setHeaders((current) => current.filter((_, i) => i !== index))}
+                                >
+                                    Remove
+                                </Button>
+                            </div>
+                        ))}
+                        <Button
+                            variant="outline"
+                            size="sm"
+                            onClick={() => setHeaders((current) => [...current, { key: "", value: "", enabled: true }])}
+                        >
+                            Add header
+                        </Button>
+                        {inherited?.headers?.length > 0 && (
+                            <div className="text-xs text-gray-500">
+                                Inherited:{" "}
+                                {inherited.headers.map((h) => `${h.key} (${h.collectionName})`).join(", ")}
+                            </div>
+                        )}
+                    </section>
+
+                    <section className="space-y-2">
+                        <h3 className="text-sm font-medium">Auth</h3>
+                        <div className="flex items-center gap-2">
+                            <select
+                                value={authMode}
+                                onChange={(e) => setAuthMode(e.target.value)}
+                                className="bg-gray-800 text-white rounded px-2 py-1"
+                            >
+                                <option value="inherit">Inherit from parent</option>
+                                <option value="none">No auth</option>
+                                <option value="custom">Authorization header</option>
+                            </select>
+                            {authMode === "custom" && (
+                                <Input
+                                    placeholder="e.g. Bearer {{token}}"
+                                    value={auth}
+                                    onChange={(e) => setAuth(e.target.value)}
+                                    className="flex-1"
+                                />
+                            )}
+                        </div>
+                        {authMode === "inherit" && inherited?.auth && (
+                            <div className="text-xs text-gray-500">
+                                Inherits auth from {inherited.authCollectionName}.
+                            </div>
+                        )}
+                    </section>
+
+                    <section className="space-y-2">
+                        <h3 className="text-sm font-medium">Pre-request Script</h3>
+                        <CodeMirror
+                            value={scripts.preRequest}
+                            height="100px"
+                            extensions={[javascript()]}
+                            theme={copilot}
+                            className="border border-gray-700 rounded w-full"
+                            onChange={(value) => setScripts((current) => ({ ...current, preRequest: value }))}
+                        />
+                        <h3 className="text-sm font-medium">Post-response Script</h3>
+                        <CodeMirror
+                            value={scripts.postResponse}
+                            height="100px"
+                            extensions={[javascript()]}
+                            theme={copilot}
+                            className="border border-gray-700 rounded w-full"
+                            onChange={(value) => setScripts((current) => ({ ...current, postResponse: value }))}
+                        />
+                    </section>
+
+                    <div className="flex justify-end gap-2">
+                        <Button variant="outline" onClick={() => onOpenChange(false)}>
+                            Cancel
+                        </Button>
+                        <Button onClick={save}>Save</Button>
+                    </div>
+                </div>
+            </DialogContent>
+        </Dialog>
+    );
+}
@@ -1,6 +1,6 @@
 import React, { useState, useEffect, useRef } from "react"
 import hotkeys from "hotkeys-js"
-import { SearchRequests } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js"
+import { FullTextSearch, GetRequest } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js"
 import {
     Command,
     CommandDialog,
@@ -22,6 +22,8 @@ export default function CommandMenu({ onSelect }) {
     const [searchResults, setSearchResults] = useState([])
     const [searchActive, setSearchActive] = useState(false)
     const [searchTerm, setSearchTerm] = useState("")
+    const [searchError, setSearchError] = useState("")
+    const [includeResponses, setIncludeResponses] = useState(false)
     const searchTimeout = useRef(null)
     const isMounted = useRef(true)
 
@@ -40,7 +42,14 @@ export default function CommandMenu({ onSelect }) {
         }
     }, [openSearchCombo])
 
-    const searchRequests = (term) => {
+    // The last word is searched as a prefix while it is still being typed; filters such as
+    // method:POST and unfinished quotes are left alone.
+    const toSearchQuery = (term) =>
+        /(^|\s)[^\s:"]*[\p{L}\p{N}]$/u.test(term) && (term.split('"').length - 1) % 2 === 0
+            ? `${term}*`
+            : term
+
+    const searchRequests = (term, withResponses = includeResponses) => {
         setSearchTerm(term)
         if (searchTimeout.current) {
             clearTimeout(searchTimeout.current)
@@ -51,71 +60,96 @@ export default function CommandMenu({ onSelect }) {
                 if (isMounted.current) {
                     setSearchResults([])
                     setSearchActive(false)
+                    setSearchError("")
                 }
                 return
             }
             try {
-                const results = await SearchRequests(term)
-                console.log(results)
+                const results = await FullTextSearch(toSearchQuery(term), withResponses, 20)
                 if (isMounted.current) {
-                    setSearchResults(results)
+                    setSearchResults(results || [])
                     setSearchActive(true)
+                    setSearchError("")
                 }
             } catch (error) {
                 console.error("Error searching requests", error)
+                if (isMounted.current) {
+                    setSearchResults([])
+                    setSearchActive(false)
+                    setSearchError(String(error?.message || error))
+                }
             }
         }, 100)
     }
 
-    function highlightMatch(text, query) {
-        if (!query) return text
-        const regex = new RegExp(`(${query.replace(/[.*+?^${}()|[\]\\]/g, '\\$&')})`, 'gi')
-        const parts = text.split(regex)
-        return parts.map((part, i) =>
-            regex.test(part)
-                ? <mark key={i} className="bg-red-300 rounded px-1">{part}</mark>
+    // Snippets mark matches with \u0002 ... \u0003.
+    function renderSnippet(snippet) {
+        return (snippet || "").split(/(\u0002[^\u0003]*\u0003)/).map((part, i) =>
+            part.startsWith("\u0002")
+                ? <mark key={i} className="bg-red-300 rounded px-1">{part.slice(1, -1)}</mark>
                 : part
         )
     }
 
+    const selectResult = async (result) => {
+        setSearchActive(false)
+        setSearchResults([])
+        setOpen(false)
+        try {
+            const req = await GetRequest(result.requestId)
+            if (req && req.id) {
+                onSelect(req)
+            }
+        } catch (error) {
+            console.error("Failed to open request", error)
+        }
+    }
+
 
     return (
         <CommandDialog open={open} onOpenChange={setOpen}>
 
                 <Command shouldFilter={false}>
                     <CommandInput
-                        onValueChange={searchRequests}
-                        placeholder="Start typing to search."
+                        onValueChange={(term) => searchRequests(term)}
+                        placeholder='Search. Use "quotes" for phrases, word* for prefixes, and filters like method:POST status:5xx in:"Billing" has:auth.'
                     />
+                    <label className="flex items-center gap-2 px-3 py-2 text-xs text-gray-400">
+                        <input
+                            type="checkbox"
+                            checked={includeResponses}
+                            onChange={(e) => {
+                                setIncludeResponses(e.target.checked)
+                                searchRequests(searchTerm, e.target.checked)
+                            }}
+                        />
+                        Include response history
+                    </label>
                     <CommandList>
+                        {searchError && (
+                            <div className="px-3 py-2 text-xs text-red-400">{searchError}</div>
+                        )}
                         {searchActive && searchResults.length === 0 && (
                             <CommandEmpty>No results found.</CommandEmpty>
                         )}
                         <CommandGroup heading="Suggestions">
-                            {searchResults.map(req => (
+                            {searchResults.map(result => (
                                 <CommandItem
-                                    key={req.id}
-                                    onSelect={() => {
-                                        onSelect(req)
-                                        setSearchActive(false)
-                                        setSearchResults([])
-                                        setOpen(false)
-                                    }}
+                                    key={`${result.kind}-${result.responseId || result.requestId}`}
+                                    onSelect={() => selectResult(result)}
                                     className="flex flex-col items-start gap-1 py-2"
                                 >
                                     <div className="flex items-center gap-2">
-                                        <span className={`text-sm font-medium ${methodColourMap.get(req.method)}`}>{req.method}
-                                        </span><span className="text-xs text-gray-500">{highlightMatch(req.name || '', searchTerm)}</span>
+                                        <span className={`text-sm font-medium ${methodColourMap.get(result.method)}`}>{result.method}
+                                        </span><span className="text-xs text-gray-500">{result.name}</span>
+                                        {result.kind === "response" && (
+                                            <span className="text-xs text-gray-500 italic">response</span>
+                                        )}
+                                    </div>
+                                    <div className="text-sm font-semibold">{result.url}</div>
+                                    <div className="text-xs text-gray-400 truncate w-full">
+                                        {renderSnippet(result.snippet)}
                                     </div>
-                                    <div className="text-sm font-semibold">{highlightMatch(req.url || '', searchTerm)}</div>
-                                    {req.description && (
-                                        <div className="text-xs text-gray-500 italic">{highlightMatch(req.description, searchTerm)}</div>
-                                    )}
-                                    {req.body && searchTerm && req.body.toLowerCase().includes(searchTerm.toLowerCase()) && (
-                                        <div className="text-xs text-gray-400 truncate w-full">
-                                            {highlightMatch(req.body, searchTerm)}
-                                        </div>
-                                    )}
                                     <CommandShortcut>Enter</CommandShortcut>
                                 </CommandItem>
                             ))}
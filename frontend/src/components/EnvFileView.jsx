@@ -13,6 +13,9 @@ export default function EnvFileView({ filename: initialFilename, isNew = false,
     const [error, setError] = useState(null);
     const [saving, setSaving] = useState(false);
     const setEnvironmentVariables = useEnvarStore((state) => state.setEnvironmentVariables);
+    const warnings = useEnvarStore(
+        (state) => state.environmentVariables.find((e) => e.env === filename)?.warnings
+    ) || [];
 
     useEffect(() => {
         if (!isNew) {
@@ -101,6 +104,13 @@ export default function EnvFileView({ filename: initialFilename, isNew = false,
                             onChange={(value) => setContent(value)}
                         />
                     </div>
+                    {warnings.length > 0 && (
+                        <ul className="shrink-0 text-sm text-yellow-500">
+                            {warnings.map((w, i) => (
+                                <li key={i}>{w.line ? `Line ${w.line}: ` : ''}{w.message}</li>
+                            ))}
+                        </ul>
+                    )}
                 </div>
             </div>
 
@@ -3,7 +3,8 @@ import {
     CreateCollection,
     SetRequestCollection,
     UpdateCollectionParent,
-    SetRequestSortOrder
+    SetRequestSortOrder,
+    SetCollectionSortOrder
 } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js";
 import { ImportPostmanCollection } from "../../bindings/github.com/D-Elbel/curlew/fileservice.js";
 import { Button } from "@/components/ui/button";
@@ -50,19 +51,48 @@ import {
     Upload,
     FileText,
     Copy,
+    Star,
+    Tag,
+    Undo2,
+    Redo2,
+    ListChecks,
+    SlidersHorizontal,
 } from "lucide-react";
 import hotkeys from "hotkeys-js";
 import { useHotkeys } from "@/services/HotkeysContext.jsx";
 import { useRequestStore } from "@/stores/requestStore.js";
 import { methodColourMap} from "@/utils/constants.js";
 import { buildCollectionTree } from "@/utils/collections.js";
+import TrashModal from "@/components/TrashModal.jsx";
+import BulkEditModal from "@/components/BulkEditModal.jsx";
+import CollectionDefaultsModal from "@/components/CollectionDefaultsModal.jsx";
 
 const validUUIDRegex =
     /^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$/;
 
+// Returns the edited tag list, or null when the prompt was cancelled.
+const promptForTags = (name, tags) => {
+    const value = window.prompt(`Tags for "${name}" (comma separated)`, (tags || []).join(", "));
+    if (value === null) {
+        return null;
+    }
+    return value.split(",").map((tag) => tag.trim()).filter(Boolean);
+};
+
+const TagChips = ({ tags }) =>
+    (tags || []).map((tag) => (
+        <span
+            key={tag}
+            className="ml-1 px-1 rounded bg-slate-700 text-[10px] text-slate-300 flex-shrink-0"
+        >
+            {tag}
+        </span>
+    ));
 
 
 const DraggableRequest = ({ req, onDelete, onDuplicate, onRequestSelect, activeDragId, index }) => {
+    const toggleRequestFavorite = useRequestStore((state) => state.toggleRequestFavorite);
+    const setRequestTags = useRequestStore((state) => state.setRequestTags);
     const {
         attributes,
         listeners,
@@ -113,9 +143,28 @@ const DraggableRequest = ({ req, onDelete, onDuplicate, onRequestSelect, activeD
                     <span className="truncate text-slate-200">
                         {req.name || req.url || "Untitled Request"}
                     </span>
+                    <TagChips tags={req.tags} />
+                    {req.favorite && <Star className="w-3 h-3 ml-1 text-yellow-400 fill-yellow-400 flex-shrink-0" />}
                 </div>
             </div>
             <div className="opacity-0 group-hover:opacity-100 flex items-center">
+                <Star
+                    onClick={(e) => {
+                        e.stopPropagation();
+                        toggleRequestFavorite(req).catch(console.error);
+                    }}
+                    className="w-3 h-3 ml-1 text-slate-400 hover:text-yellow-400 cursor-pointer"
+                />
+                <Tag
+                    onClick={(e) => {
+                        e.stopPropagation();
+                        const tags = promptForTags(req.name || req.url, req.tags);
+                        if (tags) {
+                            setRequestTags(req.id, tags).catch(console.error);
+                        }
+                    }}
+                    className="w-3 h-3 ml-1 text-slate-400 hover:text-slate-200 cursor-pointer"
+                />
                 <Copy
                     onClick={(e) => {
                         e.stopPropagation();
@@ -163,16 +212,43 @@ const RequestDropZone = ({ collectionId, index, isLast = false }) => {
     );
 };
 
+// Drop target between sibling collections; parentId is null at the top level.
+const CollectionDropZone = ({ parentId, index }) => {
+    const { isOver, setNodeRef } = useDroppable({
+        id: `collection-drop-zone-${parentId ?? "root"}-${index}`,
+        data: {
+            type: "collection-drop-zone",
+            parentId,
+            targetIndex: index
+        }
+    });
+
+    return (
+        <div
+            ref={setNodeRef}
+            className={`h-1 transition-all duration-200 ${
+                isOver ? "h-2 bg-blue-500/50 rounded" : ""
+            }`}
+        />
+    );
+};
+
 const CollectionItem = ({
     collection,
     allRequests,
+    index = 0,
     level = 0,
     onDeleteCollection,
+    onDuplicateCollection,
+    onBulkEdit,
+    onEditDefaults,
     onDeleteRequest,
     onDuplicateRequest,
     onRequestSelect,
     activeDragId,
 }) => {
+    const toggleCollectionFavorite = useRequestStore((state) => state.toggleCollectionFavorite);
+    const setCollectionTags = useRequestStore((state) => state.setCollectionTags);
     const { isOver, setNodeRef: droppableRef } = useDroppable({
         id: collection.id,
         data: { type: "collection" }
@@ -185,7 +261,13 @@ const CollectionItem = ({
         setActivatorNodeRef,
     } = useDraggable({
         id: `collection-${collection.id}`,
-        data: { type: "collection", name: collection.name, collectionId: collection.id },
+        data: {
+            type: "collection",
+            name: collection.name,
+            collectionId: collection.id,
+            parentId: collection.parentCollectionId ?? null,
+            index,
+        },
     });
 
     const [isOpen, setIsOpen] = useState(true);
@@ -245,9 +327,51 @@ const CollectionItem = ({
                             <span className="font-medium text-slate-200 truncate">
                                 {collection.name}
                             </span>
+                            <TagChips tags={collection.tags} />
+                            {collection.favorite && (
+                                <Star className="w-3 h-3 ml-1 text-yellow-400 fill-yellow-400 flex-shrink-0" />
+                            )}
                         </div>
                     </CollapsibleTrigger>
                     <div className="opacity-0 group-hover:opacity-100 flex items-center">
+                        <Star
+                            onClick={(e) => {
+                                e.stopPropagation();
+                                toggleCollectionFavorite(collection).catch(console.error);
+                            }}
+                            className="w-3 h-3 ml-1 text-slate-400 hover:text-yellow-400 cursor-pointer"
+                        />
+                        <Tag
+                            onClick={(e) => {
+                                e.stopPropagation();
+                                const tags = promptForTags(collection.name, collection.tags);
+                                if (tags) {
+                                    setCollectionTags(collection.id, tags).catch(console.error);
+                                }
+                            }}
+                            className="w-3 h-3 ml-1 text-slate-400 hover:text-slate-200 cursor-pointer"
+                        />
+                        <Copy
+                            onClick={(e) => {
+                                e.stopPropagation();
+                                onDuplicateCollection?.(collection);
+                            }}
+                            className="w-3 h-3 ml-1 text-slate-400 hover:text-slate-200 cursor-pointer"
+                        />
+                        <ListChecks
+                            onClick={(e) => {
+                                e.stopPropagation();
+                                onBulkEdit?.(collection);
+                            }}
+                            className="w-3 h-3 ml-1 text-slate-400 hover:text-slate-200 cursor-pointer"
+                        />
+                        <SlidersHorizontal
+                            onClick={(e) => {
+                                e.stopPropagation();
+                                onEditDefaults?.(collection);
+                            }}
+                            className="w-3 h-3 ml-1 text-slate-400 hover:text-slate-200 cursor-pointer"
+                        />
                         <Trash2
                             onClick={(e) => {
                                 e.stopPropagation();
@@ -267,19 +391,28 @@ const CollectionItem = ({
                 </div>
                 <CollapsibleContent className="space-y-0">
                     <div style={{ paddingLeft: "16px" }}>
-                        {collection.children?.map((child) => (
-                            <CollectionItem
-                                key={child.id}
-                                collection={child}
-                                allRequests={allRequests}
-                                level={level + 1}
-                                onDeleteCollection={onDeleteCollection}
-                                onDeleteRequest={onDeleteRequest}
-                                onDuplicateRequest={onDuplicateRequest}
-                                onRequestSelect={onRequestSelect}
-                                activeDragId={activeDragId}
-                            />
+                        {collection.children?.map((child, childIndex) => (
+                            <React.Fragment key={child.id}>
+                                <CollectionDropZone parentId={collection.id} index={childIndex} />
+                                <CollectionItem
+                                    collection={child}
+                                    allRequests={allRequests}
+                                    index={childIndex}
+                                    level={level + 1}
+                                    onDeleteCollection={onDeleteCollection}
+                                    onDuplicateCollection={onDuplicateCollection}
+                                    onBulkEdit={onBulkEdit}
+                                    onEditDefaults={onEditDefaults}
+                                    onDeleteRequest={onDeleteRequest}
+                                    onDuplicateRequest={onDuplicateRequest}
+                                    onRequestSelect={onRequestSelect}
+                                    activeDragId={activeDragId}
+                                />
+                            </React.Fragment>
                         ))}
+                        {collection.children?.length > 0 && (
+                            <CollectionDropZone parentId={collection.id} index={collection.children.length} />
+                        )}
 
                         {/* Render requests with drop zones */}
                         {requestsInThisCollection.length > 0 && (
@@ -528,12 +661,32 @@ const ImportModal = ({ isOpen, onClose, onImport }) => {
         </Dialog>    );
 };
 
-const Toolbar = ({ onNewCollection, onRefresh, onImport }) => (
+const Toolbar = ({ onNewCollection, onRefresh, onImport, onTrash, journal, onUndo, onRedo }) => (
     <div className="flex items-center justify-between px-3 py-1.5 border-b ">
         <div className="flex items-center space-x-1">
             <span className="text-sm font-medium ">Collections</span>
         </div>
         <div className="flex items-center space-x-1">
+            <Button
+                size="sm"
+                variant="ghost"
+                className="h-6 w-6 p-0 hover:bg-slate-700"
+                onClick={onUndo}
+                disabled={!journal?.undo}
+                title={journal?.undo ? `Undo ${journal.undo.description.toLowerCase()}` : "Nothing to undo"}
+            >
+                <Undo2 className="w-3 h-3" />
+            </Button>
+            <Button
+                size="sm"
+                variant="ghost"
+                className="h-6 w-6 p-0 hover:bg-slate-700"
+                onClick={onRedo}
+                disabled={!journal?.redo}
+                title={journal?.redo ? `Redo ${journal.redo.description.toLowerCase()}` : "Nothing to redo"}
+            >
+                <Redo2 className="w-3 h-3" />
+            </Button>
             <Button
                 size="sm"
                 variant="ghost"
@@ -561,6 +714,15 @@ const Toolbar = ({ onNewCollection, onRefresh, onImport }) => (
             >
                 <Settings className="w-3 h-3" />
             </Button>
+            <Button
+                size="sm"
+                variant="ghost"
+                className="h-6 w-6 p-0 hover:bg-slate-700"
+                onClick={onTrash}
+                title="Trash"
+            >
+                <Trash2 className="w-3 h-3" />
+            </Button>
         </div>
     </div>
 );
@@ -577,6 +739,34 @@ const SearchBar = ({ value, onChange }) => (
     </div>
 );
 
+// TagFilter narrows the request list to requests carrying every selected tag.
+const TagFilter = ({ tags, selected, onChange }) => {
+    if (!tags.length) {
+        return null;
+    }
+    const toggle = (name) =>
+        onChange(selected.includes(name) ? selected.filter((t) => t !== name) : [...selected, name]);
+
+    return (
+        <div className="flex flex-wrap items-center gap-1 px-3 pb-2">
+            <Tag className="w-3 h-3 text-slate-400" />
+            {tags.map((tag) => (
+                <button
+                    key={tag.name}
+                    onClick={() => toggle(tag.name)}
+                    className={`px-1.5 rounded text-[10px] ${
+                        selected.includes(tag.name)
+                            ? "bg-blue-600 text-white"
+                            : "bg-slate-700 text-slate-300 hover:bg-slate-600"
+                    }`}
+                >
+                    {tag.name}
+                </button>
+            ))}
+        </div>
+    );
+};
+
 function Sidebar({ selectedTab, setSelectedTab, collapsed, setCollapsed }) {
     const { hotkeysMap } = useHotkeys();
     const openSidebarHotkey = hotkeysMap.OPEN_SIDEBAR;
@@ -635,15 +825,27 @@ export default function RequestListSidebar({
     const deleteRequest = useRequestStore((state) => state.deleteRequest);
     const deleteCollection = useRequestStore((state) => state.deleteCollection);
     const duplicateRequest = useRequestStore((state) => state.duplicateRequest);
+    const duplicateCollection = useRequestStore((state) => state.duplicateCollection);
+    const tags = useRequestStore((state) => state.tags);
+    const tagFilter = useRequestStore((state) => state.tagFilter);
+    const setTagFilter = useRequestStore((state) => state.setTagFilter);
+    const journal = useRequestStore((state) => state.journal);
+    const undo = useRequestStore((state) => state.undo);
+    const redo = useRequestStore((state) => state.redo);
+    const { hotkeysMap } = useHotkeys();
 
     const [isDialogOpen, setDialogOpen] = useState(false);
     const [isImportOpen, setImportOpen] = useState(false);
+    const [isTrashOpen, setTrashOpen] = useState(false);
+    const [bulkEditCollection, setBulkEditCollection] = useState(null);
+    const [defaultsCollection, setDefaultsCollection] = useState(null);
     const [newCollectionName, setNewCollectionName] = useState("");
     const [activeDragId, setActiveDragId] = useState(null);
     const [activeDragItem, setActiveDragItem] = useState(null);
     const [collectionSearch, setCollectionSearch] = useState("");
     const [isNewFileOpen, setIsNewFileOpen] = useState(false);
     const [newFileName, setNewFileName] = useState("");
+    const [collectionToDelete, setCollectionToDelete] = useState(null);
 
     useEffect(() => {
         if (selectedTab === "collections") {
@@ -651,6 +853,28 @@ export default function RequestListSidebar({
         }
     }, [selectedTab, loadAll]);
 
+    // Text fields keep their own undo; the journal hotkeys only apply elsewhere.
+    useEffect(() => {
+        const bind = (combo, action) => {
+            const fn = (e) => {
+                const target = e.target;
+                if (target?.isContentEditable || ["INPUT", "TEXTAREA", "SELECT"].includes(target?.tagName)) {
+                    return;
+                }
+                e.preventDefault();
+                action().catch((error) => console.error("Failed to undo or redo:", error));
+            };
+            hotkeys(combo, fn);
+            return () => hotkeys.unbind(combo, fn);
+        };
+        const unbindUndo = bind(hotkeysMap.UNDO, undo);
+        const unbindRedo = bind(hotkeysMap.REDO, redo);
+        return () => {
+            unbindUndo();
+            unbindRedo();
+        };
+    }, [hotkeysMap.UNDO, hotkeysMap.REDO, undo, redo]);
+
     const envs = useEnvarStore((state) => state.environmentVariables);
     const sensors = useSensors(useSensor(PointerSensor));
 
@@ -693,6 +917,21 @@ export default function RequestListSidebar({
                     }
                 }
             }
+        } else if (draggedItemType === "collection" && targetData?.type === "collection-drop-zone") {
+            const { collectionId, parentId, index } = active.data.current;
+            let targetIndex = targetData.targetIndex;
+            try {
+                if (targetData.parentId !== parentId) {
+                    await UpdateCollectionParent(collectionId, targetData.parentId);
+                } else if (index < targetIndex) {
+                    // The zones are numbered with the dragged collection still in the list.
+                    targetIndex--;
+                }
+                await SetCollectionSortOrder(collectionId, targetIndex);
+                await loadAll();
+            } catch (error) {
+                console.error("Failed to reorder collection:", error);
+            }
         } else if (draggedItemType === "collection") {
             const collectionId = active.data.current.collectionId;
             const newParentId = over.id === "__UNCATEGORIZED__" ? null : over.id;
@@ -754,13 +993,33 @@ export default function RequestListSidebar({
         }
     };
 
-    const handleDeleteCollection = async (id, name) => {
-        if (
-            window.confirm(
-                `Delete collection "${name}"? This will orphan its requests and sub-collections.`,
-            )
-        ) {
-            await deleteCollection(id);
+    // The copy is placed next to the original.
+    const handleDuplicateCollection = async (collection) => {
+        const options = {
+            variables: window.confirm(`Copy the variables of "${collection.name}" and its sub-collections too?`),
+            latestResponse: window.confirm("Copy the latest response of each request too?"),
+        };
+        try {
+            await duplicateCollection(collection.id, collection.parentCollectionId ?? null, options);
+        } catch (error) {
+            console.error("Failed to duplicate collection:", error);
+            window.alert(`Failed to duplicate collection: ${error}`);
+        }
+    };
+
+    const handleDeleteCollection = (id, name) => {
+        setCollectionToDelete({ id, name });
+    };
+
+    const confirmDeleteCollection = async (mode) => {
+        const { id } = collectionToDelete;
+        setCollectionToDelete(null);
+        try {
+            const summary = await deleteCollection(id, mode);
+            console.log("Deleted collection", summary);
+        } catch (error) {
+            console.error("Failed to delete collection:", error);
+            window.alert(`Failed to delete collection: ${error}`);
         }
     };
 
@@ -776,11 +1035,20 @@ export default function RequestListSidebar({
                     onNewCollection={() => setDialogOpen(true)}
                     onRefresh={loadAll}
                     onImport={() => setImportOpen(true)}
+                    onTrash={() => setTrashOpen(true)}
+                    journal={journal}
+                    onUndo={() => undo().catch(console.error)}
+                    onRedo={() => redo().catch(console.error)}
                 />
                 <SearchBar
                     value={collectionSearch}
                     onChange={setCollectionSearch}
                 />
+                <TagFilter
+                    tags={tags}
+                    selected={tagFilter}
+                    onChange={(selected) => setTagFilter(selected).catch(console.error)}
+                />
                 <div className="flex-1 overflow-auto px-1">
                     <DndContext
                         sensors={sensors}
@@ -789,18 +1057,27 @@ export default function RequestListSidebar({
                         onDragEnd={handleDragEnd}
                     >
                         <div className="space-y-0">
-                            {collectionTree.map((collection) => (
-                                <CollectionItem
-                                    key={collection.id}
-                                    collection={collection}
-                                    allRequests={requests}
-                                    onDeleteCollection={handleDeleteCollection}
-                                    onDeleteRequest={handleDeleteRequest}
-                                    onDuplicateRequest={handleDuplicateRequest}
-                                    onRequestSelect={onRequestSelect}
-                                    activeDragId={activeDragId}
-                                />
+                            {collectionTree.map((collection, index) => (
+                                <React.Fragment key={collection.id}>
+                                    <CollectionDropZone parentId={null} index={index} />
+                                    <CollectionItem
+                                        collection={collection}
+                                        allRequests={requests}
+                                        index={index}
+                                        onDeleteCollection={handleDeleteCollection}
+                                        onDuplicateCollection={handleDuplicateCollection}
+                                        onBulkEdit={setBulkEditCollection}
+                                        onEditDefaults={setDefaultsCollection}
+                                        onDeleteRequest={handleDeleteRequest}
+                                        onDuplicateRequest={handleDuplicateRequest}
+                                        onRequestSelect={onRequestSelect}
+                                        activeDragId={activeDragId}
+                                    />
+                                </React.Fragment>
                             ))}
+                            {collectionTree.length > 0 && (
+                                <CollectionDropZone parentId={null} index={collectionTree.length} />
+                            )}
                             {uncategorizedRequests.length > 0 && (
                                 <UncategorizedDroppable
                                     requests={uncategorizedRequests}
@@ -846,12 +1123,52 @@ export default function RequestListSidebar({
                     </DialogContent>
                 </Dialog>
 
+                {/* Delete Collection Dialog */}
+                <Dialog
+                    open={!!collectionToDelete}
+                    onOpenChange={(open) => !open && setCollectionToDelete(null)}
+                >
+                    <DialogContent className="bg-slate-800 border-slate-600">
+                        <DialogHeader>
+                            <DialogTitle className="text-slate-200">
+                                Delete collection "{collectionToDelete?.name}"?
+                            </DialogTitle>
+                        </DialogHeader>
+                        <p className="text-sm text-slate-300">
+                            Delete everything moves its sub-collections and requests to the trash with it.
+                            Keep contents moves them up to the parent collection first.
+                        </p>
+                        <DialogFooter>
+                            <Button variant="outline" onClick={() => setCollectionToDelete(null)}>
+                                Cancel
+                            </Button>
+                            <Button variant="outline" onClick={() => confirmDeleteCollection("reparent")}>
+                                Keep contents
+                            </Button>
+                            <Button variant="destructive" onClick={() => confirmDeleteCollection("cascade")}>
+                                Delete everything
+                            </Button>
+                        </DialogFooter>
+                    </DialogContent>
+                </Dialog>
+
                 {/* Import Collection Modal */}
                 <ImportModal
                     isOpen={isImportOpen}
                     onClose={() => setImportOpen(false)}
                     onImport={handleImportCollection}
                 />
+                <TrashModal open={isTrashOpen} onOpenChange={setTrashOpen} />
+                <BulkEditModal
+                    open={bulkEditCollection !== null}
+                    onOpenChange={(open) => !open && setBulkEditCollection(null)}
+                    collection={bulkEditCollection}
+                />
+                <CollectionDefaultsModal
+                    open={defaultsCollection !== null}
+                    onOpenChange={(open) => !open && setDefaultsCollection(null)}
+                    collection={defaultsCollection}
+                />
             </div>
         );
     };
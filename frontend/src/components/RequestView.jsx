@@ -5,7 +5,7 @@ import { json } from "@codemirror/lang-json";
 import { html } from "@codemirror/lang-html";
 import { xml } from "@codemirror/lang-xml";
 import { javascript } from "@codemirror/lang-javascript";
-import { ExecuteRequest, GetRequest, GetResponseHistory } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js";
+import { DiffResponses, ExecuteRequest, GetRequest, GetRequestParams, GetRequestPathVariables, GetRequestScripts, GetResponseHistory, GetResponseSnapshot, PinResponse, RerunResponse, ResolveInheritedDefaults, SetRequestParams, SetRequestPathVariables, SetRequestScripts, UnpinResponse } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js";
 import { copilot } from "@uiw/codemirror-theme-copilot"
 import { Input } from "@/components/ui/input.js";
 import { EnvarSupportedInput } from "@/components/EnvarSupportedInput.jsx";
@@ -22,28 +22,32 @@ import { buildCollectionTree } from "../utils/collections.js"
 
 import { useEnvarStore } from "@/stores/envarStore";
 
-const applyEnvVars = (text, envs, activeEnv) => {
-    if (!text || typeof text !== 'string') {
-        return text;
-    }
-
-    const envFile = envs.find(e => e.env === activeEnv);
-    if (!envFile || !envFile.variables) {
-        return text;
-    }
-
-    return text.replace(/\{\{(.*?)\}\}/g, (match, key) => {
-        if (Object.prototype.hasOwnProperty.call(envFile.variables, key)) {
-            return envFile.variables[key];
-        }
-        console.warn(
-            `Environment variable '{{${key}}}' not found in active environment ` +
-            `'${activeEnv}'.`
-        );
-        return match;
-    });
+// Stored as the auth of a request that sends no Authorization header; an empty auth inherits.
+const AUTH_NONE = "noauth";
+
+// Returns the :name path segments of a URL, ignoring the scheme, host and port.
+const pathVariableNames = (value) => {
+    const withoutQuery = (value || "").split(/[?#]/)[0];
+    const afterScheme = withoutQuery.includes("://")
+        ? withoutQuery.slice(withoutQuery.indexOf("://") + 3)
+        : withoutQuery;
+    const slash = afterScheme.indexOf("/");
+    if (slash === -1) return [];
+    const names = afterScheme
+        .slice(slash)
+        .split("/")
+        .map((segment) => segment.match(/^:([A-Za-z_][A-Za-z0-9_-]*)$/)?.[1])
+        .filter(Boolean);
+    return [...new Set(names)];
 };
 
+// Encodes a query component while keeping {{variable}} placeholders intact for the backend.
+const encodeQueryPart = (value) =>
+    value
+        .split(/(\{\{.*?\}\})/g)
+        .map((part) => (part.startsWith("{{") ? part : encodeURIComponent(part)))
+        .join("");
+
 const CollectionItem = ({
                             collection,
                             level = 0,
@@ -120,6 +124,9 @@ function RequestView({ request }) {
     const [fullRequest, setFullRequest] = useState(null);
     const [responseTab, setResponseTab] = useState("body");
     const [responseHistory, setResponseHistory] = useState([]);
+    const [compareSelection, setCompareSelection] = useState([]);
+    const [responseDiff, setResponseDiff] = useState(null);
+    const [viewedSnapshot, setViewedSnapshot] = useState(null);
     const [isLoading, setIsLoading] = useState(false);
     const [errorMessage, setErrorMessage] = useState("");
     const [authType, setAuthType] = useState("none");
@@ -129,9 +136,14 @@ function RequestView({ request }) {
     const [apiKeyKey, setApiKeyKey] = useState("");
     const [apiKeyValue, setApiKeyValue] = useState("");
     const [apiKeyAddTo, setApiKeyAddTo] = useState("headers");
+    const [preRequestScript, setPreRequestScript] = useState("");
+    const [postResponseScript, setPostResponseScript] = useState("");
+    const scriptsSaveTimeout = useRef(null);
+    const [params, setParams] = useState([]);
+    const [pathVariables, setPathVariables] = useState([]);
+    const [inheritedDefaults, setInheritedDefaults] = useState(null);
 
     const collections = useRequestStore((state) => state.collections);
-    const envs = useEnvarStore((state) => state.environmentVariables);
     const activeEnv = useEnvarStore((state) => state.activeEnvironment);
     const isInitialAutosave = useRef(true);
     const saveTimeout = useRef(null);
@@ -141,6 +153,70 @@ function RequestView({ request }) {
         : typeof request?.id === "number"
             ? request.id
             : null;
+    const togglePinned = async (entry) => {
+        try {
+            if (entry.pinned) {
+                await UnpinResponse(entry.id);
+            } else {
+                await PinResponse(entry.id);
+            }
+            await loadResponseHistory(resolvedRequestId);
+        } catch (error) {
+            console.error("Failed to update pinned response:", error);
+        }
+    };
+
+    const viewSnapshot = async (id) => {
+        if (viewedSnapshot?.responseId === id) {
+            setViewedSnapshot(null);
+            return;
+        }
+        try {
+            const snapshot = await GetResponseSnapshot(id);
+            setViewedSnapshot({ responseId: id, ...snapshot });
+        } catch (error) {
+            setViewedSnapshot({ responseId: id, error: error.toString() });
+        }
+    };
+
+    const rerunSnapshot = async (id) => {
+        setIsLoading(true);
+        setErrorMessage("");
+        try {
+            await handleResponse(await RerunResponse(id));
+            await loadResponseHistory(resolvedRequestId);
+        } catch (error) {
+            console.error("Error re-running request:", error);
+            setErrorMessage(error.toString());
+        } finally {
+            setIsLoading(false);
+        }
+    };
+
+    const toggleCompare = (id) => {
+        setResponseDiff(null);
+        setCompareSelection((prev) => {
+            if (prev.includes(id)) {
+                return prev.filter((existing) => existing !== id);
+            }
+            return [...prev, id].slice(-2);
+        });
+    };
+
+    const compareResponses = async () => {
+        if (compareSelection.length !== 2) {
+            return;
+        }
+        // Diff from the older response to the newer one.
+        const [a, b] = [...compareSelection].sort((x, y) => x - y);
+        try {
+            setResponseDiff(await DiffResponses(a, b));
+        } catch (error) {
+            console.error("Failed to diff responses:", error);
+            setResponseDiff(null);
+        }
+    };
+
     const latestResponse = responseData || (responseHistory.length > 0 ? responseHistory[0] : null);
     const latestResponseSizeKb =
         latestResponse && typeof latestResponse.body === "string"
@@ -186,7 +262,92 @@ function RequestView({ request }) {
         })();
     }, [request?.id, request.isNew]);
 
+    // Headers and auth set on the enclosing collections, which the backend adds when sending.
+    useEffect(() => {
+        const collectionId = fullRequest?.collectionId;
+        if (!collectionId) {
+            setInheritedDefaults(null);
+            return;
+        }
+        ResolveInheritedDefaults(collectionId)
+            .then(setInheritedDefaults)
+            .catch((e) => console.error("Failed to resolve inherited defaults", e));
+    }, [fullRequest?.collectionId]);
+
+    useEffect(() => {
+        if (!resolvedRequestId) {
+            setPreRequestScript("");
+            setPostResponseScript("");
+            return;
+        }
+        (async () => {
+            try {
+                const scripts = await GetRequestScripts(resolvedRequestId);
+                setPreRequestScript(scripts?.preRequest || "");
+                setPostResponseScript(scripts?.postResponse || "");
+            } catch (error) {
+                console.error("Failed to load request scripts:", error);
+            }
+        })();
+    }, [resolvedRequestId]);
+
     useEffect(() => {
+        if (activeTab !== "params" || !resolvedRequestId) return;
+        (async () => {
+            try {
+                setParams((await GetRequestParams(resolvedRequestId)) || []);
+                setPathVariables((await GetRequestPathVariables(resolvedRequestId)) || []);
+            } catch (error) {
+                console.error("Failed to load request params:", error);
+            }
+        })();
+    }, [activeTab, resolvedRequestId]);
+
+    const pathVariableRows = pathVariableNames(url).map(
+        (key) =>
+            pathVariables.find((v) => v.key === key) || { key, value: "", description: "" }
+    );
+
+    const updatePathVariable = (key, patch) =>
+        pathVariableRows.map((row) => (row.key === key ? { ...row, ...patch } : row));
+
+    const commitPathVariables = async (next) => {
+        setPathVariables(next);
+        if (!resolvedRequestId) return;
+        try {
+            await SetRequestPathVariables(resolvedRequestId, next);
+        } catch (error) {
+            console.error("Failed to save path variables:", error);
+        }
+    };
+
+    const commitParams = async (next) => {
+        setParams(next);
+        if (!resolvedRequestId) return;
+        try {
+            const updatedUrl = await SetRequestParams(resolvedRequestId, next);
+            setUrl(updatedUrl);
+        } catch (error) {
+            console.error("Failed to save request params:", error);
+        }
+    };
+
+    const updateScripts = (preRequest, postResponse) => {
+        setPreRequestScript(preRequest);
+        setPostResponseScript(postResponse);
+        if (!resolvedRequestId) return;
+        if (scriptsSaveTimeout.current) clearTimeout(scriptsSaveTimeout.current);
+        scriptsSaveTimeout.current = setTimeout(() => {
+            SetRequestScripts(resolvedRequestId, { preRequest, postResponse }).catch((error) =>
+                console.error("Failed to save request scripts:", error)
+            );
+        }, 500);
+    };
+
+    useEffect(() => {
+        setCompareSelection([]);
+        setResponseDiff(null);
+        setViewedSnapshot(null);
         if (resolvedRequestId) {
             loadResponseHistory(resolvedRequestId);
         } else {
@@ -499,6 +660,21 @@ function RequestView({ request }) {
         }
     };
 
+    // Key/value headers are stored as a list so disabled entries and descriptions survive.
+    const buildHeadersForPersist = () => {
+        if (headerType !== "keyvalue") return headersRaw;
+        return JSON.stringify(
+            (headersKV || [])
+                .filter((h) => h.key || h.value)
+                .map((h) => ({
+                    key: h.key,
+                    value: h.value,
+                    enabled: h.enabled !== false,
+                    description: h.description || "",
+                }))
+        );
+    };
+
     const handleSaveRequest = async () => {
         setIsLoading(true);
         try {
@@ -524,7 +700,7 @@ function RequestView({ request }) {
                 description: description,
                 method,
                 requestUrl: url,
-                headers: headersRaw,
+                headers: buildHeadersForPersist(),
                 body: bodyPersist,
                 bodyType: bodyType,
                 bodyFormat: bodyFormat,
@@ -566,7 +742,7 @@ function RequestView({ request }) {
                 description: description,
                 method,
                 requestUrl: url,
-                headers: headersRaw,
+                headers: buildHeadersForPersist(),
                 body: bodyPersist,
                 bodyType: bodyType,
                 bodyFormat: bodyFormat,
@@ -648,7 +824,7 @@ function RequestView({ request }) {
         } else if (headerType === "keyvalue") {
             headersObj = {};
             headersKV.forEach((h) => {
-                if (h.key && h.value) headersObj[h.key] = h.value;
+                if (h.key && h.value && h.enabled !== false) headersObj[h.key] = h.value;
             });
         }
 
@@ -679,16 +855,17 @@ function RequestView({ request }) {
         setErrorMessage("");
         setResponseData(null);
 
-        let finalUrl = applyEnvVars(url, envs, activeEnv);
+        // Variables are resolved by the backend so request, collection and environment
+        // scopes are applied in one place with the right precedence.
+        let finalUrl = url;
         if (
             authType === "apikey" &&
             apiKeyAddTo === "query" &&
             apiKeyKey &&
             apiKeyValue
         ) {
-            const urlObj = new URL(finalUrl);
-            urlObj.searchParams.set(apiKeyKey, apiKeyValue);
-            finalUrl = urlObj.toString();
+            const separator = finalUrl.includes("?") ? "&" : "?";
+            finalUrl = `${finalUrl}${separator}${encodeQueryPart(apiKeyKey)}=${encodeQueryPart(apiKeyValue)}`;
         }
 
         let finalHeaders = "";
@@ -763,7 +940,8 @@ function RequestView({ request }) {
                 finalBody,
                 bodyType,
                 bodyFormat,
-                auth
+                auth,
+                activeEnv || ""
             );
             await handleResponse(result);
             if (resolvedRequestId) {
@@ -820,7 +998,7 @@ function RequestView({ request }) {
         }
     };
 
-    const renderKeyValueTable = (dataArray, setDataArray) => {
+    const renderKeyValueTable = (dataArray, setDataArray, withToggles = false) => {
         const handleChange = (index, field, value) => {
             const newData = [...dataArray];
             newData[index][field] = value;
@@ -838,18 +1016,37 @@ function RequestView({ request }) {
                 <table className="w-full text-sm mb-2">
                     <thead>
                     <tr>
+                        {withToggles && (
+                            <th className="border-b border-gray-700 p-2"></th>
+                        )}
                         <th className="border-b border-gray-700 p-2 text-left">
                             Key
                         </th>
                         <th className="border-b border-gray-700 p-2 text-left">
                             Value
                         </th>
+                        {withToggles && (
+                            <th className="border-b border-gray-700 p-2 text-left">
+                                Description
+                            </th>
+                        )}
                         <th className="border-b border-gray-700 p-2"></th>
                     </tr>
                     </thead>
                     <tbody>
                     {dataArray.map((row, i) => (
-                        <tr key={i}>
+                        <tr key={i} className={withToggles && row.enabled === false ? "opacity-50" : ""}>
+                            {withToggles && (
+                                <td className="border-b border-gray-700 p-2">
+                                    <input
+                                        type="checkbox"
+                                        checked={row.enabled !== false}
+                                        onChange={(e) =>
+                                            handleChange(i, "enabled", e.target.checked)
+                                        }
+                                    />
+                                </td>
+                            )}
                             <td className="border-b border-gray-700 p-2">
                                 <Input
                                     type="text"
@@ -870,6 +1067,18 @@ function RequestView({ request }) {
                                     className="bg-gray-800 border-gray-700 text-white"
                                 />
                             </td>
+                            {withToggles && (
+                                <td className="border-b border-gray-700 p-2">
+                                    <Input
+                                        type="text"
+                                        value={row.description || ""}
+                                        onChange={(e) =>
+                                            handleChange(i, "description", e.target.value)
+                                        }
+                                        className="bg-gray-800 border-gray-700 text-white"
+                                    />
+                                </td>
+                            )}
                             <td className="border-b border-gray-700 p-2">
                                 <button
                                     onClick={() => removeRow(i)}
@@ -892,6 +1101,64 @@ function RequestView({ request }) {
         );
     };
 
+    const renderParamsTable = () => {
+        const updateParam = (index, patch) =>
+            params.map((row, i) => (i === index ? { ...row, ...patch } : row));
+        return (
+            <div>
+                <table className="w-full text-sm mb-2">
+                    <thead>
+                    <tr>
+                        <th className="border-b border-gray-700 p-2"></th>
+                        <th className="border-b border-gray-700 p-2 text-left">Key</th>
+                        <th className="border-b border-gray-700 p-2 text-left">Value</th>
+                        <th className="border-b border-gray-700 p-2 text-left">Description</th>
+                        <th className="border-b border-gray-700 p-2"></th>
+                    </tr>
+                    </thead>
+                    <tbody>
+                    {params.map((row, i) => (
+                        <tr key={i}>
+                            <td className="border-b border-gray-700 p-2">
+                                <input
+                                    type="checkbox"
+                                    checked={row.enabled}
+                                    onChange={(e) => commitParams(updateParam(i, { enabled: e.target.checked }))}
+                                />
+                            </td>
+                            {["key", "value", "description"].map((field) => (
+                                <td key={field} className="border-b border-gray-700 p-2">
+                                    <Input
+                                        type="text"
+                                        value={row[field]}
+                                        onChange={(e) => setParams(updateParam(i, { [field]: e.target.value }))}
+                                        onBlur={() => commitParams(params)}
+                                        className="bg-gray-800 border-gray-700 text-white"
+                                    />
+                                </td>
+                            ))}
+                            <td className="border-b border-gray-700 p-2">
+                                <button
+                                    onClick={() => commitParams(params.filter((_, idx) => idx !== i))}
+                                    className="text-red-400 hover:text-red-500"
+                                >
+                                    Remove
+                                </button>
+                            </td>
+                        </tr>
+                    ))}
+                    </tbody>
+                </table>
+                <button
+                    onClick={() => setParams([...params, { key: "", value: "", enabled: true, description: "" }])}
+                    className="bg-gray-700 px-2 py-1 rounded hover:bg-gray-600 transition"
+                >
+                    + Add
+                </button>
+            </div>
+        );
+    };
+
     const renderFormDataTable = () => {
         const items = formDataItems || [];
         const setItems = setFormDataItems;
@@ -1094,7 +1361,7 @@ function RequestView({ request }) {
             const newKV = (headersKV || []).map((h) => {
                 if (h.key.toLowerCase() === "content-type") {
                     found = true;
-                    return { key: "Content-Type", value: contentType };
+                    return { ...h, key: "Content-Type", value: contentType };
                 }
                 return h;
             });
@@ -1224,6 +1491,16 @@ function RequestView({ request }) {
                 </button>
             </div>
             <div className="flex-none mb-4 border-b border-gray-700">
+                <button
+                    onClick={() => setActiveTab("params")}
+                    className={`px-4 py-2 mr-2 focus:outline-none ${
+                        activeTab === "params"
+                            ? "border-b-2 border-blue-500"
+                            : "text-gray-400"
+                    }`}
+                >
+                    Params
+                </button>
                 <button
                     onClick={() => setActiveTab("headers")}
                     className={`px-4 py-2 mr-2 focus:outline-none ${
@@ -1254,10 +1531,109 @@ function RequestView({ request }) {
                 >
                     Body
                 </button>
+                <button
+                    onClick={() => setActiveTab("scripts")}
+                    className={`px-4 py-2 focus:outline-none ${
+                        activeTab === "scripts"
+                            ? "border-b-2 border-blue-500"
+                            : "text-gray-400"
+                    }`}
+                >
+                    Scripts
+                </button>
             </div>
+            {activeTab === "scripts" && (
+                <div className="flex-none mb-4 p-3 rounded-lg shadow-md space-y-3">
+                    {!resolvedRequestId && (
+                        <div className="text-xs text-gray-400">
+                            Save the request to attach scripts to it.
+                        </div>
+                    )}
+                    <div>
+                        <h3 className="font-semibold mb-2">Pre-request Script</h3>
+                        <CodeMirror
+                            value={preRequestScript}
+                            height="120px"
+                            extensions={[javascript()]}
+                            theme={copilot}
+                            className="border border-gray-700 rounded w-full"
+                            editable={!!resolvedRequestId}
+                            onChange={(value) => updateScripts(value, postResponseScript)}
+                        />
+                    </div>
+                    <div>
+                        <h3 className="font-semibold mb-2">Post-response Script</h3>
+                        <CodeMirror
+                            value={postResponseScript}
+                            height="120px"
+                            extensions={[javascript()]}
+                            theme={copilot}
+                            className="border border-gray-700 rounded w-full"
+                            editable={!!resolvedRequestId}
+                            onChange={(value) => updateScripts(preRequestScript, value)}
+                        />
+                    </div>
+                </div>
+            )}
+            {activeTab === "params" && (
+                <div className="flex-none mb-4 p-3 rounded-lg shadow-md">
+                    <h3 className="font-semibold mb-2">Query Params</h3>
+                    {resolvedRequestId ? (
+                        <>
+                            {renderParamsTable()}
+                            {pathVariableRows.length > 0 && (
+                                <>
+                                    <h3 className="font-semibold mt-4 mb-2">Path Variables</h3>
+                                    <table className="w-full text-sm mb-2">
+                                        <thead>
+                                        <tr>
+                                            <th className="border-b border-gray-700 p-2 text-left">Key</th>
+                                            <th className="border-b border-gray-700 p-2 text-left">Value</th>
+                                            <th className="border-b border-gray-700 p-2 text-left">Description</th>
+                                        </tr>
+                                        </thead>
+                                        <tbody>
+                                        {pathVariableRows.map((row) => (
+                                            <tr key={row.key}>
+                                                <td className="border-b border-gray-700 p-2 text-gray-300">
+                                                    :{row.key}
+                                                </td>
+                                                {["value", "description"].map((field) => (
+                                                    <td key={field} className="border-b border-gray-700 p-2">
+                                                        <Input
+                                                            type="text"
+                                                            value={row[field]}
+                                                            onChange={(e) =>
+                                                                setPathVariables(updatePathVariable(row.key, { [field]: e.target.value }))
+                                                            }
+                                                            onBlur={() => commitPathVariables(pathVariableRows)}
+                                                            className="bg-gray-800 border-gray-700 text-white"
+                                                        />
+                                                    </td>
+                                                ))}
+                                            </tr>
+                                        ))}
+                                        </tbody>
+                                    </table>
+                                </>
+                            )}
+                        </>
+                    ) : (
+                        <div className="text-xs text-gray-400">
+                            Save the request to manage its params.
+                        </div>
+                    )}
+                </div>
+            )}
             {activeTab === "headers" && (
                 <div className="flex-none mb-4 p-3 rounded-lg shadow-md">
                     <h3 className="font-semibold mb-2">Headers</h3>
+                    {inheritedDefaults?.headers?.length > 0 && (
+                        <div className="text-xs text-gray-400 mb-2">
+                            Inherited unless set here:{" "}
+                            {inheritedDefaults.headers.map((h) => `${h.key} (${h.collectionName})`).join(", ")}
+                        </div>
+                    )}
                     <div className="flex justify-between items-center mb-2">
                         <select
                             value={headerType}
@@ -1286,22 +1662,42 @@ function RequestView({ request }) {
                         />
                     )}
                     {headerType === "keyvalue" &&
-                        renderKeyValueTable(headersKV, setHeadersKV)}
+                        renderKeyValueTable(headersKV, setHeadersKV, true)}
                 </div>
             )}
             {activeTab === "authorization" && (
                 <div className="p-3 rounded-lg shadow-md">
                     <h3 className="font-semibold mb-2">Authorization</h3>
                     <select
-                        value={authType}
-                        onChange={(e) => setAuthType(e.target.value)}
+                        value={auth === AUTH_NONE ? AUTH_NONE : authType}
+                        onChange={(e) => {
+                            const value = e.target.value;
+                            if (value === AUTH_NONE) {
+                                setAuth(AUTH_NONE);
+                                setAuthType("none");
+                                return;
+                            }
+                            if (auth === AUTH_NONE) setAuth("");
+                            setAuthType(value);
+                        }}
                         className="bg-gray-800 text-white rounded px-2 py-1 mb-4"
                     >
                         <option value="none">None</option>
+                        <option value={AUTH_NONE}>No auth</option>
                         <option value="bearer">Bearer Token</option>
                         <option value="basic">Basic Auth</option>
                         <option value="apikey">API Key</option>
                     </select>
+                    {authType === "none" && !auth && inheritedDefaults?.auth && (
+                        <div className="text-xs text-gray-400 mb-2">
+                            Inherits auth from {inheritedDefaults.authCollectionName}.
+                        </div>
+                    )}
+                    {auth === AUTH_NONE && (
+                        <div className="text-xs text-gray-400 mb-2">
+                            Sends no Authorization header, even when a collection sets one.
+                        </div>
+                    )}
                     {authType === "bearer" && (
                         <Input
                             placeholder="Enter Bearer Token"
@@ -1561,6 +1957,18 @@ function RequestView({ request }) {
                                 >
                                     History
                                 </button>
+                                {responseData?.scripts && (
+                                    <button
+                                        onClick={() => setResponseTab("tests")}
+                                        className={`px-4 py-2 -mb-px ${
+                                            responseTab === "tests"
+                                                ? "border-b-2 border-blue-500"
+                                                : "text-gray-400"
+                                        }`}
+                                    >
+                                        Tests
+                                    </button>
+                                )}
                             </div>
                             <div className="flex items-center space-x-4 text-sm">
                                 {latestResponse ? (
@@ -1639,6 +2047,45 @@ function RequestView({ request }) {
                             />
                         </div>
                     ) : null}
+                    {responseTab === "tests" && responseData?.scripts ? (
+                        <div className="flex-1 p-4 overflow-auto space-y-4 text-sm">
+                            {[
+                                { label: "Pre-request", result: responseData.scripts.preRequest },
+                                { label: "Post-response", result: responseData.scripts.postResponse },
+                            ]
+                                .filter(({ result }) => result)
+                                .map(({ label, result }) => (
+                                    <div key={label} className="space-y-2">
+                                        <h3 className="font-semibold">{label}</h3>
+                                        {result.error && (
+                                            <div className="text-red-300">{result.error}</div>
+                                        )}
+                                        {(result.tests || []).map((test, index) => (
+                                            <div key={index} className="flex items-start space-x-2">
+                                                <span
+                                                    className={`px-2 py-0.5 rounded text-xs ${
+                                                        test.passed
+                                                            ? "bg-green-500/20 text-green-200"
+                                                            : "bg-red-500/20 text-red-200"
+                                                    }`}
+                                                >
+                                                    {test.passed ? "PASS" : "FAIL"}
+                                                </span>
+                                                <span className="text-gray-200">{test.name}</span>
+                                                {test.error && (
+                                                    <span className="text-gray-400">{test.error}</span>
+                                                )}
+                                            </div>
+                                        ))}
+                                        {(result.logs || []).length > 0 && (
+                                            <pre className="text-xs text-gray-400 whitespace-pre-wrap">
+                                                {result.logs.join("\n")}
+                                            </pre>
+                                        )}
+                                    </div>
+                                ))}
+                        </div>
+                    ) : null}
                     {responseTab === "history" && (
                         <div className="flex-1 p-4 overflow-auto space-y-3">
                             {responseHistory.length === 0 ? (
@@ -1646,9 +2093,22 @@ function RequestView({ request }) {
                                     No responses recorded yet for this request.
                                 </div>
                             ) : (
+                                <>
+                                <div className="flex items-center justify-between text-xs text-gray-400">
+                                    <span>Select two responses to compare them.</span>
+                                    <button
+                                        type="button"
+                                        onClick={compareResponses}
+                                        disabled={compareSelection.length !== 2}
+                                        className="px-3 py-1 rounded bg-gray-800 text-gray-200 hover:bg-gray-700 disabled:opacity-40"
+                                    >
+                                        Compare
+                                    </button>
+                                </div>
                                 <table className="w-full text-sm border-collapse">
                                     <thead>
                                         <tr className="text-xs uppercase text-gray-400">
+                                            <th className="border-b border-gray-700 pb-2 w-8" />
                                             <th className="text-left font-normal border-b border-gray-700 pb-2">
                                                 Time
                                             </th>
@@ -1661,6 +2121,7 @@ function RequestView({ request }) {
                                             <th className="text-left font-normal border-b border-gray-700 pb-2">
                                                 Body Size
                                             </th>
+                                            <th className="border-b border-gray-700 pb-2 w-40" />
                                         </tr>
                                     </thead>
                                     <tbody>
@@ -1675,6 +2136,13 @@ function RequestView({ request }) {
                                                     : "Unknown";
                                             return (
                                                 <tr key={entry.id} className="border-b border-gray-800/60">
+                                                    <td className="py-2 pr-2">
+                                                        <input
+                                                            type="checkbox"
+                                                            checked={compareSelection.includes(entry.id)}
+                                                            onChange={() => toggleCompare(entry.id)}
+                                                        />
+                                                    </td>
                                                     <td className="py-2 pr-4 text-gray-200">{createdAt}</td>
                                                     <td className="py-2 pr-4">
                                                         <span
@@ -1693,11 +2161,120 @@ function RequestView({ request }) {
                                                     <td className="py-2 pr-4 text-gray-300">
                                                         {sizeKb ? `${sizeKb} KB` : "—"}
                                                     </td>
+                                                    <td className="py-2 text-right space-x-1 whitespace-nowrap">
+                                                        <button
+                                                            type="button"
+                                                            onClick={() => viewSnapshot(entry.id)}
+                                                            className="text-xs px-2 py-1 rounded text-gray-400 hover:text-gray-200"
+                                                            title="Show the request that was sent"
+                                                        >
+                                                            Request
+                                                        </button>
+                                                        <button
+                                                            type="button"
+                                                            onClick={() => rerunSnapshot(entry.id)}
+                                                            disabled={isLoading}
+                                                            className="text-xs px-2 py-1 rounded text-gray-400 hover:text-gray-200 disabled:opacity-40"
+                                                            title="Send exactly this request again"
+                                                        >
+                                                            Re-run
+                                                        </button>
+                                                        <button
+                                                            type="button"
+                                                            onClick={() => togglePinned(entry)}
+                                                            className={`text-xs px-2 py-1 rounded ${
+                                                                entry.pinned
+                                                                    ? "bg-yellow-500/20 text-yellow-200"
+                                                                    : "text-gray-400 hover:text-gray-200"
+                                                            }`}
+                                                            title="Pinned responses are kept when old history is pruned"
+                                                        >
+                                                            {entry.pinned ? "Pinned" : "Pin"}
+                                                        </button>
+                                                    </td>
                                                 </tr>
                                             );
                                         })}
                                     </tbody>
                                 </table>
+                                {viewedSnapshot && (
+                                    <div className="text-xs space-y-2 border border-gray-800 rounded p-3">
+                                        {viewedSnapshot.error ? (
+                                            <div className="text-red-300">{viewedSnapshot.error}</div>
+                                        ) : (
+                                            <>
+                                                <div className="font-mono text-gray-200 break-all">
+                                                    {viewedSnapshot.method} {viewedSnapshot.url}
+                                                </div>
+                                                {viewedSnapshot.environment && (
+                                                    <div className="text-gray-400">Environment: {viewedSnapshot.environment}</div>
+                                                )}
+                                                <div className="font-mono text-gray-300">
+                                                    {Object.entries(viewedSnapshot.headers || {}).map(([key, values]) =>
+                                                        (values || []).map((value, index) => (
+                                                            <div key={`${key}-${index}`}>
+                                                                {key}: {value}
+                                                            </div>
+                                                        ))
+                                                    )}
+                                                </div>
+                                                {viewedSnapshot.body && (
+                                                    <pre className="font-mono text-gray-300 whitespace-pre-wrap break-all">
+                                                        {viewedSnapshot.body}
+                                                    </pre>
+                                                )}
+                                                {viewedSnapshot.maskedSecrets?.length > 0 && (
+                                                    <div className="text-gray-500">
+                                                        Masked secrets: {viewedSnapshot.maskedSecrets.join(", ")}
+                                                    </div>
+                                                )}
+                                            </>
+                                        )}
+                                    </div>
+                                )}
+                                {responseDiff && (
+                                    <div className="space-y-3 text-xs">
+                                        <div className="text-gray-300">
+                                            Status: {responseDiff.statusBefore}
+                                            {responseDiff.statusChanged ? ` → ${responseDiff.statusAfter}` : " (unchanged)"}
+                                        </div>
+                                        {[
+                                            ["Headers", responseDiff.headers],
+                                            [`Body (${responseDiff.bodyFormat})`, responseDiff.body],
+                                        ].map(([label, entries]) => (
+                                            <div key={label}>
+                                                <div className="uppercase text-gray-400 mb-1">{label}</div>
+                                                {!entries || entries.length === 0 ? (
+                                                    <div className="text-gray-500">No differences.</div>
+                                                ) : (
+                                                    entries.map((diff, index) => (
+                                                        <div key={`${diff.path}-${index}`} className="font-mono py-0.5">
+                                                            <span
+                                                                className={
+                                                                    diff.kind === "added"
+                                                                        ? "text-green-300"
+                                                                        : diff.kind === "removed"
+                                                                        ? "text-red-300"
+                                                                        : "text-yellow-300"
+                                                                }
+                                                            >
+                                                                {diff.kind === "added" ? "+" : diff.kind === "removed" ? "-" : "~"} {diff.path}
+                                                            </span>
+                                                            {diff.kind !== "added" && (
+                                                                <span className="text-red-200"> {diff.before}</span>
+                                                            )}
+                                                            {diff.kind === "changed" && <span className="text-gray-500"> →</span>}
+                                                            {diff.kind !== "removed" && (
+                                                                <span className="text-green-200"> {diff.after}</span>
+                                                            )}
+                                                        </div>
+                                                    ))
+                                                )}
+                                            </div>
+                                        ))}
+                                    </div>
+                                )}
+                                </>
                             )}
                         </div>
                     )}
@@ -11,10 +11,17 @@ import {
     SelectItem,
 } from "@/components/ui/select";
 import { SaveUserSettings } from "../../bindings/github.com/D-Elbel/curlew/appstateservice.js";
+import { PruneResponseHistory } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js";
 import {
     FetchUserKeybinds,
     UpdateUserKeybinds,
 } from "../../bindings/github.com/D-Elbel/curlew/userservice.js";
+import {
+    ListCookies,
+    SaveCookie,
+    DeleteCookie,
+    ClearCookies,
+} from "../../bindings/github.com/D-Elbel/curlew/cookieservice.js";
 import { useHotkeys } from "@/services/HotkeysContext.jsx";
 import { useEnvarStore } from "@/stores/envarStore";
 import { useUserSettings } from "@/services/UserSettingsContext.jsx";
@@ -25,6 +32,9 @@ const mapSettingsToFormState = (raw) => {
         defaultEnv: "",
         enableAnimations: true,
         responseHistoryTTL: "5",
+        responseHistoryMaxAgeDays: "0",
+        responseHistoryMaxSizeMB: "0",
+        trashRetentionDays: "30",
     };
     if (!raw || typeof raw !== "object") {
         return fallback;
@@ -40,6 +50,18 @@ const mapSettingsToFormState = (raw) => {
             raw.responseHistoryTTL != null
                 ? String(raw.responseHistoryTTL)
                 : fallback.responseHistoryTTL,
+        responseHistoryMaxAgeDays:
+            raw.responseHistoryMaxAgeDays != null
+                ? String(raw.responseHistoryMaxAgeDays)
+                : fallback.responseHistoryMaxAgeDays,
+        responseHistoryMaxSizeMB:
+            raw.responseHistoryMaxSizeMB != null
+                ? String(raw.responseHistoryMaxSizeMB)
+                : fallback.responseHistoryMaxSizeMB,
+        trashRetentionDays:
+            raw.trashRetentionDays != null
+                ? String(raw.trashRetentionDays)
+                : fallback.trashRetentionDays,
     };
 };
 
@@ -53,8 +75,11 @@ export default function SettingsModal({ open, onOpenChange }) {
     const [settings, setSettings] = useState(formDefaults);
     const [keybinds, setKeybinds] = useState([]);
     const [ttlError, setTtlError] = useState("");
+    const [pruneStatus, setPruneStatus] = useState("");
     const { reloadHotkeys } = useHotkeys();
     const envs = useEnvarStore((state) => state.environmentVariables);
+    const activeEnv = useEnvarStore((state) => state.activeEnvironment) || "";
+    const [cookies, setCookies] = useState([]);
     const NO_ENV_VALUE = "__none__";
     const environmentNames = envs.map((env) => env.env).filter(Boolean);
     const isDefaultEnvMissing = settings.defaultEnv && !environmentNames.includes(settings.defaultEnv);
@@ -87,6 +112,48 @@ export default function SettingsModal({ open, onOpenChange }) {
         }
     }, [formDefaults, open]);
 
+    const loadCookies = async () => {
+        try {
+            setCookies((await ListCookies(activeEnv)) || []);
+        } catch (err) {
+            console.error("Failed to load cookies", err);
+        }
+    };
+
+    useEffect(() => {
+        if (open && activeSection === "cookies") {
+            loadCookies();
+        }
+    }, [open, activeSection, activeEnv]);
+
+    const handleCookieValueSave = async (cookie, value) => {
+        if (cookie.value === value) return;
+        try {
+            await SaveCookie(activeEnv, { ...cookie, value });
+            await loadCookies();
+        } catch (err) {
+            console.error("Failed to save cookie", err);
+        }
+    };
+
+    const handleCookieDelete = async (cookie) => {
+        try {
+            await DeleteCookie(activeEnv, cookie.domain, cookie.path, cookie.name);
+            await loadCookies();
+        } catch (err) {
+            console.error("Failed to delete cookie", err);
+        }
+    };
+
+    const handleCookiesClear = async () => {
+        try {
+            await ClearCookies(activeEnv, "");
+            await loadCookies();
+        } catch (err) {
+            console.error("Failed to clear cookies", err);
+        }
+    };
+
     const handleTtlChange = (value) => {
         setSettings((prev) => ({
             ...prev,
@@ -101,18 +168,44 @@ export default function SettingsModal({ open, onOpenChange }) {
         }
     };
 
+    const handlePruneNow = async () => {
+        setPruneStatus("Cleaning up…");
+        try {
+            const result = await PruneResponseHistory();
+            const freedKb = ((result?.freedBytes || 0) / 1024).toFixed(1);
+            setPruneStatus(`Removed ${result?.deleted || 0} responses (${freedKb} KB).`);
+        } catch (err) {
+            console.error("Failed to prune response history", err);
+            setPruneStatus(String(err));
+        }
+    };
+
     const handleSave = async () => {
         const ttlNumber = parseInt(settings.responseHistoryTTL, 10);
         if (!Number.isFinite(ttlNumber) || ttlNumber < 1) {
             setTtlError("Please enter a value of 1 or greater.");
             return;
         }
+        const maxAgeDays = parseInt(settings.responseHistoryMaxAgeDays, 10);
+        const maxSizeMB = parseInt(settings.responseHistoryMaxSizeMB, 10);
+        if (!Number.isFinite(maxAgeDays) || maxAgeDays < 0 || !Number.isFinite(maxSizeMB) || maxSizeMB < 0) {
+            setTtlError("Age and size limits must be 0 or greater.");
+            return;
+        }
+        const trashDays = parseInt(settings.trashRetentionDays, 10);
+        if (!Number.isFinite(trashDays) || trashDays < 0) {
+            setTtlError("Trash retention must be 0 or greater.");
+            return;
+        }
         setTtlError("");
 
         try {
             await SaveUserSettings({
                 ...settings,
                 responseHistoryTTL: ttlNumber,
+                responseHistoryMaxAgeDays: maxAgeDays,
+                responseHistoryMaxSizeMB: maxSizeMB,
+                trashRetentionDays: trashDays,
             });
             await UpdateUserKeybinds(keybinds);
             reloadHotkeys();
@@ -154,6 +247,16 @@ export default function SettingsModal({ open, onOpenChange }) {
                             >
                                 Keybinds
                             </button>
+                            <button
+                                className={`w-full text-left px-3 py-2 rounded ${
+                                    activeSection === "cookies"
+                                        ? "bg-primary text-primary-foreground"
+                                        : "hover:bg-accent"
+                                }`}
+                                onClick={() => setActiveSection("cookies")}
+                            >
+                                Cookies
+                            </button>
                         </nav>
                     </div>
 
@@ -246,6 +349,62 @@ export default function SettingsModal({ open, onOpenChange }) {
                                                 Oldest responses beyond this count are removed automatically.
                                             </p>
                                         )}
+                                        <label className="block text-sm font-medium mb-1 mt-4">
+                                            Maximum age in days
+                                        </label>
+                                        <Input
+                                            type="number"
+                                            min={0}
+                                            value={settings.responseHistoryMaxAgeDays}
+                                            onChange={(e) =>
+                                                setSettings({ ...settings, responseHistoryMaxAgeDays: e.target.value })
+                                            }
+                                            className="w-64"
+                                        />
+                                        <label className="block text-sm font-medium mb-1 mt-4">
+                                            Maximum total body size in MB
+                                        </label>
+                                        <Input
+                                            type="number"
+                                            min={0}
+                                            value={settings.responseHistoryMaxSizeMB}
+                                            onChange={(e) =>
+                                                setSettings({ ...settings, responseHistoryMaxSizeMB: e.target.value })
+                                            }
+                                            className="w-64"
+                                        />
+                                        <p className="text-xs text-gray-400 mt-1">
+                                            Use 0 for no limit. Pinned responses are always kept, and
+                                            collections can override the count and age limits.
+                                        </p>
+                                        <div className="flex items-center gap-3 mt-3">
+                                            <Button variant="outline" size="sm" onClick={handlePruneNow}>
+                                                Clean up now
+                                            </Button>
+                                            {pruneStatus && (
+                                                <span className="text-xs text-gray-400">{pruneStatus}</span>
+                                            )}
+                                        </div>
+                                    </section>
+
+                                    <section>
+                                        <h3 className="text-base font-semibold mb-2">Trash</h3>
+                                        <label className="block text-sm font-medium mb-1">
+                                            Days to keep deleted items
+                                        </label>
+                                        <Input
+                                            type="number"
+                                            min={0}
+                                            value={settings.trashRetentionDays}
+                                            onChange={(e) =>
+                                                setSettings({ ...settings, trashRetentionDays: e.target.value })
+                                            }
+                                            className="w-64"
+                                        />
+                                        <p className="text-xs text-gray-400 mt-1">
+                                            Deleted requests and collections are purged after this many days. Use 0
+                                            to keep them until the trash is emptied.
+                                        </p>
                                     </section>
                                 </>
                             )}
@@ -280,6 +439,74 @@ export default function SettingsModal({ open, onOpenChange }) {
                                     </div>
                                 </section>
                             )}
+
+                            {activeSection === "cookies" && (
+                                <section>
+                                    <div className="flex items-center justify-between mb-4">
+                                        <h3 className="text-base font-semibold">
+                                            Cookies for {activeEnv || "no environment"}
+                                        </h3>
+                                        <Button
+                                            variant="outline"
+                                            onClick={handleCookiesClear}
+                                            disabled={cookies.length === 0}
+                                        >
+                                            Clear All
+                                        </Button>
+                                    </div>
+                                    {cookies.length === 0 ? (
+                                        <p className="text-sm text-gray-400">No cookies stored.</p>
+                                    ) : (
+                                        <table className="w-full text-sm border-collapse">
+                                            <thead>
+                                                <tr className="text-xs uppercase text-gray-400">
+                                                    <th className="text-left font-normal border-b pb-2">Domain</th>
+                                                    <th className="text-left font-normal border-b pb-2">Path</th>
+                                                    <th className="text-left font-normal border-b pb-2">Name</th>
+                                                    <th className="text-left font-normal border-b pb-2">Value</th>
+                                                    <th className="text-left font-normal border-b pb-2">Expires</th>
+                                                    <th className="border-b pb-2" />
+                                                </tr>
+                                            </thead>
+                                            <tbody>
+                                                {cookies.map((cookie) => (
+                                                    <tr
+                                                        key={`${cookie.domain}|${cookie.path}|${cookie.name}`}
+                                                        className="border-b border-gray-800/60"
+                                                    >
+                                                        <td className="py-2 pr-4">
+                                                            {cookie.hostOnly ? cookie.domain : `.${cookie.domain}`}
+                                                        </td>
+                                                        <td className="py-2 pr-4">{cookie.path}</td>
+                                                        <td className="py-2 pr-4">{cookie.name}</td>
+                                                        <td className="py-2 pr-4">
+                                                            <Input
+                                                                defaultValue={cookie.value}
+                                                                onBlur={(e) =>
+                                                                    handleCookieValueSave(cookie, e.target.value)
+                                                                }
+                                                            />
+                                                        </td>
+                                                        <td className="py-2 pr-4 text-gray-400">
+                                                            {cookie.expires
+                                                                ? new Date(cookie.expires).toLocaleString()
+                                                                : "Session"}
+                                                        </td>
+                                                        <td className="py-2 text-right">
+                                                            <Button
+                                                                variant="ghost"
+                                                                onClick={() => handleCookieDelete(cookie)}
+                                                            >
+                                                                Delete
+                                                            </Button>
+                                                        </td>
+                                                    </tr>
+                                                ))}
+                                            </tbody>
+                                        </table>
+                                    )}
+                                </section>
+                            )}
                         </div>
 
                         <div className="border-t p-4 flex justify-end gap-2">
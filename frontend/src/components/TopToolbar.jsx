@@ -1,5 +1,6 @@
 import { useEnvarStore } from "@/stores/envarStore";
 import SettingsModal from "@/components/SettingsModal.jsx"
+import ActivityTimelineModal from "@/components/ActivityTimelineModal.jsx"
 import React, { useState } from "react";
 import {
     Select,
@@ -19,6 +20,7 @@ import { Check, Plus, Menu } from "lucide-react";
 
 function TopToolbar({ onTriggerCommand, onTriggerTabMenu }) {
     const [settingsOpen, setSettingsOpen] = useState(false);
+    const [activityOpen, setActivityOpen] = useState(false);
     const envs = useEnvarStore((state) => state.environmentVariables);
     const activeEnv = useEnvarStore((state) => state.activeEnvironment);
     const setActiveEnv = useEnvarStore((state) => state.setActiveEnvironment);
@@ -35,6 +37,9 @@ function TopToolbar({ onTriggerCommand, onTriggerTabMenu }) {
                     <DropdownMenuItem onClick={() => setSettingsOpen(true)}>
                         Settings
                     </DropdownMenuItem>
+                    <DropdownMenuItem onClick={() => setActivityOpen(true)}>
+                        Activity
+                    </DropdownMenuItem>
                     <DropdownMenuItem onClick={() => console.log("Help clicked")}>
                         Help
                     </DropdownMenuItem>
@@ -46,6 +51,7 @@ function TopToolbar({ onTriggerCommand, onTriggerTabMenu }) {
 
 
             <SettingsModal open={settingsOpen} onOpenChange={setSettingsOpen} />
+            <ActivityTimelineModal open={activityOpen} onOpenChange={setActivityOpen} />
 
             <div className="w-[10%]">
                 <DropdownMenu>
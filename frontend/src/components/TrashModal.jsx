@@ -0,0 +1,138 @@
+import React, { useCallback, useEffect, useState } from "react";
+import { Dialog, DialogContent } from "@/components/ui/dialog";
+import { Button } from "@/components/ui/button";
+import {
+    EmptyTrash,
+    GetTrash,
+    PurgeCollection,
+    PurgeRequest,
+    RestoreCollection,
+    RestoreRequest,
+} from "../../bindings/github.com/D-Elbel/curlew/trashservice.js";
+import { useRequestStore } from "@/stores/requestStore.js";
+import { methodColourMap } from "@/utils/constants.js";
+
+export default function TrashModal({ open, onOpenChange }) {
+    const loadAll = useRequestStore((state) => state.loadAll);
+    const [items, setItems] = useState([]);
+    const [error, setError] = useState("");
+
+    const loadTrash = useCallback(async () => {
+        try {
+            setItems((await GetTrash()) || []);
+            setError("");
+        } catch (err) {
+            console.error("Failed to load trash", err);
+            setError(String(err));
+        }
+    }, []);
+
+    useEffect(() => {
+        if (open) {
+            loadTrash();
+        }
+    }, [open, loadTrash]);
+
+    const run = async (action) => {
+        try {
+            await action();
+            await Promise.all([loadTrash(), loadAll()]);
+        } catch (err) {
+            console.error("Trash action failed", err);
+            setError(String(err));
+        }
+    };
+
+    const restore = (item) =>
+        run(() => (item.kind === "collection" ? RestoreCollection(item.collectionId) : RestoreRequest(item.requestId)));
+
+    const purge = (item) => {
+        if (!window.confirm(`Permanently delete "${item.name || "Untitled"}"? This cannot be undone.`)) {
+            return;
+        }
+        run(() => (item.kind === "collection" ? PurgeCollection(item.collectionId) : PurgeRequest(item.requestId)));
+    };
+
+    const emptyTrash = () => {
+        if (!window.confirm("Permanently delete everything in the trash? This cannot be undone.")) {
+            return;
+        }
+        run(() => EmptyTrash());
+    };
+
+    return (
+        <Dialog open={open} onOpenChange={onOpenChange}>
+            <DialogContent className="min-w-[60vw] h-[70vh] p-0 overflow-hidden">
+                <div className="flex flex-col h-full p-4 gap-3">
+                    <div className="flex items-center justify-between pr-8">
+                        <h2 className="text-lg font-semibold">Trash</h2>
+                        <Button variant="outline" size="sm" onClick={emptyTrash} disabled={items.length === 0}>
+                            Empty trash
+                        </Button>
+                    </div>
+                    <p className="text-xs text-gray-400">
+                        Deleted requests and collections are kept here until they are purged. The retention
+                        period can be changed in Settings.
+                    </p>
+
+                    {error && <div className="text-sm text-red-400">{error}</div>}
+
+                    <div className="flex-1 overflow-auto">
+                        {items.length === 0 ? (
+                            <div className="text-sm text-gray-400">The trash is empty.</div>
+                        ) : (
+                            <table className="w-full text-sm border-collapse">
+                                <thead>
+                                    <tr className="text-xs uppercase text-gray-400">
+                                        <th className="text-left font-normal border-b border-gray-700 pb-2">Item</th>
+                                        <th className="text-left font-normal border-b border-gray-700 pb-2">Location</th>
+                                        <th className="text-left font-normal border-b border-gray-700 pb-2">Deleted</th>
+                                        <th className="border-b border-gray-700 pb-2" />
+                                    </tr>
+                                </thead>
+                                <tbody>
+                                    {items.map((item) => (
+                                        <tr
+                                            key={`${item.kind}-${item.collectionId || item.requestId}`}
+                                            className="border-b border-gray-800/60"
+                                        >
+                                            <td className="py-2 pr-4">
+                                                {item.kind === "collection" ? (
+                                                    <span>
+                                                        {item.name}
+                                                        <span className="text-xs text-gray-500 ml-2">
+                                                            collection, {item.requestCount} requests
+                                                        </span>
+                                                    </span>
+                                                ) : (
+                                                    <span className="flex items-center gap-2">
+                                                        <span className={`text-xs font-medium ${methodColourMap.get(item.method)}`}>
+                                                            {item.method}
+                                                        </span>
+                                                        {item.name || item.url || "Untitled"}
+                                                    </span>
+                                                )}
+                                            </td>
+                                            <td className="py-2 pr-4 text-gray-400">{item.location || "Top level"}</td>
+                                            <td className="py-2 pr-4 text-gray-400 whitespace-nowrap">
+                                                {item.deletedAt ? new Date(item.deletedAt).toLocaleString() : ""}
+                                            </td>
+                                            <td className="py-2 text-right whitespace-nowrap">
+                                                <Button variant="ghost" size="sm" onClick={() => restore(item)}>
+                                                    Restore
+                                                </Button>
+                                                <Button variant="ghost" size="sm" onClick={() => purge(item)}>
+                                                    Delete forever
+                                                </Button>
+                                            </td>
+                                        </tr>
+                                    ))}
+                                </tbody>
+                            </table>
+                        )}
+                    </div>
+                </div>
+            </DialogContent>
+        </Dialog>
+    );
+}
@@ -11,7 +11,9 @@ const defaultHotkeysMap = {
     NEW_REQUEST:          'ctrl+n+r',
     OPEN_ENV:             'ctrl+e',
     OPEN_SIDEBAR:         'ctrl+b',
-    HANDLE_ENTITY_SAVE:   'ctrl+s'
+    HANDLE_ENTITY_SAVE:   'ctrl+s',
+    UNDO:                 'ctrl+z',
+    REDO:                 'ctrl+shift+z'
 }
 
 export const HotkeysProvider = ({ children }) => {
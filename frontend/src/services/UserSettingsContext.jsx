@@ -13,6 +13,8 @@ const defaultSettings = {
     defaultEnv: "",
     enableAnimations: true,
     responseHistoryTTL: 5,
+    responseHistoryMaxAgeDays: 0,
+    responseHistoryMaxSizeMB: 0,
 };
 
 const ANIMATIONS_DISABLED_CLASS = "animations-disabled";
@@ -54,11 +56,18 @@ const normalizeSettings = (raw) => {
             : defaultSettings.responseHistoryTTL;
     })();
 
+    const retentionLimit = (key) => {
+        const value = typeof raw[key] === "number" ? raw[key] : parseInt(raw[key], 10);
+        return Number.isFinite(value) && value >= 0 ? value : defaultSettings[key];
+    };
+
     return {
         theme,
         defaultEnv,
         enableAnimations,
         responseHistoryTTL,
+        responseHistoryMaxAgeDays: retentionLimit("responseHistoryMaxAgeDays"),
+        responseHistoryMaxSizeMB: retentionLimit("responseHistoryMaxSizeMB"),
     };
 };
 
@@ -7,20 +7,74 @@ import {
     UpdateRequest,
     DeleteRequest,
     DeleteCollection,
-    DuplicateRequest
+    DuplicateRequest,
+    DuplicateCollection,
+    GetTags,
+    SetRequestFavorite,
+    SetCollectionFavorite,
+    SetRequestTags,
+    SetCollectionTags,
+    GetJournalState,
+    Undo,
+    Redo
 } from "../../bindings/github.com/D-Elbel/curlew/requestcrudservice.js"
 
 export const useRequestStore = create((set, get) => ({
     requests: [],
     collections: [],
+    tags: [],
+    tagFilter: [],
+    // The operations undo and redo would apply next, or null.
+    journal: { undo: null, redo: null },
 
     // Load both collections & requests
     loadAll: async () => {
-        const [requests, collections] = await Promise.all([
-            GetAllRequestsList(),
-            GetAllCollections()
+        const [requests, collections, tags, journal] = await Promise.all([
+            GetAllRequestsList(get().tagFilter),
+            GetAllCollections(),
+            GetTags(),
+            GetJournalState()
         ])
-        set({ requests, collections })
+        set({ requests: requests || [], collections, tags: tags || [], journal })
+    },
+
+    undo: async () => {
+        const operation = await Undo()
+        await get().loadAll()
+        return operation
+    },
+
+    redo: async () => {
+        const operation = await Redo()
+        await get().loadAll()
+        return operation
+    },
+
+    setTagFilter: async (tagFilter) => {
+        set({ tagFilter })
+        await get().loadAll()
+    },
+
+    toggleRequestFavorite: async (req) => {
+        await SetRequestFavorite(req.id, !req.favorite)
+        set(state => ({
+            requests: state.requests.map(r => (r.id === req.id ? { ...r, favorite: !req.favorite } : r))
+        }))
+    },
+
+    toggleCollectionFavorite: async (collection) => {
+        await SetCollectionFavorite(collection.id, !collection.favorite)
+        await get().loadAll()
+    },
+
+    setRequestTags: async (id, tags) => {
+        await SetRequestTags(id, tags)
+        await get().loadAll()
+    },
+
+    setCollectionTags: async (id, tags) => {
+        await SetCollectionTags(id, tags)
+        await get().loadAll()
     },
 
     // Save or update a request, then inject into state
@@ -43,6 +97,7 @@ export const useRequestStore = create((set, get) => ({
                     : [...state.requests, saved]
             }
         })
+        set({ journal: await GetJournalState() })
 
         return saved
     },
@@ -52,6 +107,7 @@ export const useRequestStore = create((set, get) => ({
         set(state => ({
             requests: state.requests.filter(r => r.id !== id)
         }))
+        set({ journal: await GetJournalState() })
     },
 
     duplicateRequest: async (id) => {
@@ -72,9 +128,18 @@ export const useRequestStore = create((set, get) => ({
         return duplicated
     },
 
-    deleteCollection: async (id) => {
-        await DeleteCollection(id)
+    // options selects whether collection variables and the latest responses are copied too.
+    duplicateCollection: async (id, parentId, options) => {
+        const duplicated = await DuplicateCollection(id, parentId, options)
+        await get().loadAll()
+        return duplicated
+    },
+
+    // mode is "cascade" to delete the contents too, or "reparent" to move them up a level.
+    deleteCollection: async (id, mode) => {
+        const summary = await DeleteCollection(id, mode)
         await get().loadAll()
+        return summary
     }
 
 
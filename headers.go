@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// HeaderEntry is one header of a request. Enabled is a pointer so entries saved before the flag
+// existed count as enabled; Disabled is Postman's spelling and is honoured as well.
+type HeaderEntry struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (h HeaderEntry) isEnabled() bool {
+	return !h.Disabled && (h.Enabled == nil || *h.Enabled)
+}
+
+// parseHeaderEntries accepts either a list of entries or a plain name to value object.
+func parseHeaderEntries(headersIn string) []HeaderEntry {
+	var entries []HeaderEntry
+	if err := json.Unmarshal([]byte(headersIn), &entries); err == nil {
+		return entries
+	}
+
+	var headerMap map[string]string
+	if err := json.Unmarshal([]byte(headersIn), &headerMap); err != nil {
+		return []HeaderEntry{}
+	}
+	entries = make([]HeaderEntry, 0, len(headerMap))
+	for k, v := range headerMap {
+		entries = append(entries, HeaderEntry{Key: k, Value: v})
+	}
+	return entries
+}
+
+// enabledHeaders drops disabled and nameless entries and returns the rest in the key/value
+// form used while a request is executed.
+func enabledHeaders(entries []HeaderEntry) []map[string]string {
+	headers := make([]map[string]string, 0, len(entries))
+	for _, h := range entries {
+		if h.Key == "" || !h.isEnabled() {
+			continue
+		}
+		headers = append(headers, map[string]string{"key": h.Key, "value": h.Value})
+	}
+	return headers
+}
+
+// postmanHeaders converts Postman's header list, whose entries carry a disabled flag and may
+// have non-string values, into the stored header format.
+func postmanHeaders(raw interface{}) string {
+	list, ok := raw.([]interface{})
+	if !ok {
+		encoded, _ := json.Marshal(raw)
+		return string(encoded)
+	}
+
+	entries := make([]HeaderEntry, 0, len(list))
+	for _, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		enabled := true
+		if disabled, ok := fields["disabled"].(bool); ok && disabled {
+			enabled = false
+		}
+		entry := HeaderEntry{Enabled: &enabled, Description: postmanDescription(fields["description"])}
+		entry.Key, _ = fields["key"].(string)
+		if value, ok := fields["value"]; ok && value != nil {
+			if s, isString := value.(string); isString {
+				entry.Value = s
+			} else {
+				entry.Value = fmt.Sprint(value)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	encoded, _ := json.Marshal(entries)
+	return string(encoded)
+}
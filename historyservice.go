@@ -0,0 +1,296 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultExecutionLogPageSize = 50
+	maxExecutionLogPageSize     = 500
+)
+
+// HistoryService exposes a timeline of every execution: recorded responses and attempts that
+// failed before a response arrived.
+type HistoryService struct {
+	db *sql.DB
+}
+
+// ExecutionLogEntry is either a recorded response or a failed attempt, as Kind tells.
+type ExecutionLogEntry struct {
+	Kind           string     `json:"kind"`
+	ID             int        `json:"id"`
+	RequestID      int        `json:"requestId"`
+	RequestName    *string    `json:"requestName"`
+	CollectionID   *string    `json:"collectionId"`
+	CollectionName *string    `json:"collectionName"`
+	Method         string     `json:"method"`
+	URL            string     `json:"url"`
+	Host           string     `json:"host"`
+	Environment    string     `json:"environment"`
+	StatusCode     *int       `json:"statusCode"`
+	RuntimeMS      *int       `json:"runtimeMS"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt"`
+}
+
+// ExecutionLogFilter narrows the timeline. Empty fields do not filter. StatusClasses holds
+// values such as "2xx" or "5xx", and "error" for failed attempts. A collection includes its
+// sub-collections. Page is 1-based.
+type ExecutionLogFilter struct {
+	From          *time.Time `json:"from"`
+	To            *time.Time `json:"to"`
+	StatusClasses []string   `json:"statusClasses"`
+	Host          string     `json:"host"`
+	CollectionID  string     `json:"collectionId"`
+	Environment   string     `json:"environment"`
+	Page          int        `json:"page"`
+	PageSize      int        `json:"pageSize"`
+}
+
+type ExecutionLogPage struct {
+	Entries  []ExecutionLogEntry `json:"entries"`
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+}
+
+// executionLogQuery merges responses and failures into one row shape; method and url fall back
+// to the request's current values for responses recorded before they were stored.
+const executionLogQuery = `
+	SELECT 'response' AS kind, r.id, r.request_id, q.name AS request_name, q.collection_id, c.name AS collection_name,
+	       COALESCE(r.method, q.method, '') AS method, COALESCE(r.url, q.url, '') AS url, COALESCE(r.host, '') AS host,
+	       COALESCE(r.environment, '') AS environment, r.status_code, r.runtime_ms, '' AS error,
+	       COALESCE(r.created_at, CURRENT_TIMESTAMP) AS created_at
+	FROM responses r
+	LEFT JOIN requests q ON q.id = r.request_id
+	LEFT JOIN collections c ON c.id = q.collection_id
+	UNION ALL
+	SELECT 'failure', f.id, f.request_id, q.name, q.collection_id, c.name,
+	       COALESCE(f.method, ''), COALESCE(f.url, ''), COALESCE(f.host, ''),
+	       COALESCE(f.environment, ''), NULL, NULL, f.error,
+	       COALESCE(f.created_at, CURRENT_TIMESTAMP)
+	FROM request_failures f
+	LEFT JOIN requests q ON q.id = f.request_id
+	LEFT JOIN collections c ON c.id = q.collection_id`
+
+// GetExecutionLog returns one page of the timeline, newest first.
+func (s *HistoryService) GetExecutionLog(filter ExecutionLogFilter) (ExecutionLogPage, error) {
+	if s.db == nil {
+		return ExecutionLogPage{}, fmt.Errorf("database not initialized")
+	}
+
+	page := ExecutionLogPage{Entries: []ExecutionLogEntry{}, Page: filter.Page, PageSize: filter.PageSize}
+	if page.Page < 1 {
+		page.Page = 1
+	}
+	if page.PageSize < 1 {
+		page.PageSize = defaultExecutionLogPageSize
+	}
+	if page.PageSize > maxExecutionLogPageSize {
+		page.PageSize = maxExecutionLogPageSize
+	}
+
+	where, args, err := executionLogConditions(filter)
+	if err != nil {
+		return ExecutionLogPage{}, err
+	}
+	prefix := `WITH RECURSIVE scoped_collections(id) AS (
+		SELECT ? UNION SELECT collections.id FROM collections JOIN scoped_collections ON collections.parent_collection = scoped_collections.id
+	), timeline AS (` + executionLogQuery + `)`
+	args = append([]interface{}{filter.CollectionID}, args...)
+
+	if err := s.db.QueryRow(prefix+" SELECT COUNT(*) FROM timeline"+where, args...).Scan(&page.Total); err != nil {
+		return ExecutionLogPage{}, fmt.Errorf("failed to count execution log: %w", err)
+	}
+
+	rows, err := s.db.Query(
+		prefix+` SELECT kind, id, request_id, request_name, collection_id, collection_name, method, url, host,
+		                environment, status_code, runtime_ms, error, created_at
+		 FROM timeline`+where+` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		append(args, page.PageSize, (page.Page-1)*page.PageSize)...,
+	)
+	if err != nil {
+		return ExecutionLogPage{}, fmt.Errorf("failed to load execution log: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry          ExecutionLogEntry
+			requestName    sql.NullString
+			collectionID   sql.NullString
+			collectionName sql.NullString
+			statusCode     sql.NullInt64
+			runtimeMS      sql.NullInt64
+			createdAt      sql.NullString
+		)
+		if err := rows.Scan(&entry.Kind, &entry.ID, &entry.RequestID, &requestName, &collectionID, &collectionName,
+			&entry.Method, &entry.URL, &entry.Host, &entry.Environment, &statusCode, &runtimeMS, &entry.Error, &createdAt); err != nil {
+			return ExecutionLogPage{}, fmt.Errorf("failed to scan execution log: %w", err)
+		}
+		entry.RequestName = nullStringToPointer(requestName)
+		entry.CollectionID = nullStringToPointer(collectionID)
+		entry.CollectionName = nullStringToPointer(collectionName)
+		entry.StatusCode = nullIntToPointer(statusCode)
+		entry.RuntimeMS = nullIntToPointer(runtimeMS)
+		entry.CreatedAt = parseStoredTime(createdAt.String)
+		page.Entries = append(page.Entries, entry)
+	}
+	return page, rows.Err()
+}
+
+// GetExecutionHosts lists the hosts seen in the timeline for the host filter.
+func (s *HistoryService) GetExecutionHosts() []string {
+	hosts := []string{}
+	if s.db == nil {
+		return hosts
+	}
+	rows, err := s.db.Query(
+		`SELECT host FROM responses WHERE host IS NOT NULL AND host != ''
+		 UNION SELECT host FROM request_failures WHERE host IS NOT NULL AND host != ''
+		 ORDER BY host`,
+	)
+	if err != nil {
+		fmt.Println("Failed to load execution hosts:", err)
+		return hosts
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var host string
+		if err := rows.Scan(&host); err == nil {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// storedTimeLayouts are the forms created_at takes: time.Time values written by the driver and
+// CURRENT_TIMESTAMP defaults. The union in executionLogQuery hides the column type, so the
+// driver returns them as text.
+var storedTimeLayouts = []string{"2006-01-02 15:04:05.999999999 -0700 MST", "2006-01-02 15:04:05", time.RFC3339Nano}
+
+func parseStoredTime(value string) *time.Time {
+	for _, layout := range storedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func executionLogConditions(filter ExecutionLogFilter) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, filter.To.UTC())
+	}
+	if host := strings.ToLower(strings.TrimSpace(filter.Host)); host != "" {
+		conditions = append(conditions, "host = ?")
+		args = append(args, host)
+	}
+	if filter.Environment != "" {
+		conditions = append(conditions, "environment = ?")
+		args = append(args, filter.Environment)
+	}
+	if filter.CollectionID != "" {
+		conditions = append(conditions, "collection_id IN (SELECT id FROM scoped_collections)")
+	}
+
+	var classes []string
+	for _, class := range filter.StatusClasses {
+		switch class = strings.ToLower(strings.TrimSpace(class)); class {
+		case "error":
+			classes = append(classes, "kind = 'failure'")
+		case "1xx", "2xx", "3xx", "4xx", "5xx":
+			low := int(class[0]-'0') * 100
+			classes = append(classes, "(status_code >= ? AND status_code < ?)")
+			args = append(args, low, low+100)
+		default:
+			return "", nil, fmt.Errorf("unknown status class %q", class)
+		}
+	}
+	if len(classes) > 0 {
+		conditions = append(conditions, "("+strings.Join(classes, " OR ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", args, nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// executionOrigin records where an execution was sent, for the timeline.
+type executionOrigin struct {
+	method      string
+	url         string
+	environment string
+	// snapshot is the stored snapshot JSON.
+	snapshot string
+}
+
+func newExecutionOrigin(snapshot RequestSnapshot, stored string) executionOrigin {
+	return executionOrigin{method: snapshot.Method, url: snapshot.URL, environment: snapshot.Environment, snapshot: stored}
+}
+
+func (o executionOrigin) host() string {
+	parsed, err := url.Parse(o.url)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// executionAttempt tracks what ExecuteRequest knows about the outgoing request so far. stored
+// is set when the stored snapshot already exists, as when re-running a response.
+type executionAttempt struct {
+	snapshot RequestSnapshot
+	secrets  revealedSecrets
+	stored   string
+}
+
+func (s *RequestCRUDService) logExecutionFailure(requestID int, attempt *executionAttempt, cause error) {
+	if s.db == nil || requestID <= 0 {
+		return
+	}
+
+	stored := attempt.stored
+	display := attempt.snapshot
+	if stored == "" {
+		sealed, err := s.sealSnapshot(attempt.snapshot, attempt.secrets)
+		if err != nil {
+			// Without a sealed copy only the masked snapshot may be stored.
+			sealed = storedSnapshot{RequestSnapshot: maskSnapshot(attempt.snapshot, attempt.secrets)}
+		}
+		display = sealed.RequestSnapshot
+		encoded, _ := json.Marshal(sealed)
+		stored = string(encoded)
+	}
+	origin := newExecutionOrigin(display, stored)
+
+	_, err := s.db.Exec(
+		`INSERT INTO request_failures (request_id, method, url, host, environment, error, request_snapshot, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		requestID,
+		emptyStringToNullString(origin.method),
+		emptyStringToNullString(origin.url),
+		emptyStringToNullString(origin.host()),
+		emptyStringToNullString(origin.environment),
+		cause.Error(),
+		emptyStringToNullString(origin.snapshot),
+		time.Now().UTC(),
+	)
+	if err != nil {
+		fmt.Println("Failed to record request failure:", err)
+	}
+}
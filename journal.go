@@ -0,0 +1,315 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// journalLimit is how many operations Undo can step back through.
+const journalLimit = 200
+
+// journalColumns are the columns Undo and Redo put back, per journaled table. Both tables are
+// keyed by id.
+var journalColumns = map[string][]string{
+	"requests":    {"collection_id", "name", "description", "method", "url", "headers", "body", "body_type", "body_format", "auth", "sort_order", "deleted_at", "deleted_with"},
+	"collections": {"parent_collection", "sort_order", "headers", "auth", "deleted_at", "deleted_with"},
+}
+
+// journalScope selects the rows of a table an operation may change.
+type journalScope struct {
+	table     string
+	condition string
+	args      []interface{}
+}
+
+// journalChange is one row before and after an operation. Values are stored as text, which
+// SQLite converts back through the column affinity when they are written.
+type journalChange struct {
+	Table  string             `json:"table"`
+	ID     string             `json:"id"`
+	Before map[string]*string `json:"before"`
+	After  map[string]*string `json:"after"`
+}
+
+type JournalOperation struct {
+	ID          int       `json:"id"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// JournalState names the operations Undo and Redo would apply next; either is nil when there is
+// nothing to apply.
+type JournalState struct {
+	Undo *JournalOperation `json:"undo"`
+	Redo *JournalOperation `json:"redo"`
+}
+
+// journaled runs apply in a transaction and records the rows it changed within scopes.
+func (s *RequestCRUDService) journaled(description string, scopes []journalScope, apply func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start %s: %w", strings.ToLower(description), err)
+	}
+	defer tx.Rollback()
+
+	changes, err := snapshotJournalRows(tx, scopes...)
+	if err != nil {
+		return err
+	}
+	if err := apply(tx); err != nil {
+		return err
+	}
+	if err := recordJournalEntry(tx, description, changes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// snapshotJournalRows captures the rows within scopes before an operation changes them.
+func snapshotJournalRows(tx *sql.Tx, scopes ...journalScope) ([]journalChange, error) {
+	var changes []journalChange
+	seen := make(map[string]bool)
+	for _, scope := range scopes {
+		rows, err := queryJournalRows(tx, scope.table, scope.condition, scope.args...)
+		if err != nil {
+			return nil, err
+		}
+		for id, values := range rows {
+			if seen[scope.table+"/"+id] {
+				continue
+			}
+			seen[scope.table+"/"+id] = true
+			changes = append(changes, journalChange{Table: scope.table, ID: id, Before: values})
+		}
+	}
+	return changes, nil
+}
+
+// recordJournalEntry compares the snapshot with the rows as they are now and journals the
+// ones that changed. A new operation discards the operations that were undone.
+func recordJournalEntry(tx *sql.Tx, description string, snapshot []journalChange) error {
+	var changes []journalChange
+	for _, change := range snapshot {
+		rows, err := queryJournalRows(tx, change.Table, "id = ?", change.ID)
+		if err != nil {
+			return err
+		}
+		after, ok := rows[change.ID]
+		if !ok || len(changedJournalColumns(change.Table, change.Before, after)) == 0 {
+			continue
+		}
+		change.After = after
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+	for _, statement := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{"DELETE FROM operation_journal WHERE undone = 1", nil},
+		{"INSERT INTO operation_journal (description, changes) VALUES (?, ?)", []interface{}{description, string(encoded)}},
+		{"DELETE FROM operation_journal WHERE id NOT IN (SELECT id FROM operation_journal ORDER BY id DESC LIMIT ?)", []interface{}{journalLimit}},
+	} {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			return fmt.Errorf("failed to record %s: %w", strings.ToLower(description), err)
+		}
+	}
+	return nil
+}
+
+func queryJournalRows(tx *sql.Tx, table string, condition string, args ...interface{}) (map[string]map[string]*string, error) {
+	columns := journalColumns[table]
+	selected := []string{"CAST(id AS TEXT)"}
+	for _, column := range columns {
+		selected = append(selected, "CAST("+column+" AS TEXT)")
+	}
+	rows, err := tx.Query("SELECT "+strings.Join(selected, ", ")+" FROM "+table+" WHERE "+condition, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s for the journal: %w", table, err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]*string)
+	for rows.Next() {
+		values := make([]sql.NullString, len(selected))
+		targets := make([]interface{}, len(selected))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("failed to read %s for the journal: %w", table, err)
+		}
+		row := make(map[string]*string, len(columns))
+		for i, column := range columns {
+			row[column] = nullStringToPointer(values[i+1])
+		}
+		result[values[0].String] = row
+	}
+	return result, rows.Err()
+}
+
+func changedJournalColumns(table string, before map[string]*string, after map[string]*string) []string {
+	var changed []string
+	for _, column := range journalColumns[table] {
+		a, b := before[column], after[column]
+		if (a == nil) != (b == nil) || (a != nil && *a != *b) {
+			changed = append(changed, column)
+		}
+	}
+	return changed
+}
+
+func clearJournal(tx *sql.Tx) error {
+	if _, err := tx.Exec("DELETE FROM operation_journal"); err != nil {
+		return fmt.Errorf("failed to clear the undo history: %w", err)
+	}
+	return nil
+}
+
+// Undo reverts the most recent operation that has not been undone and returns it, or nil when
+// there is nothing to undo.
+func (s *RequestCRUDService) Undo() (*JournalOperation, error) {
+	return s.replayJournal(true)
+}
+
+// Redo reapplies the most recently undone operation and returns it, or nil when there is
+// nothing to redo.
+func (s *RequestCRUDService) Redo() (*JournalOperation, error) {
+	return s.replayJournal(false)
+}
+
+func (s *RequestCRUDService) GetJournalState() (JournalState, error) {
+	var state JournalState
+	var err error
+	if state.Undo, _, err = nextJournalOperation(s.db, true); err != nil {
+		return state, err
+	}
+	if state.Redo, _, err = nextJournalOperation(s.db, false); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func (s *RequestCRUDService) replayJournal(undo bool) (*JournalOperation, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start undo: %w", err)
+	}
+	defer tx.Rollback()
+
+	operation, changes, err := nextJournalOperation(tx, undo)
+	if err != nil || operation == nil {
+		return nil, err
+	}
+
+	// Undo walks the changes backwards so each row returns to the state it had first.
+	urls := make(map[int]string)
+	for i := range changes {
+		change, target := changes[i], changes[i].After
+		if undo {
+			change = changes[len(changes)-1-i]
+			target = change.Before
+		}
+
+		// A row edited since the operation, outside the journal or by an operation that was
+		// later discarded, would lose that edit, so the replay is refused instead.
+		expected := change.After
+		if !undo {
+			expected = change.Before
+		}
+		current, err := queryJournalRows(tx, change.Table, "id = ?", change.ID)
+		if err != nil {
+			return nil, err
+		}
+		columns := changedJournalColumns(change.Table, change.Before, change.After)
+		if row, ok := current[change.ID]; !ok || len(changedJournalColumns(change.Table, expected, row)) > 0 {
+			return nil, fmt.Errorf("cannot %s %s: the items it changed have been edited since", replayVerb(undo), strings.ToLower(operation.Description))
+		}
+
+		assignments := make([]string, len(columns))
+		args := make([]interface{}, len(columns))
+		for j, column := range columns {
+			assignments[j] = column + " = ?"
+			if value := target[column]; value != nil {
+				args[j] = *value
+			}
+		}
+		if _, err := tx.Exec("UPDATE "+change.Table+" SET "+strings.Join(assignments, ", ")+" WHERE id = ?", append(args, change.ID)...); err != nil {
+			return nil, fmt.Errorf("failed to replay %s: %w", strings.ToLower(operation.Description), err)
+		}
+
+		for _, column := range columns {
+			if change.Table != "requests" || column != "url" {
+				continue
+			}
+			if id, err := strconv.Atoi(change.ID); err == nil {
+				urls[id] = ""
+				if target["url"] != nil {
+					urls[id] = *target["url"]
+				}
+			}
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE operation_journal SET undone = ? WHERE id = ?", undo, operation.ID); err != nil {
+		return nil, fmt.Errorf("failed to update the undo history: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit undo: %w", err)
+	}
+
+	// Query parameters and path variables follow the restored URL.
+	for id, url := range urls {
+		s.syncRequestParams(id, url)
+		s.syncPathVariables(id, url)
+	}
+	return operation, nil
+}
+
+func replayVerb(undo bool) string {
+	if undo {
+		return "undo"
+	}
+	return "redo"
+}
+
+type journalQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// nextJournalOperation loads the operation Undo, or Redo when undo is false, would apply next.
+func nextJournalOperation(db journalQueryer, undo bool) (*JournalOperation, []journalChange, error) {
+	query := "SELECT id, description, changes, created_at FROM operation_journal WHERE undone = 0 ORDER BY id DESC LIMIT 1"
+	if !undo {
+		query = "SELECT id, description, changes, created_at FROM operation_journal WHERE undone = 1 ORDER BY id ASC LIMIT 1"
+	}
+
+	var operation JournalOperation
+	var encoded string
+	var createdAt sql.NullTime
+	err := db.QueryRow(query).Scan(&operation.ID, &operation.Description, &encoded, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load the undo history: %w", err)
+	}
+	operation.CreatedAt = createdAt.Time
+
+	var changes []journalChange
+	if err := json.Unmarshal([]byte(encoded), &changes); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode journal entry %d: %w", operation.ID, err)
+	}
+	return &operation, changes, nil
+}
@@ -25,23 +25,30 @@ func main() {
 
 	var openDbErr error
 	var db *sql.DB
-	db, openDbErr = sql.Open("sqlite", "./curlew_db.db")
+	db, openDbErr = sql.Open("sqlite", databaseDSN)
 	if openDbErr != nil {
 		log.Fatal(openDbErr)
 	}
 
-	files := []string{"sql/collections.sql", "sql/requests.sql", "sql/environments.sql", "sql/responses.sql", "sql/hotkey_binds.sql", "sql/app_state.sql", "sql/users.sql"}
+	files := []string{"sql/collections.sql", "sql/requests.sql", "sql/environments.sql", "sql/responses.sql", "sql/hotkey_binds.sql", "sql/app_state.sql", "sql/users.sql", "sql/collection_variables.sql", "sql/request_variables.sql", "sql/request_scripts.sql", "sql/collection_scripts.sql", "sql/cookies.sql", "sql/request_params.sql", "sql/request_path_variables.sql", "sql/collection_retention.sql", "sql/request_failures.sql", "sql/requests_fts.sql", "sql/responses_fts.sql", "sql/tags.sql", "sql/item_tags.sql", "sql/operation_journal.sql"}
 	for _, file := range files {
 		if err := executeSQLFromFile(db, file); err != nil {
 			log.Fatalf("Failed to execute %s: %v", file, err)
 		}
 	}
+	if err := migrateForeignKeys(db); err != nil {
+		fmt.Println("Failed to migrate foreign keys:", err)
+	}
 
-	crudService := &RequestCRUDService{db: db}
 	envarService := &EnvarService{}
+	crudService := &RequestCRUDService{db: db, envars: envarService}
 	userService := &UserService{db: db}
 	fileService := &FileService{db: db}
 	appStateService := NewAppStateService(db)
+	workspaceService := &WorkspaceService{db: db, envars: envarService}
+	cookieService := &CookieService{db: db}
+	historyService := &HistoryService{db: db}
+	trashService := &TrashService{db: db}
 
 	crudService.Init()
 
@@ -54,6 +61,10 @@ func main() {
 			application.NewService(envarService),
 			application.NewService(userService),
 			application.NewService(appStateService),
+			application.NewService(workspaceService),
+			application.NewService(cookieService),
+			application.NewService(historyService),
+			application.NewService(trashService),
 		},
 		Assets: application.AssetOptions{
 			Handler: application.AssetFileServerFS(assets),
@@ -73,6 +84,8 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go envarService.InitEnvarWatch(ctx)
+	go crudService.runRetentionJanitor(ctx)
+	go trashService.runTrashJanitor(ctx)
 
 	window := app.NewWebviewWindowWithOptions(application.WebviewWindowOptions{
 		Title: "Curlew",
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type PathVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+// pathVariablePattern matches a whole path segment such as :userId.
+var pathVariablePattern = regexp.MustCompile(`^:([A-Za-z_][A-Za-z0-9_-]*)$`)
+
+func (s *RequestCRUDService) GetRequestPathVariables(requestID int) []PathVariable {
+	if s.db == nil {
+		return []PathVariable{}
+	}
+	variables, err := queryPathVariables(s.db, requestID)
+	if err != nil {
+		fmt.Println("Failed to load path variables:", err)
+	}
+	return variables
+}
+
+func queryPathVariables(db sqlQueryExecer, requestID int) ([]PathVariable, error) {
+	variables := []PathVariable{}
+	rows, err := db.Query("SELECT key, value, description FROM request_path_variables WHERE request_id = ? ORDER BY key", requestID)
+	if err != nil {
+		return variables, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			v           PathVariable
+			value       sql.NullString
+			description sql.NullString
+		)
+		if err := rows.Scan(&v.Key, &value, &description); err != nil {
+			fmt.Println("Failed to scan path variable:", err)
+			continue
+		}
+		v.Value = value.String
+		v.Description = description.String
+		variables = append(variables, v)
+	}
+	return variables, rows.Err()
+}
+
+func (s *RequestCRUDService) SetRequestPathVariables(requestID int, variables []PathVariable) error {
+	if s.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start path variable transaction: %w", err)
+	}
+	if err := writePathVariables(tx, requestID, variables); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit path variables: %w", err)
+	}
+	return nil
+}
+
+// syncPathVariables rebuilds the path variables of a request after its URL was edited.
+func (s *RequestCRUDService) syncPathVariables(requestID int, rawURL string) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		fmt.Println("Failed to sync path variables:", err)
+		return
+	}
+	if err := rebuildPathVariables(tx, requestID, rawURL); err != nil {
+		tx.Rollback()
+		fmt.Println("Failed to sync path variables:", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Println("Failed to sync path variables:", err)
+	}
+}
+
+// rebuildPathVariables keeps one row per :name segment of the URL, dropping variables the URL
+// no longer uses and adding empty ones for new segments.
+func rebuildPathVariables(tx sqlQueryExecer, requestID int, rawURL string) error {
+	current, err := queryPathVariables(tx, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to load path variables: %w", err)
+	}
+	existing := make(map[string]PathVariable)
+	for _, v := range current {
+		existing[v.Key] = v
+	}
+
+	var variables []PathVariable
+	for _, name := range pathVariableNames(rawURL) {
+		if v, ok := existing[name]; ok {
+			variables = append(variables, v)
+		} else {
+			variables = append(variables, PathVariable{Key: name})
+		}
+	}
+	return writePathVariables(tx, requestID, variables)
+}
+
+func writePathVariables(tx sqlExecer, requestID int, variables []PathVariable) error {
+	if _, err := tx.Exec("DELETE FROM request_path_variables WHERE request_id = ?", requestID); err != nil {
+		return fmt.Errorf("failed to clear path variables: %w", err)
+	}
+	return insertPathVariables(tx, requestID, variables)
+}
+
+func insertPathVariables(tx sqlExecer, requestID int, variables []PathVariable) error {
+	for _, v := range variables {
+		key := strings.TrimPrefix(strings.TrimSpace(v.Key), ":")
+		if key == "" {
+			continue
+		}
+		_, err := tx.Exec(
+			`INSERT INTO request_path_variables (request_id, key, value, description) VALUES (?, ?, ?, ?)
+			 ON CONFLICT(request_id, key) DO UPDATE SET value = excluded.value, description = excluded.description`,
+			requestID,
+			key,
+			v.Value,
+			emptyStringToNullString(v.Description),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save path variable %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// urlPathStart returns the index where the path of a URL without its query begins, skipping
+// the scheme and authority so a port is never mistaken for a path variable.
+func urlPathStart(base string) int {
+	offset := 0
+	if i := strings.Index(base, "://"); i != -1 {
+		offset = i + 3
+	}
+	if i := strings.Index(base[offset:], "/"); i != -1 {
+		return offset + i
+	}
+	return len(base)
+}
+
+func pathVariableNames(rawURL string) []string {
+	base, _, _ := splitURLQuery(rawURL)
+	var names []string
+	seen := make(map[string]bool)
+	for _, segment := range strings.Split(base[urlPathStart(base):], "/") {
+		if m := pathVariablePattern.FindStringSubmatch(segment); m != nil && !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// substitutePathVariables replaces :name segments with their URL-encoded values. Values may
+// contain placeholders; a segment without a value is an error rather than being sent as is.
+func substitutePathVariables(base string, variables []PathVariable, lookup variableLookup) (string, error) {
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		values[v.Key] = v.Value
+	}
+
+	start := urlPathStart(base)
+	segments := strings.Split(base[start:], "/")
+	for i, segment := range segments {
+		m := pathVariablePattern.FindStringSubmatch(segment)
+		if m == nil {
+			continue
+		}
+		value, err := expandPlaceholders(values[m[1]], lookup)
+		if err != nil {
+			return "", err
+		}
+		if value == "" {
+			return "", fmt.Errorf("path variable :%s has no value", m[1])
+		}
+		if placeholderPattern.MatchString(value) {
+			return "", fmt.Errorf("path variable :%s uses an undefined variable: %s", m[1], value)
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	return base[:start] + strings.Join(segments, "/"), nil
+}
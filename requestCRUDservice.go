@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,8 +16,16 @@ import (
 )
 
 type RequestCRUDService struct {
-	db  *sql.DB
-	app *application.App
+	db     *sql.DB
+	app    *application.App
+	envars *EnvarService
+
+	// runtimeVars holds variables set by scripts through pm.variables. They live until the app
+	// exits or ClearRuntimeVariables is called.
+	runtimeMu   sync.Mutex
+	runtimeVars map[string]string
+
+	retentionMu sync.Mutex
 }
 
 type Request struct {
@@ -33,14 +42,19 @@ type Request struct {
 	BodyFormat     *string   `json:"bodyFormat"`
 	Auth           *string   `json:"auth"`
 	SortOrder      *int      `json:"sortOrder"`
+	Favorite       bool      `json:"favorite"`
+	Tags           []string  `json:"tags"`
 	Response       *Response `json:"response,omitempty"`
 }
 
 type Collection struct {
-	ID                 string  `json:"id"`
-	Name               string  `json:"name"`
-	Description        string  `json:"description"`
-	ParentCollectionId *string `json:"parentCollectionId"`
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	ParentCollectionId *string  `json:"parentCollectionId"`
+	SortOrder          *int     `json:"sortOrder"`
+	Favorite           bool     `json:"favorite"`
+	Tags               []string `json:"tags"`
 }
 
 type Response struct {
@@ -51,10 +65,16 @@ type Response struct {
 	RuntimeMS  int        `json:"runtimeMS"`
 	RequestID  int        `json:"requestID"`
 	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	Pinned     bool       `json:"pinned"`
 }
 
 func (s *RequestCRUDService) Init() {
 	s.ensureResponsesSchema()
+	s.ensureFavoriteColumns()
+	s.ensureTrashColumns()
+	s.ensureCollectionSortColumn()
+	s.ensureCollectionDefaultColumns()
+	s.ensureSearchIndex()
 }
 
 func (s *RequestCRUDService) ensureResponsesSchema() {
@@ -62,32 +82,11 @@ func (s *RequestCRUDService) ensureResponsesSchema() {
 		return
 	}
 
-	hasCreatedAt := false
-	rows, err := s.db.Query(`PRAGMA table_info(responses)`)
-	if err != nil {
-		fmt.Println("Failed to inspect responses schema:", err)
-	} else {
-		defer rows.Close()
-		for rows.Next() {
-			var cid int
-			var name, ctype string
-			var notnull int
-			var dfltValue sql.NullString
-			var pk int
-			if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
-				continue
-			}
-			if name == "created_at" {
-				hasCreatedAt = true
-				break
-			}
-		}
-	}
-
-	if !hasCreatedAt {
-		if _, err := s.db.Exec(`ALTER TABLE responses ADD COLUMN created_at DATETIME DEFAULT CURRENT_TIMESTAMP`); err != nil {
-			fmt.Println("Failed to add created_at to responses:", err)
-		}
+	ensureColumn(s.db, "responses", "created_at", "DATETIME DEFAULT CURRENT_TIMESTAMP")
+	ensureColumn(s.db, "responses", "pinned", "INTEGER NOT NULL DEFAULT 0")
+	ensureColumn(s.db, "responses", "request_snapshot", "TEXT")
+	for _, column := range []string{"method", "url", "host", "environment"} {
+		ensureColumn(s.db, "responses", column, "TEXT")
 	}
 
 	if _, err := s.db.Exec(
@@ -101,6 +100,39 @@ func (s *RequestCRUDService) ensureResponsesSchema() {
 	}
 }
 
+// ensureColumn adds a column to a table created by an older version of the schema. The
+// PRAGMA rows are closed before altering the table so the connection is not held busy.
+func ensureColumn(db *sql.DB, table string, column string, definition string) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		fmt.Printf("Failed to inspect %s schema: %v\n", table, err)
+		return
+	}
+
+	found := false
+	for rows.Next() {
+		var cid int
+		var name, ctype string
+		var notnull int
+		var dfltValue sql.NullString
+		var pk int
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
+			continue
+		}
+		if name == column {
+			found = true
+			break
+		}
+	}
+	rows.Close()
+
+	if !found {
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+			fmt.Printf("Failed to add %s to %s: %v\n", column, table, err)
+		}
+	}
+}
+
 func (s *RequestCRUDService) getResponseHistoryLimit() int {
 	ttl, err := loadResponseHistoryTTL(s.db)
 	if err != nil {
@@ -110,7 +142,7 @@ func (s *RequestCRUDService) getResponseHistoryLimit() int {
 	return ttl
 }
 
-func (s *RequestCRUDService) logResponseHistory(requestID int, statusCode int, headers string, body string, runtimeMS int, createdAt *time.Time) {
+func (s *RequestCRUDService) logResponseHistory(requestID int, statusCode int, headers string, body string, runtimeMS int, createdAt *time.Time, origin executionOrigin) {
 	if s.db == nil || requestID <= 0 {
 		return
 	}
@@ -118,24 +150,34 @@ func (s *RequestCRUDService) logResponseHistory(requestID int, statusCode int, h
 	var err error
 	if createdAt != nil {
 		_, err = s.db.Exec(
-			`INSERT INTO responses (status_code, headers, body, runtime_ms, request_id, created_at)
-			 VALUES (?, ?, ?, ?, ?, ?)`,
+			`INSERT INTO responses (status_code, headers, body, runtime_ms, request_id, created_at, request_snapshot, method, url, host, environment)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			statusCode,
 			headers,
 			body,
 			runtimeMS,
 			requestID,
 			createdAt.UTC(),
+			emptyStringToNullString(origin.snapshot),
+			emptyStringToNullString(origin.method),
+			emptyStringToNullString(origin.url),
+			emptyStringToNullString(origin.host()),
+			emptyStringToNullString(origin.environment),
 		)
 	} else {
 		_, err = s.db.Exec(
-			`INSERT INTO responses (status_code, headers, body, runtime_ms, request_id)
-			 VALUES (?, ?, ?, ?, ?)`,
+			`INSERT INTO responses (status_code, headers, body, runtime_ms, request_id, request_snapshot, method, url, host, environment)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			statusCode,
 			headers,
 			body,
 			runtimeMS,
 			requestID,
+			emptyStringToNullString(origin.snapshot),
+			emptyStringToNullString(origin.method),
+			emptyStringToNullString(origin.url),
+			emptyStringToNullString(origin.host()),
+			emptyStringToNullString(origin.environment),
 		)
 	}
 	if err != nil {
@@ -143,25 +185,12 @@ func (s *RequestCRUDService) logResponseHistory(requestID int, statusCode int, h
 		return
 	}
 
-	limit := s.getResponseHistoryLimit()
+	// Pinned responses are never pruned and do not count towards the limit.
+	limit, _ := s.effectiveRetention(requestID)
 	if limit <= 0 {
 		return
 	}
-
-	_, err = s.db.Exec(
-		`DELETE FROM responses
-		 WHERE request_id = ?
-		   AND id NOT IN (
-		       SELECT id FROM responses
-		       WHERE request_id = ?
-		       ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC
-		       LIMIT ?
-		   )`,
-		requestID,
-		requestID,
-		limit,
-	)
-	if err != nil {
+	if _, _, err := trimResponseHistory(s.db, requestID, limit); err != nil {
 		fmt.Println("Failed to enforce response history TTL:", err)
 	}
 }
@@ -180,15 +209,16 @@ func (s *RequestCRUDService) GetRequest(id int) Request {
 		bodyType       sql.NullString
 		bodyFormat     sql.NullString
 		auth           sql.NullString
+		favorite       bool
 	)
 
 	s.app.EmitEvent("test")
-	err := s.db.QueryRow("SELECT requests.id, requests.collection_id, collections.name as 'collection_name', requests.name, requests.description, requests.method, requests.url, requests.headers, requests.body, requests.body_type, requests.body_format, requests.auth FROM requests LEFT JOIN collections ON collections.id = requests.collection_id WHERE requests.id = ?", id).
-		Scan(&requestID, &collectionID, &collectionName, &name, &description, &method, &url, &headers, &body, &bodyType, &bodyFormat, &auth)
+	err := s.db.QueryRow("SELECT requests.id, requests.collection_id, collections.name as 'collection_name', requests.name, requests.description, requests.method, requests.url, requests.headers, requests.body, requests.body_type, requests.body_format, requests.auth, requests.favorite FROM requests LEFT JOIN collections ON collections.id = requests.collection_id WHERE requests.id = ?", id).
+		Scan(&requestID, &collectionID, &collectionName, &name, &description, &method, &url, &headers, &body, &bodyType, &bodyFormat, &auth, &favorite)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			fmt.Println("No request found with ID %d", id)
+			fmt.Printf("No request found with ID %d\n", id)
 			return Request{}
 		}
 		return Request{}
@@ -207,12 +237,14 @@ func (s *RequestCRUDService) GetRequest(id int) Request {
 		BodyType:       nullStringToPointer(bodyType),
 		BodyFormat:     nullStringToPointer(bodyFormat),
 		Auth:           nullStringToPointer(auth),
+		Favorite:       favorite,
+		Tags:           s.GetRequestTags(requestID),
 	}
 
 	var resp Response
 	var createdAt sql.NullTime
-	respErr := s.db.QueryRow("SELECT id, status_code, headers, body, runtime_ms, request_id, created_at FROM responses WHERE request_id = ? ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC LIMIT 1", requestID).
-		Scan(&resp.ID, &resp.StatusCode, &resp.Headers, &resp.Body, &resp.RuntimeMS, &resp.RequestID, &createdAt)
+	respErr := s.db.QueryRow("SELECT id, status_code, headers, body, runtime_ms, request_id, created_at, pinned FROM responses WHERE request_id = ? ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC LIMIT 1", requestID).
+		Scan(&resp.ID, &resp.StatusCode, &resp.Headers, &resp.Body, &resp.RuntimeMS, &resp.RequestID, &createdAt, &resp.Pinned)
 	if respErr == nil {
 		if createdAt.Valid {
 			t := createdAt.Time
@@ -230,7 +262,7 @@ func (s *RequestCRUDService) GetResponseHistory(requestID int) []Response {
 	}
 
 	rows, err := s.db.Query(
-		`SELECT id, status_code, headers, body, runtime_ms, request_id, created_at
+		`SELECT id, status_code, headers, body, runtime_ms, request_id, created_at, pinned
 		 FROM responses
 		 WHERE request_id = ?
 		 ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC`,
@@ -246,7 +278,7 @@ func (s *RequestCRUDService) GetResponseHistory(requestID int) []Response {
 	for rows.Next() {
 		var resp Response
 		var createdAt sql.NullTime
-		if err := rows.Scan(&resp.ID, &resp.StatusCode, &resp.Headers, &resp.Body, &resp.RuntimeMS, &resp.RequestID, &createdAt); err != nil {
+		if err := rows.Scan(&resp.ID, &resp.StatusCode, &resp.Headers, &resp.Body, &resp.RuntimeMS, &resp.RequestID, &createdAt, &resp.Pinned); err != nil {
 			fmt.Println("Failed to scan response history row:", err)
 			continue
 		}
@@ -260,21 +292,49 @@ func (s *RequestCRUDService) GetResponseHistory(requestID int) []Response {
 	return history
 }
 
+// DeleteRequest moves a request to the trash; TrashService restores or purges it.
 func (s *RequestCRUDService) DeleteRequest(id int) error {
-	_, err := s.db.Exec("DELETE FROM requests WHERE id = ?", id)
-	if err != nil {
-		fmt.Println("Error deleting request")
-		return err
-	}
-	return nil
+	return s.journaled("Delete request", []journalScope{siblingRequestsScope(id)}, func(tx *sql.Tx) error {
+		if err := closeSortOrderGap(tx, id); err != nil {
+			return err
+		}
+		result, err := tx.Exec("UPDATE requests SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL", id)
+		if err != nil {
+			fmt.Println("Error deleting request")
+			return fmt.Errorf("failed to delete request %d: %w", id, err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return fmt.Errorf("request %d not found", id)
+		}
+		return nil
+	})
 }
 
+// GetAllRequestsList lists every request, or with tags only those carrying all of them,
+// directly or through one of their collections.
 // TODO: lock down response object, replace "" with nulls etc
-func (s *RequestCRUDService) GetAllRequestsList() []Request {
+func (s *RequestCRUDService) GetAllRequestsList(tags []string) []Request {
 	s.normalizeRequestSortOrder()
+	return s.listRequests(tags)
+}
+
+func (s *RequestCRUDService) listRequests(tags []string) []Request {
+	query := "SELECT r.id, r.collection_id, r.name, r.description, r.method, r.url, r.sort_order, r.favorite FROM requests r"
+	conditions := []string{"r.deleted_at IS NULL"}
+	var args []interface{}
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			conditions = append(conditions, taggedRequestCondition)
+			args = append(args, tag, tag)
+		}
+	}
+	query += " WHERE " + strings.Join(conditions, " AND ")
+	query += " ORDER BY r.collection_id, COALESCE(r.sort_order, r.id)"
+
+	requestTags := s.tagsByItem("request_id")
 
 	var requests []Request
-	rows, err := s.db.Query("SELECT id, collection_id, name, description, method, url, sort_order FROM requests ORDER BY collection_id, COALESCE(sort_order, id)")
+	rows, err := s.db.Query(query, args...)
 
 	if err != nil {
 		fmt.Print("No requests found")
@@ -291,9 +351,10 @@ func (s *RequestCRUDService) GetAllRequestsList() []Request {
 			method       sql.NullString
 			url          sql.NullString
 			sortOrder    sql.NullInt64
+			favorite     bool
 		)
 
-		err := rows.Scan(&requestID, &collectionID, &name, &description, &method, &url, &sortOrder)
+		err := rows.Scan(&requestID, &collectionID, &name, &description, &method, &url, &sortOrder, &favorite)
 		if err != nil {
 			fmt.Println("Failed to scan request:", err)
 			continue
@@ -306,6 +367,8 @@ func (s *RequestCRUDService) GetAllRequestsList() []Request {
 			Method:       nullStringToPointer(method),
 			URL:          nullStringToPointer(url),
 			SortOrder:    nullIntToPointer(sortOrder),
+			Favorite:     favorite,
+			Tags:         requestTags[fmt.Sprint(requestID)],
 		}
 		requests = append(requests, r)
 	}
@@ -313,28 +376,76 @@ func (s *RequestCRUDService) GetAllRequestsList() []Request {
 	return requests
 }
 
-func (s *RequestCRUDService) ExecuteRequest(requestID int, method string, requestUrl string, headersIn string, body string, bodyType string, bodyFormat string, auth string) (json.RawMessage, error) {
-	var bodyReader io.Reader
+func (s *RequestCRUDService) ExecuteRequest(requestID int, method string, requestUrl string, headersIn string, body string, bodyType string, bodyFormat string, auth string, environment string) (json.RawMessage, error) {
+	attempt := &executionAttempt{
+		snapshot: RequestSnapshot{Method: method, URL: requestUrl, Environment: environment},
+		secrets:  make(revealedSecrets),
+	}
+	result, err := s.executeRequest(attempt, requestID, method, requestUrl, headersIn, body, bodyType, bodyFormat, auth, environment)
+	if err != nil {
+		s.logExecutionFailure(requestID, attempt, err)
+	}
+	return result, err
+}
+
+// executeRequest resolves and sends a request, keeping attempt up to date with what would be
+// sent so a failure can be recorded with as much detail as is known at that point.
+func (s *RequestCRUDService) executeRequest(attempt *executionAttempt, requestID int, method string, requestUrl string, headersIn string, body string, bodyType string, bodyFormat string, auth string, environment string) (json.RawMessage, error) {
+	var sentBody string
 
-	var headers []map[string]string
-	if err := json.Unmarshal([]byte(headersIn), &headers); err != nil {
-		var headerMap map[string]string
-		if err := json.Unmarshal([]byte(headersIn), &headerMap); err != nil {
-			headers = []map[string]string{}
-		} else {
-			headers = make([]map[string]string, 0, len(headerMap))
-			for k, v := range headerMap {
-				headers = append(headers, map[string]string{"key": k, "value": v})
+	entries := parseHeaderEntries(headersIn)
+	if requestID > 0 && s.db != nil {
+		var err error
+		if entries, auth, err = s.applyCollectionDefaults(requestID, entries, auth); err != nil {
+			return encodeError(err), err
+		}
+	}
+	headers := enabledHeaders(entries)
+
+	var preRequestResult *ScriptResult
+	var preRequestScripts, postResponseScripts []string
+	if requestID > 0 && s.db != nil {
+		preRequestScripts, postResponseScripts = s.requestScripts(requestID)
+	}
+	scripted := &scriptRequest{Method: method, URL: requestUrl, Headers: headers, Body: body, Auth: auth}
+	if result, ran := s.runScripts(preRequestScripts, requestID, environment, scripted, nil); ran {
+		if result.Error != "" {
+			err := fmt.Errorf("pre-request script failed: %s", result.Error)
+			return encodeError(err), err
+		}
+		preRequestResult = &result
+		method, requestUrl, headers, body, auth = scripted.Method, scripted.URL, scripted.Headers, scripted.Body, scripted.Auth
+	}
+
+	// Placeholders are resolved here so decrypted secrets never leave the backend. Dynamic
+	// variables and template functions share one evaluator, so $timestamp is stable per request.
+	secrets := attempt.secrets
+	lookup := secretTrackingLookup(s.recordingVariableLookup(requestID, environment, secrets), secrets)
+	var err error
+	if requestUrl, err = s.resolveRequestURL(requestID, requestUrl, lookup); err != nil {
+		return encodeError(err), err
+	}
+	attempt.snapshot.Method, attempt.snapshot.URL = method, requestUrl
+	for i, header := range headers {
+		for _, field := range []string{"key", "value"} {
+			if headers[i][field], err = expandPlaceholders(header[field], lookup); err != nil {
+				return encodeError(err), err
 			}
 		}
 	}
+	if body, err = expandPlaceholders(body, lookup); err != nil {
+		return encodeError(err), err
+	}
+	if auth, err = expandPlaceholders(auth, lookup); err != nil {
+		return encodeError(err), err
+	}
 
 	switch bodyType {
 	case "none":
-		bodyReader = nil
+		sentBody = ""
 
 	case "raw":
-		bodyReader = bytes.NewBufferString(body)
+		sentBody = body
 
 		contentType := "text/plain" // Default
 		switch bodyFormat {
@@ -374,7 +485,7 @@ func (s *RequestCRUDService) ExecuteRequest(requestID int, method string, reques
 			return encodeError(fmt.Errorf("failed to encode GraphQL request: %w", err)), err
 		}
 
-		bodyReader = bytes.NewReader(graphqlBody)
+		sentBody = string(graphqlBody)
 
 		foundContentType := false
 		for i, header := range headers {
@@ -389,41 +500,100 @@ func (s *RequestCRUDService) ExecuteRequest(requestID int, method string, reques
 		}
 
 	default:
-		bodyReader = bytes.NewBufferString(body)
+		sentBody = body
+	}
+
+	outgoing := make(http.Header)
+	for _, header := range headers {
+		if header["key"] != "" {
+			outgoing.Set(header["key"], header["value"])
+		}
+	}
+	if auth != "" {
+		outgoing.Set("Authorization", auth)
 	}
 
-	httpReq, err := http.NewRequest(method, requestUrl, bodyReader)
+	snapshot := RequestSnapshot{
+		Method:      method,
+		URL:         requestUrl,
+		Headers:     outgoing,
+		Body:        sentBody,
+		Environment: environment,
+	}
+	attempt.snapshot = snapshot
+	resp, bodyBytes, requestTime, err := s.sendSnapshot(snapshot, environment)
 	if err != nil {
 		return encodeError(err), err
 	}
 
-	//
-	for _, header := range headers {
-		if header["key"] != "" {
-			httpReq.Header.Set(header["key"], header["value"])
+	var scriptResults map[string]ScriptResult
+	if preRequestResult != nil {
+		scriptResults = map[string]ScriptResult{"preRequest": *preRequestResult}
+	}
+	sent := &scriptRequest{Method: method, URL: requestUrl, Headers: headers, Body: body, Auth: auth}
+	received := &scriptResponse{
+		Code:         resp.StatusCode,
+		Status:       http.StatusText(resp.StatusCode),
+		Headers:      resp.Header,
+		Body:         string(bodyBytes),
+		ResponseTime: requestTime,
+	}
+	if result, ran := s.runScripts(postResponseScripts, requestID, environment, sent, received); ran {
+		if scriptResults == nil {
+			scriptResults = make(map[string]ScriptResult)
 		}
+		scriptResults["postResponse"] = result
 	}
 
-	if auth != "" {
-		httpReq.Header.Set("Authorization", auth)
+	stored, err := s.sealSnapshot(snapshot, secrets)
+	if err != nil {
+		return encodeError(err), err
+	}
+	storedJSON, err := json.Marshal(stored)
+	if err != nil {
+		return encodeError(err), err
+	}
+	return s.recordResponse(requestID, resp, bodyBytes, requestTime, stored.RequestSnapshot, string(storedJSON), scriptResults)
+}
+
+// sendSnapshot sends a fully resolved request. Cookies from the environment's jar are added
+// by the client and are not part of the snapshot.
+func (s *RequestCRUDService) sendSnapshot(snapshot RequestSnapshot, environment string) (*http.Response, []byte, int64, error) {
+	httpReq, err := http.NewRequest(snapshot.Method, snapshot.URL, bytes.NewBufferString(snapshot.Body))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for key, values := range snapshot.Headers {
+		for _, value := range values {
+			httpReq.Header.Add(key, value)
+		}
 	}
 
 	client := http.DefaultClient
+	if s.db != nil {
+		client = &http.Client{Jar: newCookieJar(s.db, environment)}
+	}
 	startTime := time.Now()
 	resp, err := client.Do(httpReq)
 	endTime := time.Now()
 	requestTime := endTime.Sub(startTime).Milliseconds()
 
 	if err != nil {
-		return encodeError(err), err
+		return nil, nil, 0, err
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return encodeError(err), err
+		return nil, nil, 0, err
 	}
+	return resp, bodyBytes, requestTime, nil
+}
 
+// recordResponse stores a response in the history together with the stored snapshot of the
+// request that produced it and returns the JSON sent to the frontend, which only ever carries
+// the masked snapshot.
+func (s *RequestCRUDService) recordResponse(requestID int, resp *http.Response, bodyBytes []byte, requestTime int64, snapshot RequestSnapshot, storedJSON string, scriptResults map[string]ScriptResult) (json.RawMessage, error) {
 	headersJSON, err := json.Marshal(resp.Header)
 	if err != nil {
 		return encodeError(err), err
@@ -439,13 +609,18 @@ func (s *RequestCRUDService) ExecuteRequest(requestID int, method string, reques
 
 	createdAt := time.Now().UTC()
 
-	responseJSON, err := json.Marshal(map[string]interface{}{
-		"statusCode": resp.StatusCode,
-		"headers":    json.RawMessage(headersJSON),
-		"body":       bodyJSON,
-		"runtimeMS":  int(requestTime),
-		"createdAt":  createdAt,
-	})
+	responseFields := map[string]interface{}{
+		"statusCode":      resp.StatusCode,
+		"headers":         json.RawMessage(headersJSON),
+		"body":            bodyJSON,
+		"runtimeMS":       int(requestTime),
+		"createdAt":       createdAt,
+		"requestSnapshot": snapshot,
+	}
+	if scriptResults != nil {
+		responseFields["scripts"] = scriptResults
+	}
+	responseJSON, err := json.Marshal(responseFields)
 	if err != nil {
 		return encodeError(err), err
 	}
@@ -453,11 +628,75 @@ func (s *RequestCRUDService) ExecuteRequest(requestID int, method string, reques
 	responseStorage := string(bodyBytes)
 	headersStorage := string(headersJSON)
 
-	s.logResponseHistory(requestID, resp.StatusCode, headersStorage, responseStorage, int(requestTime), &createdAt)
+	s.logResponseHistory(requestID, resp.StatusCode, headersStorage, responseStorage, int(requestTime), &createdAt, newExecutionOrigin(snapshot, storedJSON))
 
 	return responseJSON, nil
 }
 
+func (s *RequestCRUDService) variableLookup(requestID int, environment string) variableLookup {
+	return s.recordingVariableLookup(requestID, environment, nil)
+}
+
+// recordingVariableLookup is variableLookup that also records every secret it decrypts in
+// revealed, when revealed is not nil.
+func (s *RequestCRUDService) recordingVariableLookup(requestID int, environment string, revealed revealedSecrets) variableLookup {
+	layers, err := s.variableLayers(requestID, environment)
+	if err != nil {
+		fmt.Println("Failed to load variables for execution:", err)
+	}
+
+	vars := make(map[string]string)
+	for _, layer := range layers {
+		for key, value := range layer.values {
+			vars[key] = value
+		}
+	}
+	return func(name string) (string, bool, error) {
+		raw, ok := vars[name]
+		if !ok {
+			return "", false, nil
+		}
+		if s.envars == nil {
+			return raw, true, nil
+		}
+		value, err := s.envars.revealValue(raw)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to resolve secret %s: %w", name, err)
+		}
+		if revealed != nil && isSecretValue(raw) {
+			revealed[name] = value
+		}
+		return value, true, nil
+	}
+}
+
+// resolveRequestURL substitutes path variables and expands placeholders in the URL. The query
+// is sent as written in the editor, which save keeps in sync with the params table: literal
+// text keeps its own encoding apart from characters a query cannot hold, and substituted values
+// are escaped so they cannot break the path or the query string.
+func (s *RequestCRUDService) resolveRequestURL(requestID int, requestUrl string, lookup variableLookup) (string, error) {
+	base, query, _ := splitURLQuery(requestUrl)
+	var pathVariables []PathVariable
+	if requestID > 0 && s.db != nil {
+		pathVariables = s.GetRequestPathVariables(requestID)
+	}
+
+	base, err := substitutePathVariables(base, pathVariables, lookup)
+	if err != nil {
+		return "", err
+	}
+	if base, err = expandPlaceholders(base, lookup); err != nil {
+		return "", err
+	}
+	if query == "" {
+		return base, nil
+	}
+	if query, err = expandQueryPlaceholders(query, lookup); err != nil {
+		return "", err
+	}
+	return base + "?" + query, nil
+}
+
 func encodeError(err error) json.RawMessage {
 	errorJSON, _ := json.Marshal(map[string]string{
 		"error": err.Error(),
@@ -492,7 +731,7 @@ func (s *RequestCRUDService) SaveRequest(collectionId *string, name string, desc
 	}
 
 	err = s.db.QueryRow("INSERT INTO requests (collection_id, name, description, method, url, headers, body, body_type, body_format, auth, sort_order) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING ID",
-		newRequest.CollectionID,
+		collectionRef(newRequest.CollectionID),
 		emptyStringToNullString(name),
 		emptyStringToNullString(description),
 		emptyStringToNullString(method),
@@ -509,10 +748,12 @@ func (s *RequestCRUDService) SaveRequest(collectionId *string, name string, desc
 	}
 
 	newRequest.SortOrder = intPointer(nextSortOrder)
+	s.syncRequestParams(newRequest.ID, url)
+	s.syncPathVariables(newRequest.ID, url)
 
 	// Insert response if provided
 	if response != nil {
-		s.logResponseHistory(newRequest.ID, response.StatusCode, response.Headers, response.Body, response.RuntimeMS, response.CreatedAt)
+		s.logResponseHistory(newRequest.ID, response.StatusCode, response.Headers, response.Body, response.RuntimeMS, response.CreatedAt, executionOrigin{})
 	}
 
 	return newRequest
@@ -643,7 +884,7 @@ func (s *RequestCRUDService) DuplicateRequest(requestID int) (Request, error) {
 
 	var latestResponse *Response
 	rows, err := tx.Query(`
-		SELECT status_code, headers, body, runtime_ms, created_at
+		SELECT status_code, headers, body, runtime_ms, created_at, request_snapshot
 		FROM responses
 		WHERE request_id = ?
 		ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP), id
@@ -660,9 +901,10 @@ func (s *RequestCRUDService) DuplicateRequest(requestID int) (Request, error) {
 			bodyVal    sql.NullString
 			runtimeVal sql.NullInt64
 			createdAt  sql.NullTime
+			snapshot   sql.NullString
 		)
 
-		if err = rows.Scan(&statusCode, &headersVal, &bodyVal, &runtimeVal, &createdAt); err != nil {
+		if err = rows.Scan(&statusCode, &headersVal, &bodyVal, &runtimeVal, &createdAt, &snapshot); err != nil {
 			return Request{}, fmt.Errorf("failed to scan response for duplication: %w", err)
 		}
 
@@ -692,14 +934,15 @@ func (s *RequestCRUDService) DuplicateRequest(requestID int) (Request, error) {
 		}
 
 		result, execErr := tx.Exec(
-			`INSERT INTO responses (status_code, headers, body, runtime_ms, request_id, created_at)
-			 VALUES (?, ?, ?, ?, ?, ?)`,
+			`INSERT INTO responses (status_code, headers, body, runtime_ms, request_id, created_at, request_snapshot)
+			 VALUES (?, ?, ?, ?, ?, ?, ?)`,
 			statusInsert,
 			headersInsert,
 			bodyInsert,
 			runtimeInsert,
 			newRequestID,
 			createdInsert,
+			snapshot,
 		)
 		if execErr != nil {
 			return Request{}, fmt.Errorf("failed to duplicate response history: %w", execErr)
@@ -732,6 +975,10 @@ func (s *RequestCRUDService) DuplicateRequest(requestID int) (Request, error) {
 		return Request{}, fmt.Errorf("failed during response duplication: %w", err)
 	}
 
+	if err = copyRequestDetails(tx, requestID, newRequestID); err != nil {
+		return Request{}, err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return Request{}, fmt.Errorf("failed to commit duplicated request: %w", err)
 	}
@@ -751,6 +998,7 @@ func (s *RequestCRUDService) DuplicateRequest(requestID int) (Request, error) {
 		BodyFormat:     nullStringToPointer(bodyFormat),
 		Auth:           nullStringToPointer(auth),
 		SortOrder:      intPointer(nextSort),
+		Tags:           s.GetRequestTags(newRequestID),
 		Response:       latestResponse,
 	}
 
@@ -758,29 +1006,39 @@ func (s *RequestCRUDService) DuplicateRequest(requestID int) (Request, error) {
 }
 
 func (s *RequestCRUDService) UpdateRequest(id int, collectionId *string, name string, description string, method string, requestUrl string, headers string, body string, bodyType string, bodyFormat string, auth string, response *Response) Request {
-	_, err := s.db.Exec(
-		`UPDATE requests
+	err := s.journaled("Edit request", []journalScope{{table: "requests", condition: "id = ?", args: []interface{}{id}}}, func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`UPDATE requests
          SET collection_id = ?, name = ?, description = ?, method = ?, url = ?, headers = ?, body = ?, body_type = ?, body_format = ?, auth = ?
          WHERE id = ?`,
-		collectionId,
-		emptyStringToNullString(name),
-		emptyStringToNullString(description),
-		emptyStringToNullString(method),
-		emptyStringToNullString(requestUrl),
-		emptyStringToNullString(headers),
-		emptyStringToNullString(body),
-		emptyStringToNullString(bodyType),
-		emptyStringToNullString(bodyFormat),
-		emptyStringToNullString(auth),
-		id,
-	)
+			collectionRef(collectionId),
+			emptyStringToNullString(name),
+			emptyStringToNullString(description),
+			emptyStringToNullString(method),
+			emptyStringToNullString(requestUrl),
+			emptyStringToNullString(headers),
+			emptyStringToNullString(body),
+			emptyStringToNullString(bodyType),
+			emptyStringToNullString(bodyFormat),
+			emptyStringToNullString(auth),
+			id,
+		)
+		return err
+	})
 	if err != nil {
 		fmt.Println("Failed to update request:", err)
 		return Request{}
 	}
+	s.syncRequestParams(id, requestUrl)
+	s.syncPathVariables(id, requestUrl)
 
 	if response != nil {
-		s.logResponseHistory(id, response.StatusCode, response.Headers, response.Body, response.RuntimeMS, response.CreatedAt)
+		s.logResponseHistory(id, response.StatusCode, response.Headers, response.Body, response.RuntimeMS, response.CreatedAt, executionOrigin{})
+	}
+
+	var favorite bool
+	if err := s.db.QueryRow("SELECT favorite FROM requests WHERE id = ?", id).Scan(&favorite); err != nil {
+		fmt.Println("Failed to load request favorite:", err)
 	}
 
 	return Request{
@@ -795,24 +1053,31 @@ func (s *RequestCRUDService) UpdateRequest(id int, collectionId *string, name st
 		BodyType:     stringPointerOrNil(bodyType),
 		BodyFormat:   stringPointerOrNil(bodyFormat),
 		Auth:         stringPointerOrNil(auth),
+		Favorite:     favorite,
+		Tags:         s.GetRequestTags(id),
 	}
 }
 
 // TODO: Implement this
 func (s *RequestCRUDService) SetRequestSortOrder(id int, sortOrder int) error {
+	return s.journaled("Reorder requests", []journalScope{siblingRequestsScope(id)}, func(tx *sql.Tx) error {
+		return setRequestSortOrder(tx, id, sortOrder)
+	})
+}
+
+func setRequestSortOrder(tx *sql.Tx, id int, sortOrder int) error {
 	var collectionId sql.NullString
-	err := s.db.QueryRow("SELECT collection_id FROM requests WHERE id = ?", id).Scan(&collectionId)
+	err := tx.QueryRow("SELECT collection_id FROM requests WHERE id = ?", id).Scan(&collectionId)
 	if err != nil {
 		fmt.Println("Failed to get request collection:", err)
 		return err
 	}
 
-	rows, err := s.db.Query("SELECT id FROM requests WHERE collection_id = ? AND sort_order IS NULL ORDER BY id", collectionId)
+	rows, err := tx.Query("SELECT id FROM requests WHERE collection_id = ? AND sort_order IS NULL AND deleted_at IS NULL ORDER BY id", collectionId)
 	if err != nil {
 		fmt.Println("Failed to get requests with null sort orders:", err)
 		return err
 	}
-	defer rows.Close()
 
 	var requestsToUpdate []int
 	for rows.Next() {
@@ -822,9 +1087,10 @@ func (s *RequestCRUDService) SetRequestSortOrder(id int, sortOrder int) error {
 		}
 		requestsToUpdate = append(requestsToUpdate, requestId)
 	}
+	rows.Close()
 
 	var maxSortOrder sql.NullInt64
-	err = s.db.QueryRow("SELECT MAX(sort_order) FROM requests WHERE collection_id = ? AND sort_order IS NOT NULL", collectionId).Scan(&maxSortOrder)
+	err = tx.QueryRow("SELECT MAX(sort_order) FROM requests WHERE collection_id = ? AND sort_order IS NOT NULL AND deleted_at IS NULL", collectionId).Scan(&maxSortOrder)
 	if err != nil {
 		fmt.Println("Failed to get max sort order:", err)
 		return err
@@ -836,7 +1102,7 @@ func (s *RequestCRUDService) SetRequestSortOrder(id int, sortOrder int) error {
 	}
 
 	for i, requestId := range requestsToUpdate {
-		_, err = s.db.Exec("UPDATE requests SET sort_order = ? WHERE id = ?", startOrder+i, requestId)
+		_, err = tx.Exec("UPDATE requests SET sort_order = ? WHERE id = ?", startOrder+i, requestId)
 		if err != nil {
 			fmt.Println("Failed to initialize sort order for request:", requestId, err)
 			return err
@@ -844,7 +1110,7 @@ func (s *RequestCRUDService) SetRequestSortOrder(id int, sortOrder int) error {
 	}
 
 	var currentSortOrder sql.NullInt64
-	err = s.db.QueryRow("SELECT sort_order FROM requests WHERE id = ?", id).Scan(&currentSortOrder)
+	err = tx.QueryRow("SELECT sort_order FROM requests WHERE id = ?", id).Scan(&currentSortOrder)
 	if err != nil {
 		fmt.Println("Failed to get current sort order:", err)
 		return err
@@ -863,30 +1129,30 @@ func (s *RequestCRUDService) SetRequestSortOrder(id int, sortOrder int) error {
 
 	if currentOrder < sortOrder {
 		if collectionId.Valid {
-			_, err = s.db.Exec(`
+			_, err = tx.Exec(`
 			UPDATE requests 
 			SET sort_order = sort_order - 1 
-			WHERE collection_id = ? AND sort_order > ? AND sort_order <= ? AND id != ?
+			WHERE collection_id = ? AND sort_order > ? AND sort_order <= ? AND id != ? AND deleted_at IS NULL
 		`, collectionId.String, currentOrder, sortOrder, id)
 		} else {
-			_, err = s.db.Exec(`
+			_, err = tx.Exec(`
 			UPDATE requests 
 			SET sort_order = sort_order - 1 
-			WHERE collection_id IS NULL AND sort_order > ? AND sort_order <= ? AND id != ?
+			WHERE collection_id IS NULL AND sort_order > ? AND sort_order <= ? AND id != ? AND deleted_at IS NULL
 		`, currentOrder, sortOrder, id)
 		}
 	} else {
 		if collectionId.Valid {
-			_, err = s.db.Exec(`
+			_, err = tx.Exec(`
 			UPDATE requests 
 			SET sort_order = sort_order + 1 
-			WHERE collection_id = ? AND sort_order >= ? AND sort_order < ? AND id != ?
+			WHERE collection_id = ? AND sort_order >= ? AND sort_order < ? AND id != ? AND deleted_at IS NULL
 		`, collectionId.String, sortOrder, currentOrder, id)
 		} else {
-			_, err = s.db.Exec(`
+			_, err = tx.Exec(`
 			UPDATE requests 
 			SET sort_order = sort_order + 1 
-			WHERE collection_id IS NULL AND sort_order >= ? AND sort_order < ? AND id != ?
+			WHERE collection_id IS NULL AND sort_order >= ? AND sort_order < ? AND id != ? AND deleted_at IS NULL
 		`, sortOrder, currentOrder, id)
 		}
 	}
@@ -896,7 +1162,7 @@ func (s *RequestCRUDService) SetRequestSortOrder(id int, sortOrder int) error {
 		return err
 	}
 
-	_, err = s.db.Exec("UPDATE requests SET sort_order = ? WHERE id = ?", sortOrder, id)
+	_, err = tx.Exec("UPDATE requests SET sort_order = ? WHERE id = ?", sortOrder, id)
 	if err != nil {
 		fmt.Println("Failed to update request sort order:", err)
 		return err
@@ -905,22 +1171,30 @@ func (s *RequestCRUDService) SetRequestSortOrder(id int, sortOrder int) error {
 	return nil
 }
 
+// SearchRequests ranks requests through the full-text index, treating every word as a prefix
+// so results update while typing. Filters such as method:POST, host:, status:5xx, in:"Billing"
+// and has:auth narrow the results; see parseSearchQuery. FullTextSearch accepts the explicit
+// query syntax.
 func (s *RequestCRUDService) SearchRequests(searchTerm string) []Request {
-	var requests []Request
-	query := `
-        SELECT id, collection_id, name, description, body, url, method
-        FROM requests
-        WHERE name = ? OR url = ? 
-        OR name LIKE ? OR url LIKE ? OR body LIKE ?
-        ORDER BY id ASC
-    `
-	rows, err := s.db.Query(query, searchTerm, searchTerm, "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%")
+	q, err := parseSearchQuery(searchTerm)
+	if err != nil {
+		fmt.Println("Error parsing search query:", err)
+		return []Request{}
+	}
+	match := q.match(true)
+	if match == "" && len(q.conditions) == 0 {
+		return []Request{}
+	}
+
+	query, args := q.requestSQL("r.id, r.collection_id, r.name, r.description, r.body, r.url, r.method", match, defaultSearchLimit)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		fmt.Println("Error searching requests:", err)
 		return []Request{}
 	}
 	defer rows.Close()
 
+	requests := []Request{}
 	for rows.Next() {
 		var (
 			requestID    int
@@ -967,19 +1241,23 @@ func (s *RequestCRUDService) CreateCollection(name string, description string, p
 		ParentCollectionId: parentId,
 	}
 
+	var sortOrder int
 	err := s.db.QueryRow(
-		"INSERT INTO collections (id, name, description, parent_collection) VALUES(?, ?, ?, ?) RETURNING ID",
+		"INSERT INTO collections (id, name, description, parent_collection, sort_order) VALUES(?, ?, ?, ?, "+nextCollectionSortOrder+") RETURNING ID, sort_order",
 		newCollection.ID,
 		newCollection.Name,
 		emptyStringToNullString(newCollection.Description),
-		newCollection.ParentCollectionId,
-	).Scan(&newCollection.ID)
+		collectionRef(newCollection.ParentCollectionId),
+		collectionRef(newCollection.ParentCollectionId),
+		newCollection.ID,
+	).Scan(&newCollection.ID, &sortOrder)
 
 	if err != nil {
 		fmt.Println("Failed to insert collection", err)
 		return Collection{}
 	}
 
+	newCollection.SortOrder = intPointer(sortOrder)
 	return newCollection
 }
 
@@ -988,9 +1266,15 @@ func (s *RequestCRUDService) UpdateCollectionParent(collectionId string, parentI
 		return fmt.Errorf("a collection cannot be its own parent")
 	}
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start collection move: %w", err)
+	}
+	defer tx.Rollback()
+
 	if parentId != nil {
 		var exists bool
-		if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM collections WHERE id = ?)", *parentId).Scan(&exists); err != nil {
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM collections WHERE id = ? AND deleted_at IS NULL)", *parentId).Scan(&exists); err != nil {
 			fmt.Println("Failed to validate parent collection:", err)
 			return err
 		}
@@ -998,8 +1282,9 @@ func (s *RequestCRUDService) UpdateCollectionParent(collectionId string, parentI
 			return fmt.Errorf("parent collection %s does not exist", *parentId)
 		}
 
-		cycle, err := s.createsCollectionCycle(collectionId, *parentId)
-		if err != nil {
+		// Moving a collection below one of its own descendants would detach the subtree.
+		var cycle bool
+		if err := tx.QueryRow("SELECT EXISTS("+collectionSubtreeQuery+" WHERE id = ?)", collectionId, *parentId).Scan(&cycle); err != nil {
 			fmt.Println("Failed to validate collection hierarchy:", err)
 			return err
 		}
@@ -1008,53 +1293,127 @@ func (s *RequestCRUDService) UpdateCollectionParent(collectionId string, parentI
 		}
 	}
 
-	_, err := s.db.Exec(
-		"UPDATE collections SET parent_collection = ? WHERE id = ?",
-		parentId,
+	changes, err := snapshotJournalRows(tx, journalScope{table: "collections", condition: "id = ?", args: []interface{}{collectionId}})
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"UPDATE collections SET parent_collection = ?, sort_order = "+nextCollectionSortOrder+" WHERE id = ?",
+		collectionRef(parentId),
+		collectionRef(parentId),
+		collectionId,
 		collectionId,
 	)
 	if err != nil {
 		fmt.Println("Failed to update collection parent:", err)
 		return err
 	}
-	return nil
+	if err := recordJournalEntry(tx, "Move collection", changes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetRequestCollection moves a request to the end of another collection.
+func (s *RequestCRUDService) SetRequestCollection(requestId int, collectionId string) error {
+	return s.journaled("Move request", []journalScope{siblingRequestsScope(requestId)}, func(tx *sql.Tx) error {
+		if err := closeSortOrderGap(tx, requestId); err != nil {
+			return err
+		}
+		target := collectionRef(&collectionId)
+		_, err := tx.Exec(
+			`UPDATE requests
+			 SET collection_id = ?,
+			     sort_order = (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM requests WHERE collection_id IS ? AND deleted_at IS NULL AND id != ?)
+			 WHERE id = ?`,
+			target,
+			target,
+			requestId,
+			requestId,
+		)
+		if err != nil {
+			fmt.Println("Failed to set request collection", err)
+			return fmt.Errorf("failed to move request %d: %w", requestId, err)
+		}
+		return nil
+	})
 }
 
-func (s *RequestCRUDService) SetRequestCollection(requestId int, collectionId string) {
-	var value interface{}
-	if strings.TrimSpace(collectionId) != "" {
-		value = collectionId
+// DeleteCollection moves a collection to the trash in one transaction. In
+// CollectionDeleteCascade mode its sub-collections and requests go with it; in
+// CollectionDeleteReparent mode they move up to the collection's parent first.
+func (s *RequestCRUDService) DeleteCollection(collectionId string, mode string) (CollectionDeleteSummary, error) {
+	summary := CollectionDeleteSummary{Mode: mode}
+	if mode != CollectionDeleteCascade && mode != CollectionDeleteReparent {
+		return summary, fmt.Errorf("unknown delete mode %q", mode)
 	}
 
-	if _, err := s.db.Exec("UPDATE requests SET collection_id = ? WHERE id = ?", value, requestId); err != nil {
-		fmt.Println("Failed to set request collection", err)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return summary, fmt.Errorf("failed to start collection delete: %w", err)
+	}
+	defer tx.Rollback()
+
+	var parent sql.NullString
+	err = tx.QueryRow("SELECT parent_collection FROM collections WHERE id = ? AND deleted_at IS NULL", collectionId).Scan(&parent)
+	if err == sql.ErrNoRows {
+		return summary, fmt.Errorf("collection %s not found", collectionId)
+	}
+	if err != nil {
+		return summary, fmt.Errorf("failed to load collection %s: %w", collectionId, err)
+	}
+
+	subtree := "id IN (" + collectionSubtreeQuery + ")"
+	changes, err := snapshotJournalRows(tx,
+		journalScope{table: "collections", condition: subtree, args: []interface{}{collectionId}},
+		journalScope{table: "requests", condition: "collection_id IN (" + collectionSubtreeQuery + ")", args: []interface{}{collectionId}},
+	)
+	if err != nil {
+		return summary, err
 	}
-}
 
-func (s *RequestCRUDService) DeleteCollection(collectionId string) error {
-	_, err := s.db.Query("DELETE FROM collections where id = ?", collectionId)
+	if mode == CollectionDeleteReparent {
+		summary.CollectionsMoved, summary.RequestsMoved, err = moveCollectionContents(tx, collectionId, parent)
+		if err != nil {
+			return summary, err
+		}
+	}
+
+	summary.CollectionsDeleted, summary.RequestsDeleted, summary.ResponsesTrashed, err = trashCollection(tx, collectionId)
 	if err != nil {
 		fmt.Println("Filed to delete collection", err)
-		return err
+		return summary, err
 	}
-	return nil
+	if err := recordJournalEntry(tx, "Delete collection", changes); err != nil {
+		return summary, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return summary, fmt.Errorf("failed to commit collection delete: %w", err)
+	}
+	return summary, nil
 }
 
 func (s *RequestCRUDService) GetAllCollections() []Collection {
 	s.sanitizeCollectionParents()
+	s.normalizeCollectionSortOrder()
+
+	collectionTags := s.tagsByItem("collection_id")
 
 	var collections []Collection
-	rows, err := s.db.Query("SELECT id, name, parent_collection FROM collections")
+	rows, err := s.db.Query("SELECT id, name, parent_collection, sort_order, favorite FROM collections WHERE deleted_at IS NULL ORDER BY sort_order, name, id")
 
 	if err != nil {
 		fmt.Println("Failed to get all collections", err)
 		return []Collection{}
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var c Collection
 		var parentId sql.NullString
-		err := rows.Scan(&c.ID, &c.Name, &parentId)
+		var sortOrder sql.NullInt64
+		err := rows.Scan(&c.ID, &c.Name, &parentId, &sortOrder, &c.Favorite)
 		if err != nil {
 			fmt.Println("Failed to scan row to collection", err)
 			continue
@@ -1063,6 +1422,10 @@ func (s *RequestCRUDService) GetAllCollections() []Collection {
 			parentId.Valid = false
 		}
 		c.ParentCollectionId = nullStringToPointer(parentId)
+		if sortOrder.Valid {
+			c.SortOrder = intPointer(int(sortOrder.Int64))
+		}
+		c.Tags = collectionTags[c.ID]
 		collections = append(collections, c)
 	}
 
@@ -1078,104 +1441,66 @@ func (s *RequestCRUDService) sanitizeCollectionParents() {
 	}
 }
 
+// normalizeRequestSortOrder renumbers the requests of each collection from zero, closing the
+// gaps left by moved or deleted requests. All rows are read before any are updated so the
+// read does not hold the database while writing.
 func (s *RequestCRUDService) normalizeRequestSortOrder() {
 	if s.db == nil {
 		return
 	}
 
-	rows, err := s.db.Query("SELECT DISTINCT collection_id FROM requests")
+	rows, err := s.db.Query(`
+		SELECT id, collection_id, sort_order
+		FROM requests
+		WHERE deleted_at IS NULL
+		ORDER BY collection_id, CASE WHEN sort_order IS NULL THEN 1 ELSE 0 END, sort_order, id
+	`)
 	if err != nil {
-		fmt.Println("Failed to gather collections for sort normalization:", err)
+		fmt.Println("Failed to load requests for normalization:", err)
 		return
 	}
-	defer rows.Close()
 
+	type sortUpdate struct {
+		requestID int
+		sortOrder int
+	}
+	var (
+		updates     []sortUpdate
+		previous    sql.NullString
+		index       int
+		initialized bool
+	)
 	for rows.Next() {
+		var requestID int
 		var collectionID sql.NullString
-		if err := rows.Scan(&collectionID); err != nil {
-			fmt.Println("Failed to scan collection id during sort normalization:", err)
+		var sortOrder sql.NullInt64
+		if err := rows.Scan(&requestID, &collectionID, &sortOrder); err != nil {
+			fmt.Println("Failed to scan request during normalization:", err)
 			continue
 		}
-
-		var (
-			reqRows *sql.Rows
-			query   string
-			args    []interface{}
-		)
-
-		if collectionID.Valid {
-			query = `
-			SELECT id, sort_order
-			FROM requests
-			WHERE collection_id = ?
-			ORDER BY CASE WHEN sort_order IS NULL THEN 1 ELSE 0 END, sort_order, id
-		`
-			args = []interface{}{collectionID.String}
-		} else {
-			query = `
-			SELECT id, sort_order
-			FROM requests
-			WHERE collection_id IS NULL
-			ORDER BY CASE WHEN sort_order IS NULL THEN 1 ELSE 0 END, sort_order, id
-		`
+		if !initialized || collectionID != previous {
+			previous, index, initialized = collectionID, 0, true
 		}
-
-		reqRows, err = s.db.Query(query, args...)
-		if err != nil {
-			fmt.Println("Failed to load requests for normalization:", err)
-			continue
+		if !sortOrder.Valid || int(sortOrder.Int64) != index {
+			updates = append(updates, sortUpdate{requestID: requestID, sortOrder: index})
 		}
+		index++
+	}
+	rows.Close()
 
-		index := 0
-		for reqRows.Next() {
-			var requestID int
-			var sortOrder sql.NullInt64
-			if err := reqRows.Scan(&requestID, &sortOrder); err != nil {
-				fmt.Println("Failed to scan request during normalization:", err)
-				continue
-			}
-
-			if !sortOrder.Valid || int(sortOrder.Int64) != index {
-				if _, err := s.db.Exec("UPDATE requests SET sort_order = ? WHERE id = ?", index, requestID); err != nil {
-					fmt.Println("Failed to normalize request sort order:", err)
-					continue
-				}
-			}
-			index++
+	for _, update := range updates {
+		if _, err := s.db.Exec("UPDATE requests SET sort_order = ? WHERE id = ?", update.sortOrder, update.requestID); err != nil {
+			fmt.Println("Failed to normalize request sort order:", err)
 		}
-		reqRows.Close()
 	}
 }
 
-func (s *RequestCRUDService) createsCollectionCycle(collectionId string, newParentId string) (bool, error) {
-	current := newParentId
-	for current != "" {
-		if current == collectionId {
-			return true, nil
-		}
-
-		var parent sql.NullString
-		err := s.db.QueryRow("SELECT parent_collection FROM collections WHERE id = ?", current).Scan(&parent)
-		if err == sql.ErrNoRows {
-			return false, nil
-		}
-		if err != nil {
-			return false, err
-		}
-		if !parent.Valid {
-			return false, nil
-		}
-
-		if parent.String == current {
-			if _, err := s.db.Exec("UPDATE collections SET parent_collection = NULL WHERE id = ?", current); err != nil {
-				fmt.Println("Failed to repair self-referential parent during cycle check:", err)
-			}
-			return false, nil
-		}
-
-		current = parent.String
+// collectionRef is the value stored for an optional collection id; blank ids mean no collection.
+func collectionRef(id *string) interface{} {
+	if id == nil || strings.TrimSpace(*id) == "" {
+		return nil
 	}
-	return false, nil
+	return *id
 }
 
 func emptyStringToNullString(s string) sql.NullString {
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type QueryParam struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description"`
+}
+
+func (s *RequestCRUDService) GetRequestParams(requestID int) []QueryParam {
+	if s.db == nil {
+		return []QueryParam{}
+	}
+	params, err := queryRequestParams(s.db, requestID)
+	if err != nil {
+		fmt.Println("Failed to load request params:", err)
+	}
+	return params
+}
+
+func queryRequestParams(db sqlQueryExecer, requestID int) ([]QueryParam, error) {
+	params := []QueryParam{}
+	rows, err := db.Query("SELECT key, value, enabled, description FROM request_params WHERE request_id = ? ORDER BY COALESCE(sort_order, 0), id", requestID)
+	if err != nil {
+		return params, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			p           QueryParam
+			value       sql.NullString
+			description sql.NullString
+		)
+		if err := rows.Scan(&p.Key, &value, &p.Enabled, &description); err != nil {
+			fmt.Println("Failed to scan request param:", err)
+			continue
+		}
+		p.Value = value.String
+		p.Description = description.String
+		params = append(params, p)
+	}
+	return params, rows.Err()
+}
+
+// SetRequestParams replaces a request's params and rewrites the query string of its stored URL
+// from the enabled ones. The updated URL is returned so the editor can show it.
+func (s *RequestCRUDService) SetRequestParams(requestID int, params []QueryParam) (string, error) {
+	if s.db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+
+	var rawURL sql.NullString
+	if err := s.db.QueryRow("SELECT url FROM requests WHERE id = ?", requestID).Scan(&rawURL); err != nil {
+		return "", fmt.Errorf("failed to load request %d: %w", requestID, err)
+	}
+	base, _, fragment := splitURLQuery(rawURL.String)
+	updatedURL := buildURLWithParams(base, params, fragment)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to start params transaction: %w", err)
+	}
+	if err := writeRequestParams(tx, requestID, params); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	if _, err := tx.Exec("UPDATE requests SET url = ? WHERE id = ?", emptyStringToNullString(updatedURL), requestID); err != nil {
+		tx.Rollback()
+		return "", fmt.Errorf("failed to update request url: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit request params: %w", err)
+	}
+	return updatedURL, nil
+}
+
+// syncRequestParams rebuilds the stored params after the URL was edited directly.
+func (s *RequestCRUDService) syncRequestParams(requestID int, rawURL string) {
+	if err := rebuildRequestParams(s.db, requestID, rawURL); err != nil {
+		fmt.Println("Failed to sync request params:", err)
+	}
+}
+
+// rebuildRequestParams makes the URL's query the enabled params of a request. Descriptions are
+// carried over by key and disabled params, which never appear in the URL, are kept.
+func rebuildRequestParams(tx sqlQueryExecer, requestID int, rawURL string) error {
+	existing, err := queryRequestParams(tx, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to load request params: %w", err)
+	}
+	_, query, _ := splitURLQuery(rawURL)
+
+	descriptions := make(map[string]string)
+	var disabled []QueryParam
+	for _, p := range existing {
+		if !p.Enabled {
+			disabled = append(disabled, p)
+			continue
+		}
+		if _, ok := descriptions[p.Key]; !ok {
+			descriptions[p.Key] = p.Description
+		}
+	}
+
+	params := parseQueryParams(query)
+	for i := range params {
+		params[i].Description = descriptions[params[i].Key]
+	}
+	params = append(params, disabled...)
+	return writeRequestParams(tx, requestID, params)
+}
+
+func writeRequestParams(tx sqlExecer, requestID int, params []QueryParam) error {
+	if _, err := tx.Exec("DELETE FROM request_params WHERE request_id = ?", requestID); err != nil {
+		return fmt.Errorf("failed to clear request params: %w", err)
+	}
+	for i, p := range params {
+		if p.Key == "" && p.Value == "" {
+			continue
+		}
+		_, err := tx.Exec(
+			"INSERT INTO request_params (request_id, key, value, enabled, description, sort_order) VALUES (?, ?, ?, ?, ?, ?)",
+			requestID,
+			p.Key,
+			p.Value,
+			p.Enabled,
+			emptyStringToNullString(p.Description),
+			i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save request param %s: %w", p.Key, err)
+		}
+	}
+	return nil
+}
+
+// splitURLQuery splits a URL into the part before the query, the raw query and the fragment.
+// Question marks and hashes inside {{...}} placeholders are not treated as separators.
+func splitURLQuery(rawURL string) (string, string, string) {
+	queryStart, fragmentStart := -1, -1
+	depth := 0
+	for i := 0; i < len(rawURL) && fragmentStart == -1; i++ {
+		switch {
+		case strings.HasPrefix(rawURL[i:], "{{"):
+			depth++
+			i++
+		case strings.HasPrefix(rawURL[i:], "}}") && depth > 0:
+			depth--
+			i++
+		case depth > 0:
+		case rawURL[i] == '?' && queryStart == -1:
+			queryStart = i
+		case rawURL[i] == '#':
+			fragmentStart = i
+		}
+	}
+
+	fragment := ""
+	if fragmentStart != -1 {
+		fragment = rawURL[fragmentStart+1:]
+		rawURL = rawURL[:fragmentStart]
+	}
+	if queryStart == -1 {
+		return rawURL, "", fragment
+	}
+	return rawURL[:queryStart], rawURL[queryStart+1:], fragment
+}
+
+func parseQueryParams(rawQuery string) []QueryParam {
+	params := []QueryParam{}
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		params = append(params, QueryParam{
+			Key:     unescapeQueryComponent(key),
+			Value:   unescapeQueryComponent(value),
+			Enabled: true,
+		})
+	}
+	return params
+}
+
+// buildURLWithParams appends the enabled params to base, leaving placeholders unencoded so
+// they can still be resolved at execution time.
+func buildURLWithParams(base string, params []QueryParam, fragment string) string {
+	var pairs []string
+	for _, p := range params {
+		if !p.Enabled || (p.Key == "" && p.Value == "") {
+			continue
+		}
+		pairs = append(pairs, encodeQueryPair(p.Key, p.Value, encodeQueryComponent))
+	}
+
+	result := base
+	if len(pairs) > 0 {
+		result += "?" + strings.Join(pairs, "&")
+	}
+	if fragment != "" {
+		result += "#" + fragment
+	}
+	return result
+}
+
+func encodeQueryPair(key string, value string, encode func(string) string) string {
+	if value == "" {
+		return encode(key)
+	}
+	return encode(key) + "=" + encode(value)
+}
+
+func encodeQueryComponent(value string) string {
+	var b strings.Builder
+	for value != "" {
+		loc := placeholderPattern.FindStringIndex(value)
+		if loc == nil {
+			b.WriteString(url.QueryEscape(value))
+			break
+		}
+		b.WriteString(url.QueryEscape(value[:loc[0]]))
+		b.WriteString(value[loc[0]:loc[1]])
+		value = value[loc[1]:]
+	}
+	return b.String()
+}
+
+// expandQueryPlaceholders resolves the placeholders in a raw query string, escaping each
+// substituted value. Literal text keeps its own encoding, with only characters that are not
+// allowed in a query escaped, and unknown placeholders are left as written.
+func expandQueryPlaceholders(rawQuery string, lookup variableLookup) (string, error) {
+	var b strings.Builder
+	for rawQuery != "" {
+		loc := placeholderPattern.FindStringIndex(rawQuery)
+		if loc == nil {
+			b.WriteString(escapeQueryLiteral(rawQuery))
+			break
+		}
+		b.WriteString(escapeQueryLiteral(rawQuery[:loc[0]]))
+		match := rawQuery[loc[0]:loc[1]]
+		value, ok, err := lookup(strings.TrimSpace(match[2 : len(match)-2]))
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			b.WriteString(url.QueryEscape(value))
+		} else {
+			b.WriteString(match)
+		}
+		rawQuery = rawQuery[loc[1]:]
+	}
+	return b.String(), nil
+}
+
+// escapeQueryLiteral percent-encodes the bytes that may not appear in a query, such as spaces,
+// and leaves separators, sub-delimiters and existing escapes alone.
+func escapeQueryLiteral(value string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '%' && i+2 < len(value) && isHexDigit(value[i+1]) && isHexDigit(value[i+2]):
+			b.WriteByte(c)
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', strings.IndexByte("-._~!$&'()*+,;=:@/?", c) >= 0:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		}
+	}
+	return b.String()
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func unescapeQueryComponent(value string) string {
+	unescaped, err := url.QueryUnescape(value)
+	if err != nil {
+		return value
+	}
+	return unescaped
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// RequestSnapshot is the fully resolved request as it was sent: placeholders, path variables,
+// params, scripts and auth are all applied. Cookies added from the jar are not included.
+type RequestSnapshot struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	Headers     http.Header `json:"headers"`
+	Body        string      `json:"body"`
+	Environment string      `json:"environment"`
+	// MaskedSecrets names the secret variables, and the expressions derived from them, whose
+	// values are masked in this copy.
+	MaskedSecrets []string `json:"maskedSecrets,omitempty"`
+}
+
+// storedSnapshot is the form kept in responses.request_snapshot. When secrets were used the
+// embedded snapshot has them masked and Sealed holds the exact snapshot encrypted with the
+// secrets key, so history can be shown without decrypting and re-run exactly when unlocked.
+type storedSnapshot struct {
+	RequestSnapshot
+	Sealed string `json:"sealed,omitempty"`
+}
+
+// revealedSecrets maps variable names to the secret values decrypted for one execution, and
+// template expressions to the values they derived from a secret.
+type revealedSecrets map[string]string
+
+// secretTrackingLookup evaluates placeholders with lookup, which records the secrets it reveals
+// in secrets. The result of every expression that read a secret is recorded too, so derived
+// values such as {{base64 (concat user ":" password)}} or an hmacSHA256 signature are masked
+// like the secret itself.
+func secretTrackingLookup(lookup variableLookup, secrets revealedSecrets) variableLookup {
+	readSecret := false
+	evaluator := newTemplateEvaluator(func(name string) (string, bool, error) {
+		value, ok, err := lookup(name)
+		if _, isSecret := secrets[name]; ok && isSecret {
+			readSecret = true
+		}
+		return value, ok, err
+	})
+	return func(expr string) (string, bool, error) {
+		readSecret = false
+		value, ok, err := evaluator.evaluate(expr)
+		expr = strings.TrimSpace(expr)
+		if _, isSecret := secrets[expr]; ok && readSecret && !isSecret {
+			secrets[expr] = value
+		}
+		return value, ok, err
+	}
+}
+
+// GetResponseSnapshot returns the request that produced a response, with secrets masked.
+func (s *RequestCRUDService) GetResponseSnapshot(responseID int) (RequestSnapshot, error) {
+	stored, _, err := s.loadStoredSnapshot(responseID)
+	if err != nil {
+		return RequestSnapshot{}, err
+	}
+	return stored.RequestSnapshot, nil
+}
+
+// RerunResponse sends the exact request recorded with a response again and records the result
+// as a new history entry. Scripts are not run, since the snapshot already contains their effects.
+func (s *RequestCRUDService) RerunResponse(responseID int) (json.RawMessage, error) {
+	stored, raw, err := s.loadStoredSnapshot(responseID)
+	if err != nil {
+		return encodeError(err), err
+	}
+
+	snapshot := stored.RequestSnapshot
+	if stored.Sealed != "" {
+		if snapshot, err = s.unsealSnapshot(stored.Sealed); err != nil {
+			return encodeError(err), err
+		}
+	}
+
+	// A response whose request is gone still reruns, as request 0, which is not recorded.
+	var storedRequestID sql.NullInt64
+	if err := s.db.QueryRow("SELECT request_id FROM responses WHERE id = ?", responseID).Scan(&storedRequestID); err != nil {
+		return encodeError(err), err
+	}
+	requestID := int(storedRequestID.Int64)
+
+	resp, bodyBytes, requestTime, err := s.sendSnapshot(snapshot, snapshot.Environment)
+	if err != nil {
+		s.logExecutionFailure(requestID, &executionAttempt{snapshot: stored.RequestSnapshot, stored: raw}, err)
+		return encodeError(err), err
+	}
+	return s.recordResponse(requestID, resp, bodyBytes, requestTime, stored.RequestSnapshot, raw, nil)
+}
+
+func (s *RequestCRUDService) loadStoredSnapshot(responseID int) (storedSnapshot, string, error) {
+	var raw sql.NullString
+	err := s.db.QueryRow("SELECT request_snapshot FROM responses WHERE id = ?", responseID).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return storedSnapshot{}, "", fmt.Errorf("response %d not found", responseID)
+	}
+	if err != nil {
+		return storedSnapshot{}, "", fmt.Errorf("failed to load response %d: %w", responseID, err)
+	}
+	if !raw.Valid || raw.String == "" {
+		return storedSnapshot{}, "", fmt.Errorf("response %d was recorded without a request snapshot", responseID)
+	}
+
+	var stored storedSnapshot
+	if err := json.Unmarshal([]byte(raw.String), &stored); err != nil {
+		return storedSnapshot{}, "", fmt.Errorf("failed to parse request snapshot: %w", err)
+	}
+	return stored, raw.String, nil
+}
+
+// sealSnapshot builds the stored form of a snapshot. Without secrets it is stored as is.
+func (s *RequestCRUDService) sealSnapshot(snapshot RequestSnapshot, secrets revealedSecrets) (storedSnapshot, error) {
+	if len(secrets) == 0 {
+		return storedSnapshot{RequestSnapshot: snapshot}, nil
+	}
+	if s.envars == nil {
+		return storedSnapshot{}, fmt.Errorf("secrets are not available")
+	}
+
+	exact, err := json.Marshal(snapshot)
+	if err != nil {
+		return storedSnapshot{}, err
+	}
+	key, err := s.envars.currentSecretKey()
+	if err != nil {
+		return storedSnapshot{}, err
+	}
+	sealed, err := encryptSecret(key, string(exact))
+	if err != nil {
+		return storedSnapshot{}, err
+	}
+	return storedSnapshot{RequestSnapshot: maskSnapshot(snapshot, secrets), Sealed: sealed}, nil
+}
+
+func (s *RequestCRUDService) unsealSnapshot(sealed string) (RequestSnapshot, error) {
+	if s.envars == nil {
+		return RequestSnapshot{}, fmt.Errorf("secrets are not available")
+	}
+	key, err := s.envars.currentSecretKey()
+	if err != nil {
+		return RequestSnapshot{}, err
+	}
+	exact, err := decryptSecret(key, sealed)
+	if err != nil {
+		return RequestSnapshot{}, err
+	}
+	var snapshot RequestSnapshot
+	if err := json.Unmarshal([]byte(exact), &snapshot); err != nil {
+		return RequestSnapshot{}, fmt.Errorf("failed to parse request snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// maskSnapshot replaces secret values, including their URL-encoded forms, with secretMask.
+// Longer values are replaced first so a secret containing another is masked whole.
+func maskSnapshot(snapshot RequestSnapshot, secrets revealedSecrets) RequestSnapshot {
+	var names []string
+	var replacements []string
+	values := make([]string, 0, len(secrets))
+	for name, value := range secrets {
+		names = append(names, name)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	sort.Strings(names)
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, value := range values {
+		for _, form := range []string{value, url.QueryEscape(value), url.PathEscape(value)} {
+			replacements = append(replacements, form, secretMask)
+		}
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	masked := snapshot
+	masked.URL = replacer.Replace(snapshot.URL)
+	masked.Body = replacer.Replace(snapshot.Body)
+	masked.Headers = make(http.Header, len(snapshot.Headers))
+	for key, headerValues := range snapshot.Headers {
+		for _, value := range headerValues {
+			masked.Headers[key] = append(masked.Headers[key], replacer.Replace(value))
+		}
+	}
+	masked.MaskedSecrets = names
+	return masked
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMaskSnapshotDerivedSecrets(t *testing.T) {
+	vars := map[string]string{"user": "alice", "secretPass": "s3cr3t pass", "apiKey": "k-123"}
+	secretNames := map[string]bool{"secretPass": true, "apiKey": true}
+
+	tests := []struct {
+		name   string
+		url    string
+		header string
+		body   string
+		want   RequestSnapshot
+	}{
+		{
+			name:   "imported basic auth",
+			url:    "https://api.internal/me",
+			header: basicAuthValue("{{user}}", "{{secretPass}}"),
+			want: RequestSnapshot{
+				URL:           "https://api.internal/me",
+				Headers:       http.Header{"Authorization": {"Basic " + secretMask}},
+				MaskedSecrets: []string{`base64 (concat (user) ":" (secretPass))`, "secretPass"},
+			},
+		},
+		{
+			name:   "signature and query secret",
+			url:    "https://api.internal/orders?key={{apiKey}}&user={{user}}",
+			header: `{{hmacSHA256 secretPass (concat "GET" user)}}`,
+			body:   `{"pass":"{{secretPass}}","hash":"{{sha256 apiKey}}"}`,
+			want: RequestSnapshot{
+				URL:     "https://api.internal/orders?key=" + secretMask + "&user=alice",
+				Headers: http.Header{"Authorization": {secretMask}},
+				Body:    `{"pass":"` + secretMask + `","hash":"` + secretMask + `"}`,
+				MaskedSecrets: []string{
+					"apiKey",
+					`hmacSHA256 secretPass (concat "GET" user)`,
+					"secretPass",
+					"sha256 apiKey",
+				},
+			},
+		},
+		{
+			name:   "derived from plain variables only",
+			url:    "https://api.internal/me",
+			header: basicAuthValue("{{user}}", "open"),
+			want: RequestSnapshot{
+				URL:     "https://api.internal/me",
+				Headers: http.Header{"Authorization": {"Basic YWxpY2U6b3Blbg=="}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secrets := make(revealedSecrets)
+			lookup := secretTrackingLookup(func(name string) (string, bool, error) {
+				value, ok := vars[name]
+				if ok && secretNames[name] {
+					secrets[name] = value
+				}
+				return value, ok, nil
+			}, secrets)
+
+			s := &RequestCRUDService{}
+			requestURL, err := s.resolveRequestURL(0, tt.url, lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			header, err := expandPlaceholders(tt.header, lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := expandPlaceholders(tt.body, lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sent := RequestSnapshot{URL: requestURL, Headers: http.Header{"Authorization": {header}}, Body: body}
+			got := maskSnapshot(sent, secrets)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("masked snapshot = %+v, want %+v", got, tt.want)
+			}
+			for _, value := range secrets {
+				if strings.Contains(got.URL+got.Body+got.Headers.Get("Authorization"), value) {
+					t.Errorf("masked snapshot still contains %q", value)
+				}
+			}
+		})
+	}
+}
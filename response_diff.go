@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+
+	// maxLineDiffCells bounds the LCS table used for text bodies; larger bodies are compared
+	// line by line instead.
+	maxLineDiffCells = 4_000_000
+)
+
+// DiffEntry describes one difference. For JSON bodies Path is a JSONPath-like location and
+// Before/After hold JSON-encoded values; for text bodies Path is the line number.
+type DiffEntry struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+type ResponseDiff struct {
+	StatusBefore  int         `json:"statusBefore"`
+	StatusAfter   int         `json:"statusAfter"`
+	StatusChanged bool        `json:"statusChanged"`
+	Headers       []DiffEntry `json:"headers"`
+	// BodyFormat is "json" when both bodies parsed as JSON and "text" otherwise.
+	BodyFormat string      `json:"bodyFormat"`
+	Body       []DiffEntry `json:"body"`
+}
+
+func (s *RequestCRUDService) PinResponse(id int) error {
+	return s.setResponsePinned(id, true)
+}
+
+func (s *RequestCRUDService) UnpinResponse(id int) error {
+	return s.setResponsePinned(id, false)
+}
+
+func (s *RequestCRUDService) setResponsePinned(id int, pinned bool) error {
+	result, err := s.db.Exec("UPDATE responses SET pinned = ? WHERE id = ?", pinned, id)
+	if err != nil {
+		return fmt.Errorf("failed to update response %d: %w", id, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("response %d not found", id)
+	}
+	return nil
+}
+
+// DiffResponses compares two recorded responses, a being the older one.
+func (s *RequestCRUDService) DiffResponses(a int, b int) (ResponseDiff, error) {
+	before, err := s.loadResponse(a)
+	if err != nil {
+		return ResponseDiff{}, err
+	}
+	after, err := s.loadResponse(b)
+	if err != nil {
+		return ResponseDiff{}, err
+	}
+
+	diff := ResponseDiff{
+		StatusBefore:  before.StatusCode,
+		StatusAfter:   after.StatusCode,
+		StatusChanged: before.StatusCode != after.StatusCode,
+		Headers:       diffHeaders(before.Headers, after.Headers),
+	}
+	diff.BodyFormat, diff.Body = diffBodies(before.Body, after.Body)
+	return diff, nil
+}
+
+func (s *RequestCRUDService) loadResponse(id int) (Response, error) {
+	var (
+		resp    Response
+		headers sql.NullString
+		body    sql.NullString
+	)
+	err := s.db.QueryRow("SELECT id, status_code, headers, body, request_id, pinned FROM responses WHERE id = ?", id).
+		Scan(&resp.ID, &resp.StatusCode, &headers, &body, &resp.RequestID, &resp.Pinned)
+	if err == sql.ErrNoRows {
+		return Response{}, fmt.Errorf("response %d not found", id)
+	}
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to load response %d: %w", id, err)
+	}
+	resp.Headers = headers.String
+	resp.Body = body.String
+	return resp, nil
+}
+
+func diffHeaders(before string, after string) []DiffEntry {
+	a, b := parseStoredHeaders(before), parseStoredHeaders(after)
+
+	names := make(map[string]bool)
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	entries := []DiffEntry{}
+	for _, name := range sorted {
+		va, inA := a[name]
+		vb, inB := b[name]
+		switch {
+		case !inA:
+			entries = append(entries, DiffEntry{Path: name, Kind: DiffAdded, After: vb})
+		case !inB:
+			entries = append(entries, DiffEntry{Path: name, Kind: DiffRemoved, Before: va})
+		case va != vb:
+			entries = append(entries, DiffEntry{Path: name, Kind: DiffChanged, Before: va, After: vb})
+		}
+	}
+	return entries
+}
+
+// parseStoredHeaders reads headers as stored by ExecuteRequest, a JSON object of value lists,
+// and also accepts single string values.
+func parseStoredHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return headers
+	}
+	for name, value := range decoded {
+		canonical := http.CanonicalHeaderKey(name)
+		switch v := value.(type) {
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, part := range v {
+				parts = append(parts, fmt.Sprint(part))
+			}
+			headers[canonical] = strings.Join(parts, ", ")
+		default:
+			headers[canonical] = fmt.Sprint(v)
+		}
+	}
+	return headers
+}
+
+func diffBodies(before string, after string) (string, []DiffEntry) {
+	a, aErr := decodeJSONBody(before)
+	b, bErr := decodeJSONBody(after)
+	if aErr == nil && bErr == nil {
+		entries := []DiffEntry{}
+		diffJSON("$", a, b, &entries)
+		return "json", entries
+	}
+	return "text", diffLines(before, after)
+}
+
+func decodeJSONBody(body string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(body)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return value, nil
+}
+
+func diffJSON(path string, a interface{}, b interface{}, entries *[]DiffEntry) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool)
+		for k := range av {
+			keys[k] = true
+		}
+		for k := range bv {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			childPath := jsonChildPath(path, k)
+			va, inA := av[k]
+			vb, inB := bv[k]
+			switch {
+			case !inA:
+				*entries = append(*entries, DiffEntry{Path: childPath, Kind: DiffAdded, After: encodeDiffValue(vb)})
+			case !inB:
+				*entries = append(*entries, DiffEntry{Path: childPath, Kind: DiffRemoved, Before: encodeDiffValue(va)})
+			default:
+				diffJSON(childPath, va, vb, entries)
+			}
+		}
+		return
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(av):
+				*entries = append(*entries, DiffEntry{Path: childPath, Kind: DiffAdded, After: encodeDiffValue(bv[i])})
+			case i >= len(bv):
+				*entries = append(*entries, DiffEntry{Path: childPath, Kind: DiffRemoved, Before: encodeDiffValue(av[i])})
+			default:
+				diffJSON(childPath, av[i], bv[i], entries)
+			}
+		}
+		return
+	}
+
+	if an, ok := a.(json.Number); ok {
+		if bn, ok := b.(json.Number); ok && equalJSONNumbers(an, bn) {
+			return
+		}
+	}
+	before, after := encodeDiffValue(a), encodeDiffValue(b)
+	if before != after {
+		*entries = append(*entries, DiffEntry{Path: path, Kind: DiffChanged, Before: before, After: after})
+	}
+}
+
+// equalJSONNumbers compares numbers by value, so 1, 1.0 and 1e0 are equal.
+func equalJSONNumbers(a json.Number, b json.Number) bool {
+	af, _, aErr := big.ParseFloat(a.String(), 10, 256, big.ToNearestEven)
+	bf, _, bErr := big.ParseFloat(b.String(), 10, 256, big.ToNearestEven)
+	if aErr != nil || bErr != nil {
+		return a == b
+	}
+	return af.Cmp(bf) == 0
+}
+
+func jsonChildPath(parent string, key string) string {
+	if isJSONPathIdentifier(key) {
+		return parent + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return parent + "[" + string(quoted) + "]"
+}
+
+// isJSONPathIdentifier reports whether key can follow a dot in a path, as in $.user.name.
+func isJSONPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_' || r == '$' || unicode.IsLetter(r):
+		case i > 0 && unicode.IsDigit(r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func encodeDiffValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// diffLines returns the added and removed lines between two texts using a longest common
+// subsequence, falling back to a positional comparison for very large bodies.
+func diffLines(before string, after string) []DiffEntry {
+	a := strings.Split(before, "\n")
+	b := strings.Split(after, "\n")
+	entries := []DiffEntry{}
+
+	if len(a)*len(b) > maxLineDiffCells {
+		for i := 0; i < len(a) || i < len(b); i++ {
+			path := fmt.Sprintf("line %d", i+1)
+			switch {
+			case i >= len(a):
+				entries = append(entries, DiffEntry{Path: path, Kind: DiffAdded, After: b[i]})
+			case i >= len(b):
+				entries = append(entries, DiffEntry{Path: path, Kind: DiffRemoved, Before: a[i]})
+			case a[i] != b[i]:
+				entries = append(entries, DiffEntry{Path: path, Kind: DiffChanged, Before: a[i], After: b[i]})
+			}
+		}
+		return entries
+	}
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			entries = append(entries, DiffEntry{Path: fmt.Sprintf("line %d", j+1), Kind: DiffAdded, After: b[j]})
+			j++
+		default:
+			entries = append(entries, DiffEntry{Path: fmt.Sprintf("line %d", i+1), Kind: DiffRemoved, Before: a[i]})
+			i++
+		}
+	}
+	return entries
+}
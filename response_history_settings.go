@@ -59,3 +59,51 @@ func saveResponseHistoryTTL(db *sql.DB, ttl int) error {
 	}
 	return nil
 }
+
+const (
+	responseHistoryMaxAgeKey  = "response_history_max_age_days"
+	responseHistoryMaxSizeKey = "response_history_max_size_mb"
+)
+
+// loadRetentionLimit reads a non-negative retention setting where zero means no limit.
+func loadRetentionLimit(db *sql.DB, key string) (int, error) {
+	if db == nil {
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	var raw string
+	err := db.QueryRow(`SELECT value FROM app_state WHERE key = ?`, key).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("invalid value %q for %s", raw, key)
+	}
+	return val, nil
+}
+
+func saveRetentionLimit(db *sql.DB, key string, limit int) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if limit < 0 {
+		return fmt.Errorf("%s must not be negative", key)
+	}
+
+	_, err := db.Exec(
+		`INSERT INTO app_state (key, value)
+		 VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		key,
+		fmt.Sprintf("%d", limit),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to persist %s: %w", key, err)
+	}
+	return nil
+}
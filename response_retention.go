@@ -0,0 +1,462 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const retentionJanitorInterval = time.Hour
+
+// RetentionPolicy overrides the global response history limits for a collection and its
+// sub-collections. A nil field inherits from the parent collection or the global setting;
+// zero disables that limit.
+type RetentionPolicy struct {
+	MaxEntries *int `json:"maxEntries"`
+	MaxAgeDays *int `json:"maxAgeDays"`
+}
+
+type RetentionResult struct {
+	Deleted    int   `json:"deleted"`
+	FreedBytes int64 `json:"freedBytes"`
+	Vacuumed   bool  `json:"vacuumed"`
+}
+
+func (s *RequestCRUDService) GetCollectionRetention(collectionID string) (RetentionPolicy, error) {
+	var maxEntries, maxAgeDays sql.NullInt64
+	err := s.db.QueryRow("SELECT max_entries, max_age_days FROM collection_retention WHERE collection_id = ?", collectionID).
+		Scan(&maxEntries, &maxAgeDays)
+	if err == sql.ErrNoRows {
+		return RetentionPolicy{}, nil
+	}
+	if err != nil {
+		return RetentionPolicy{}, fmt.Errorf("failed to load retention for collection %s: %w", collectionID, err)
+	}
+	return RetentionPolicy{MaxEntries: nullIntToPointer(maxEntries), MaxAgeDays: nullIntToPointer(maxAgeDays)}, nil
+}
+
+func (s *RequestCRUDService) SetCollectionRetention(collectionID string, policy RetentionPolicy) error {
+	if (policy.MaxEntries != nil && *policy.MaxEntries < 0) || (policy.MaxAgeDays != nil && *policy.MaxAgeDays < 0) {
+		return fmt.Errorf("retention limits must not be negative")
+	}
+	if policy.MaxEntries == nil && policy.MaxAgeDays == nil {
+		if _, err := s.db.Exec("DELETE FROM collection_retention WHERE collection_id = ?", collectionID); err != nil {
+			return fmt.Errorf("failed to clear retention for collection %s: %w", collectionID, err)
+		}
+		return nil
+	}
+
+	_, err := s.db.Exec(
+		`INSERT INTO collection_retention (collection_id, max_entries, max_age_days) VALUES (?, ?, ?)
+		 ON CONFLICT(collection_id) DO UPDATE SET max_entries = excluded.max_entries, max_age_days = excluded.max_age_days`,
+		collectionID,
+		policy.MaxEntries,
+		policy.MaxAgeDays,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save retention for collection %s: %w", collectionID, err)
+	}
+	return nil
+}
+
+// retentionVacuumThreshold is how many body bytes the janitor must free before it vacuums the
+// database on its own; pruning from the settings always vacuums.
+const retentionVacuumThreshold = 64 * 1024 * 1024
+
+// retentionBatchSize caps the number of request ids bound in one statement.
+const retentionBatchSize = 500
+
+// collectionAncestorsQuery lists a request's collection and the collections above it, nearest
+// first. It takes the request id.
+const collectionAncestorsQuery = `WITH RECURSIVE ancestors(id, depth) AS (
+	SELECT collection_id, 0 FROM requests WHERE id = ? AND collection_id IS NOT NULL
+	UNION
+	SELECT c.parent_collection, a.depth + 1 FROM collections c JOIN ancestors a ON c.id = a.id
+	WHERE c.parent_collection IS NOT NULL AND a.depth < 64
+)`
+
+// retentionLimits are the entry and age limits that apply to a request; zero disables a limit.
+type retentionLimits struct {
+	maxEntries int
+	maxAgeDays int
+}
+
+// retentionPolicies holds the global limits and every collection override, loaded once per
+// pruning pass so requests resolve their limits without further queries.
+type retentionPolicies struct {
+	global    retentionLimits
+	parents   map[string]string
+	overrides map[string]RetentionPolicy
+	resolved  map[string]retentionLimits
+}
+
+// PruneResponseHistory applies the retention policies to the responses and recorded failures
+// of every request, then trims the oldest responses until the stored bodies fit the global size
+// limit. Pinned responses are never removed. The database is vacuumed when anything was deleted.
+func (s *RequestCRUDService) PruneResponseHistory() (RetentionResult, error) {
+	return s.pruneResponseHistory(func(result RetentionResult) bool { return result.Deleted > 0 })
+}
+
+// pruneResponseHistory prunes as PruneResponseHistory does and vacuums when vacuum says so.
+// Requests sharing the same limits are pruned together.
+func (s *RequestCRUDService) pruneResponseHistory(vacuum func(RetentionResult) bool) (RetentionResult, error) {
+	s.retentionMu.Lock()
+	defer s.retentionMu.Unlock()
+
+	var result RetentionResult
+	if s.db == nil {
+		return result, fmt.Errorf("database not initialized")
+	}
+
+	policies, err := s.loadRetentionPolicies()
+	if err != nil {
+		return result, err
+	}
+	groups, err := s.requestsWithHistory(policies)
+	if err != nil {
+		return result, err
+	}
+	for limits, requestIDs := range groups {
+		for start := 0; start < len(requestIDs); start += retentionBatchSize {
+			end := min(start+retentionBatchSize, len(requestIDs))
+			n, freed, err := applyRetentionLimits(s.db, limits, requestIDs[start:end])
+			if err != nil {
+				return result, err
+			}
+			result.Deleted += n
+			result.FreedBytes += freed
+		}
+	}
+
+	n, freed, err := s.enforceHistorySizeLimit()
+	if err != nil {
+		return result, err
+	}
+	result.Deleted += n
+	result.FreedBytes += freed
+
+	if vacuum(result) {
+		if _, err := s.db.Exec("VACUUM"); err != nil {
+			return result, fmt.Errorf("failed to vacuum database: %w", err)
+		}
+		result.Vacuumed = true
+	}
+	return result, nil
+}
+
+// applyRetentionLimits prunes the responses and failures of requests that share limits.
+func applyRetentionLimits(db *sql.DB, limits retentionLimits, requestIDs []int) (int, int64, error) {
+	inList, ids := sqlInList(requestIDs)
+	deleted, freedBytes := 0, int64(0)
+
+	if limits.maxAgeDays > 0 {
+		cutoff := time.Now().UTC().AddDate(0, 0, -limits.maxAgeDays)
+		n, freed, err := deleteResponses(db,
+			"request_id IN "+inList+" AND pinned = 0 AND COALESCE(created_at, CURRENT_TIMESTAMP) < ?", append(ids, cutoff)...)
+		if err != nil {
+			return deleted, freedBytes, err
+		}
+		deleted += n
+		freedBytes += freed
+
+		n, err = deleteFailures(db, "request_id IN "+inList+" AND COALESCE(created_at, CURRENT_TIMESTAMP) < ?", append(ids, cutoff)...)
+		if err != nil {
+			return deleted, freedBytes, err
+		}
+		deleted += n
+	}
+	if limits.maxEntries > 0 {
+		n, freed, err := deleteResponses(db,
+			`id IN (
+			     SELECT id FROM (
+			         SELECT id, ROW_NUMBER() OVER (
+			             PARTITION BY request_id ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC
+			         ) AS position
+			         FROM responses WHERE request_id IN `+inList+` AND pinned = 0
+			     ) WHERE position > ?
+			 )`,
+			append(ids, limits.maxEntries)...,
+		)
+		if err != nil {
+			return deleted, freedBytes, err
+		}
+		deleted += n
+		freedBytes += freed
+
+		n, err = deleteFailures(db,
+			`id IN (
+			     SELECT id FROM (
+			         SELECT id, ROW_NUMBER() OVER (
+			             PARTITION BY request_id ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC
+			         ) AS position
+			         FROM request_failures WHERE request_id IN `+inList+`
+			     ) WHERE position > ?
+			 )`,
+			append(ids, limits.maxEntries)...,
+		)
+		if err != nil {
+			return deleted, freedBytes, err
+		}
+		deleted += n
+	}
+	return deleted, freedBytes, nil
+}
+
+// runRetentionJanitor prunes response history at startup and then periodically until ctx is
+// done. It only vacuums once enough space was freed to be worth rewriting the database.
+func (s *RequestCRUDService) runRetentionJanitor(ctx context.Context) {
+	ticker := time.NewTicker(retentionJanitorInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.pruneResponseHistory(func(result RetentionResult) bool {
+			return result.FreedBytes >= retentionVacuumThreshold
+		})
+		if err != nil {
+			fmt.Println("Failed to prune response history:", err)
+		} else if result.Deleted > 0 {
+			fmt.Printf("Pruned %d responses, freed %d bytes\n", result.Deleted, result.FreedBytes)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// requestsWithHistory groups the requests that have unpinned responses or recorded failures
+// by the limits that apply to them.
+func (s *RequestCRUDService) requestsWithHistory(policies *retentionPolicies) (map[retentionLimits][]int, error) {
+	rows, err := s.db.Query(
+		`SELECT h.request_id, r.collection_id FROM (
+		     SELECT request_id FROM responses WHERE pinned = 0 AND request_id IS NOT NULL
+		     UNION SELECT request_id FROM request_failures
+		 ) h LEFT JOIN requests r ON r.id = h.request_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list response history: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make(map[retentionLimits][]int)
+	for rows.Next() {
+		var id int
+		var collectionID sql.NullString
+		if err := rows.Scan(&id, &collectionID); err != nil {
+			return nil, err
+		}
+		limits := policies.forCollection(collectionID.String)
+		groups[limits] = append(groups[limits], id)
+	}
+	return groups, rows.Err()
+}
+
+func (s *RequestCRUDService) globalRetentionLimits() retentionLimits {
+	maxAgeDays, err := loadRetentionLimit(s.db, responseHistoryMaxAgeKey)
+	if err != nil {
+		fmt.Println("Failed to load response history max age:", err)
+	}
+	return retentionLimits{maxEntries: s.getResponseHistoryLimit(), maxAgeDays: maxAgeDays}
+}
+
+func (s *RequestCRUDService) loadRetentionPolicies() (*retentionPolicies, error) {
+	policies := &retentionPolicies{
+		global:    s.globalRetentionLimits(),
+		parents:   make(map[string]string),
+		overrides: make(map[string]RetentionPolicy),
+		resolved:  make(map[string]retentionLimits),
+	}
+
+	rows, err := s.db.Query("SELECT id, parent_collection FROM collections")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load collections: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		var parent sql.NullString
+		if err := rows.Scan(&id, &parent); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		policies.parents[id] = parent.String
+	}
+	rows.Close()
+
+	rows, err = s.db.Query("SELECT collection_id, max_entries, max_age_days FROM collection_retention")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load collection retention: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var maxEntries, maxAgeDays sql.NullInt64
+		if err := rows.Scan(&id, &maxEntries, &maxAgeDays); err != nil {
+			return nil, err
+		}
+		policies.overrides[id] = RetentionPolicy{MaxEntries: nullIntToPointer(maxEntries), MaxAgeDays: nullIntToPointer(maxAgeDays)}
+	}
+	return policies, rows.Err()
+}
+
+// forCollection resolves the limits for requests in a collection from the nearest collection
+// that overrides each of them, falling back to the global settings.
+func (p *retentionPolicies) forCollection(collectionID string) retentionLimits {
+	if limits, ok := p.resolved[collectionID]; ok {
+		return limits
+	}
+	limits := p.global
+	entriesSet, ageSet := false, false
+	visited := make(map[string]bool)
+	for id := collectionID; id != "" && !visited[id]; id = p.parents[id] {
+		visited[id] = true
+		policy := p.overrides[id]
+		if !entriesSet && policy.MaxEntries != nil {
+			limits.maxEntries, entriesSet = *policy.MaxEntries, true
+		}
+		if !ageSet && policy.MaxAgeDays != nil {
+			limits.maxAgeDays, ageSet = *policy.MaxAgeDays, true
+		}
+	}
+	p.resolved[collectionID] = limits
+	return limits
+}
+
+// effectiveRetention resolves a single request's entry and age limits, reading only the
+// overrides along its collection chain.
+func (s *RequestCRUDService) effectiveRetention(requestID int) (int, int) {
+	limits := s.globalRetentionLimits()
+
+	var maxEntries, maxAgeDays sql.NullInt64
+	err := s.db.QueryRow(
+		collectionAncestorsQuery+`
+		 SELECT
+		     (SELECT r.max_entries FROM ancestors a JOIN collection_retention r ON r.collection_id = a.id
+		      WHERE r.max_entries IS NOT NULL ORDER BY a.depth LIMIT 1),
+		     (SELECT r.max_age_days FROM ancestors a JOIN collection_retention r ON r.collection_id = a.id
+		      WHERE r.max_age_days IS NOT NULL ORDER BY a.depth LIMIT 1)`,
+		requestID,
+	).Scan(&maxEntries, &maxAgeDays)
+	if err != nil {
+		fmt.Println("Failed to resolve collection retention:", err)
+		return limits.maxEntries, limits.maxAgeDays
+	}
+	if maxEntries.Valid {
+		limits.maxEntries = int(maxEntries.Int64)
+	}
+	if maxAgeDays.Valid {
+		limits.maxAgeDays = int(maxAgeDays.Int64)
+	}
+	return limits.maxEntries, limits.maxAgeDays
+}
+
+// trimResponseHistory keeps the newest maxEntries unpinned responses of a request.
+func trimResponseHistory(db *sql.DB, requestID int, maxEntries int) (int, int64, error) {
+	return deleteResponses(db,
+		`request_id = ?
+		 AND pinned = 0
+		 AND id NOT IN (
+		     SELECT id FROM responses
+		     WHERE request_id = ? AND pinned = 0
+		     ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC
+		     LIMIT ?
+		 )`,
+		requestID,
+		requestID,
+		maxEntries,
+	)
+}
+
+// enforceHistorySizeLimit deletes the oldest unpinned responses, across all requests, until
+// the stored bodies fit within the global size limit.
+func (s *RequestCRUDService) enforceHistorySizeLimit() (int, int64, error) {
+	limitMB, err := loadRetentionLimit(s.db, responseHistoryMaxSizeKey)
+	if err != nil {
+		fmt.Println("Failed to load response history max size:", err)
+		return 0, 0, nil
+	}
+	if limitMB <= 0 {
+		return 0, 0, nil
+	}
+	limit := int64(limitMB) * 1024 * 1024
+
+	var total int64
+	if err := s.db.QueryRow("SELECT COALESCE(SUM(LENGTH(CAST(body AS BLOB))), 0) FROM responses").Scan(&total); err != nil {
+		return 0, 0, fmt.Errorf("failed to measure response history: %w", err)
+	}
+	if total <= limit {
+		return 0, 0, nil
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, COALESCE(LENGTH(CAST(body AS BLOB)), 0) FROM responses
+		 WHERE pinned = 0
+		 ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP), id`,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list response history: %w", err)
+	}
+	var ids []int
+	for rows.Next() && total > limit {
+		var (
+			id   int
+			size int64
+		)
+		if err := rows.Scan(&id, &size); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		ids = append(ids, id)
+		total -= size
+	}
+	rows.Close()
+
+	deleted, freed := 0, int64(0)
+	for start := 0; start < len(ids); start += retentionBatchSize {
+		inList, args := sqlInList(ids[start:min(start+retentionBatchSize, len(ids))])
+		n, size, err := deleteResponses(s.db, "id IN "+inList, args...)
+		if err != nil {
+			return deleted, freed, err
+		}
+		deleted += n
+		freed += size
+	}
+	return deleted, freed, nil
+}
+
+// sqlInList returns a parenthesised placeholder list for ids and the matching arguments.
+func sqlInList(ids []int) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i], args[i] = "?", id
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")", args
+}
+
+// deleteResponses removes the responses matching where and reports how many rows and body
+// bytes were removed.
+func deleteResponses(db *sql.DB, where string, args ...interface{}) (int, int64, error) {
+	var freed int64
+	if err := db.QueryRow("SELECT COALESCE(SUM(LENGTH(CAST(body AS BLOB))), 0) FROM responses WHERE "+where, args...).Scan(&freed); err != nil {
+		return 0, 0, fmt.Errorf("failed to measure responses: %w", err)
+	}
+	result, err := db.Exec("DELETE FROM responses WHERE "+where, args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete responses: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), freed, nil
+}
+
+func deleteFailures(db *sql.DB, where string, args ...interface{}) (int, error) {
+	result, err := db.Exec("DELETE FROM request_failures WHERE "+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete request failures: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
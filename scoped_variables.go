@@ -0,0 +1,265 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Variable struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+}
+
+type ResolvedVariable struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Scope  string `json:"scope"`
+	Source string `json:"source"`
+}
+
+const (
+	VariableScopeGlobal      = "global"
+	VariableScopeEnvironment = "environment"
+	VariableScopeCollection  = "collection"
+	VariableScopeRequest     = "request"
+	VariableScopeRuntime     = "runtime"
+)
+
+func (s *RequestCRUDService) GetCollectionVariables(collectionID string) []Variable {
+	return s.loadVariables("SELECT key, value, enabled FROM collection_variables WHERE collection_id = ? ORDER BY COALESCE(sort_order, 0), key", collectionID)
+}
+
+func (s *RequestCRUDService) SetCollectionVariables(collectionID string, variables []Variable) error {
+	return s.replaceVariables("collection_variables", "collection_id", collectionID, variables)
+}
+
+func (s *RequestCRUDService) GetRequestVariables(requestID int) []Variable {
+	return s.loadVariables("SELECT key, value, enabled FROM request_variables WHERE request_id = ? ORDER BY COALESCE(sort_order, 0), key", requestID)
+}
+
+func (s *RequestCRUDService) SetRequestVariables(requestID int, variables []Variable) error {
+	return s.replaceVariables("request_variables", "request_id", requestID, variables)
+}
+
+// ResolveVariables returns every variable visible to a request together with the scope that
+// supplied it. Secret values stay masked.
+func (s *RequestCRUDService) ResolveVariables(requestID int, environment string) []ResolvedVariable {
+	layers, err := s.variableLayers(requestID, environment)
+	if err != nil {
+		fmt.Println("Failed to resolve variables:", err)
+	}
+
+	merged := make(map[string]ResolvedVariable)
+	for _, layer := range layers {
+		for key, value := range layer.values {
+			if isSecretValue(value) {
+				value = secretMask
+			}
+			merged[key] = ResolvedVariable{Key: key, Value: value, Scope: layer.scope, Source: layer.sources[key]}
+		}
+	}
+
+	resolved := make([]ResolvedVariable, 0, len(merged))
+	for _, v := range merged {
+		resolved = append(resolved, v)
+	}
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].Key < resolved[j].Key })
+	return resolved
+}
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx.
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// sqlQueryExecer is satisfied by both *sql.DB and *sql.Tx.
+type sqlQueryExecer interface {
+	sqlExecer
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type variableLayer struct {
+	scope   string
+	values  map[string]string
+	sources map[string]string
+}
+
+// variableLayers returns the variable scopes for a request from lowest to highest precedence:
+// global, environment, the collection chain from the root folder down, the request itself and
+// finally the runtime variables set by scripts.
+func (s *RequestCRUDService) variableLayers(requestID int, environment string) ([]variableLayer, error) {
+	var layers []variableLayer
+
+	if s.envars != nil {
+		resolved, err := s.envars.resolvedEnvironment(environment)
+		if err != nil {
+			fmt.Println("Failed to load environment:", err)
+		}
+		global := variableLayer{scope: VariableScopeGlobal, values: map[string]string{}, sources: map[string]string{}}
+		env := variableLayer{scope: VariableScopeEnvironment, values: map[string]string{}, sources: map[string]string{}}
+		for key, value := range resolved.vars {
+			source := resolved.sources[key]
+			if isGlobalEnvironment(source) {
+				global.values[key] = value
+				global.sources[key] = source
+			} else {
+				env.values[key] = value
+				env.sources[key] = source
+			}
+		}
+		layers = append(layers, global, env)
+	}
+
+	if s.db == nil || requestID <= 0 {
+		return append(layers, s.runtimeVariableLayer()), nil
+	}
+
+	var collectionID sql.NullString
+	err := s.db.QueryRow("SELECT collection_id FROM requests WHERE id = ?", requestID).Scan(&collectionID)
+	if err != nil && err != sql.ErrNoRows {
+		return layers, fmt.Errorf("failed to load request %d: %w", requestID, err)
+	}
+
+	chain, err := s.collectionChain(collectionID.String)
+	if err != nil {
+		return layers, err
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		layer := variableLayer{scope: VariableScopeCollection, values: map[string]string{}, sources: map[string]string{}}
+		for _, v := range s.GetCollectionVariables(chain[i]) {
+			if v.Enabled {
+				layer.values[v.Key] = v.Value
+				layer.sources[v.Key] = chain[i]
+			}
+		}
+		layers = append(layers, layer)
+	}
+
+	requestLayer := variableLayer{scope: VariableScopeRequest, values: map[string]string{}, sources: map[string]string{}}
+	for _, v := range s.GetRequestVariables(requestID) {
+		if v.Enabled {
+			requestLayer.values[v.Key] = v.Value
+			requestLayer.sources[v.Key] = fmt.Sprintf("%d", requestID)
+		}
+	}
+	layers = append(layers, requestLayer, s.runtimeVariableLayer())
+
+	return layers, nil
+}
+
+func (s *RequestCRUDService) runtimeVariableLayer() variableLayer {
+	s.runtimeMu.Lock()
+	defer s.runtimeMu.Unlock()
+
+	layer := variableLayer{scope: VariableScopeRuntime, values: map[string]string{}, sources: map[string]string{}}
+	for key, value := range s.runtimeVars {
+		layer.values[key] = value
+		layer.sources[key] = "script"
+	}
+	return layer
+}
+
+// collectionChain returns the collection and its ancestors, nearest first.
+func (s *RequestCRUDService) collectionChain(collectionID string) ([]string, error) {
+	var chain []string
+	visited := make(map[string]bool)
+	current := collectionID
+	for current != "" && !visited[current] {
+		visited[current] = true
+		chain = append(chain, current)
+
+		var parent sql.NullString
+		err := s.db.QueryRow("SELECT parent_collection FROM collections WHERE id = ?", current).Scan(&parent)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return chain, fmt.Errorf("failed to load collection %s: %w", current, err)
+		}
+		current = parent.String
+	}
+	return chain, nil
+}
+
+func (s *RequestCRUDService) loadVariables(query string, owner interface{}) []Variable {
+	variables := []Variable{}
+	if s.db == nil {
+		return variables
+	}
+
+	rows, err := s.db.Query(query, owner)
+	if err != nil {
+		fmt.Println("Failed to load variables:", err)
+		return variables
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			v       Variable
+			value   sql.NullString
+			enabled bool
+		)
+		if err := rows.Scan(&v.Key, &value, &enabled); err != nil {
+			fmt.Println("Failed to scan variable:", err)
+			continue
+		}
+		v.Value = value.String
+		v.Enabled = enabled
+		variables = append(variables, v)
+	}
+	return variables
+}
+
+func (s *RequestCRUDService) replaceVariables(table string, ownerColumn string, owner interface{}, variables []Variable) error {
+	if s.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start variable update transaction: %w", err)
+	}
+	if err := insertVariables(tx, table, ownerColumn, owner, variables, true); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit variable updates: %w", err)
+	}
+	return nil
+}
+
+// insertVariables writes variables for one owner, optionally clearing the existing ones first.
+// Later duplicates of a key win, matching how the scopes themselves are merged.
+func insertVariables(tx sqlExecer, table string, ownerColumn string, owner interface{}, variables []Variable, replace bool) error {
+	if replace {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table, ownerColumn), owner); err != nil {
+			return fmt.Errorf("failed to clear variables: %w", err)
+		}
+	}
+
+	for i, v := range variables {
+		key := strings.TrimSpace(v.Key)
+		if key == "" {
+			continue
+		}
+		_, err := tx.Exec(
+			fmt.Sprintf(`INSERT INTO %s (%s, key, value, enabled, sort_order)
+			 VALUES (?, ?, ?, ?, ?)
+			 ON CONFLICT(%s, key) DO UPDATE SET value = excluded.value, enabled = excluded.enabled, sort_order = excluded.sort_order`, table, ownerColumn, ownerColumn),
+			owner,
+			key,
+			v.Value,
+			v.Enabled,
+			i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save variable %s: %w", key, err)
+		}
+	}
+	return nil
+}
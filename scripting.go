@@ -0,0 +1,629 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+type RequestScripts struct {
+	PreRequest   string `json:"preRequest"`
+	PostResponse string `json:"postResponse"`
+}
+
+type ScriptTestResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ScriptResult struct {
+	Tests []ScriptTestResult `json:"tests"`
+	Logs  []string           `json:"logs"`
+	Error string             `json:"error,omitempty"`
+}
+
+const scriptTimeout = 5 * time.Second
+
+// scriptRequest is the outgoing request as pre-request scripts see and modify it.
+type scriptRequest struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers []map[string]string `json:"headers"`
+	Body    string              `json:"body"`
+	Auth    string              `json:"auth"`
+}
+
+type scriptResponse struct {
+	Code         int                 `json:"code"`
+	Status       string              `json:"status"`
+	Headers      map[string][]string `json:"headers"`
+	Body         string              `json:"body"`
+	ResponseTime int64               `json:"responseTime"`
+}
+
+func (s *RequestCRUDService) GetRequestScripts(requestID int) RequestScripts {
+	var preRequest, postResponse sql.NullString
+	err := s.db.QueryRow("SELECT pre_request, post_response FROM request_scripts WHERE request_id = ?", requestID).Scan(&preRequest, &postResponse)
+	if err != nil && err != sql.ErrNoRows {
+		fmt.Println("Failed to load request scripts:", err)
+	}
+	return RequestScripts{PreRequest: preRequest.String, PostResponse: postResponse.String}
+}
+
+func (s *RequestCRUDService) SetRequestScripts(requestID int, scripts RequestScripts) error {
+	if strings.TrimSpace(scripts.PreRequest) == "" && strings.TrimSpace(scripts.PostResponse) == "" {
+		if _, err := s.db.Exec("DELETE FROM request_scripts WHERE request_id = ?", requestID); err != nil {
+			return fmt.Errorf("failed to clear request scripts: %w", err)
+		}
+		return nil
+	}
+
+	_, err := s.db.Exec(
+		`INSERT INTO request_scripts (request_id, pre_request, post_response)
+		 VALUES (?, ?, ?)
+		 ON CONFLICT(request_id) DO UPDATE SET pre_request = excluded.pre_request, post_response = excluded.post_response`,
+		requestID,
+		scripts.PreRequest,
+		scripts.PostResponse,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save request scripts: %w", err)
+	}
+	return nil
+}
+
+// GetCollectionScripts returns the scripts that run before and after every request in a
+// collection and the collections below it.
+func (s *RequestCRUDService) GetCollectionScripts(collectionID string) RequestScripts {
+	var preRequest, postResponse sql.NullString
+	err := s.db.QueryRow("SELECT pre_request, post_response FROM collection_scripts WHERE collection_id = ?", collectionID).Scan(&preRequest, &postResponse)
+	if err != nil && err != sql.ErrNoRows {
+		fmt.Println("Failed to load collection scripts:", err)
+	}
+	return RequestScripts{PreRequest: preRequest.String, PostResponse: postResponse.String}
+}
+
+func (s *RequestCRUDService) SetCollectionScripts(collectionID string, scripts RequestScripts) error {
+	if strings.TrimSpace(scripts.PreRequest) == "" && strings.TrimSpace(scripts.PostResponse) == "" {
+		if _, err := s.db.Exec("DELETE FROM collection_scripts WHERE collection_id = ?", collectionID); err != nil {
+			return fmt.Errorf("failed to clear collection scripts: %w", err)
+		}
+		return nil
+	}
+
+	_, err := s.db.Exec(
+		`INSERT INTO collection_scripts (collection_id, pre_request, post_response)
+		 VALUES (?, ?, ?)
+		 ON CONFLICT(collection_id) DO UPDATE SET pre_request = excluded.pre_request, post_response = excluded.post_response`,
+		collectionID,
+		scripts.PreRequest,
+		scripts.PostResponse,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save collection scripts: %w", err)
+	}
+	return nil
+}
+
+// requestScripts lists the pre-request and post-response scripts that run for a request: those
+// of its collections from the outermost in, then its own, as Postman runs them.
+func (s *RequestCRUDService) requestScripts(requestID int) ([]string, []string) {
+	var preRequest, postResponse []string
+	var collectionID sql.NullString
+	if err := s.db.QueryRow("SELECT collection_id FROM requests WHERE id = ?", requestID).Scan(&collectionID); err != nil && err != sql.ErrNoRows {
+		fmt.Println("Failed to load request collection:", err)
+	}
+	if collectionID.Valid {
+		chain, err := s.collectionChain(collectionID.String)
+		if err != nil {
+			fmt.Println("Failed to resolve collection scripts:", err)
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			scripts := s.GetCollectionScripts(chain[i])
+			preRequest = append(preRequest, scripts.PreRequest)
+			postResponse = append(postResponse, scripts.PostResponse)
+		}
+	}
+	scripts := s.GetRequestScripts(requestID)
+	return append(preRequest, scripts.PreRequest), append(postResponse, scripts.PostResponse)
+}
+
+// runScripts runs scripts in order, skipping empty ones and stopping at the first that fails,
+// and combines their tests and logs. ran is false when every script was empty.
+func (s *RequestCRUDService) runScripts(scripts []string, requestID int, environment string, request *scriptRequest, response *scriptResponse) (ScriptResult, bool) {
+	combined := ScriptResult{Tests: []ScriptTestResult{}, Logs: []string{}}
+	ran := false
+	for _, script := range scripts {
+		if strings.TrimSpace(script) == "" {
+			continue
+		}
+		ran = true
+		result := s.runScript(script, requestID, environment, request, response)
+		combined.Tests = append(combined.Tests, result.Tests...)
+		combined.Logs = append(combined.Logs, result.Logs...)
+		if result.Error != "" {
+			combined.Error = result.Error
+			break
+		}
+	}
+	return combined, ran
+}
+
+func (s *RequestCRUDService) ClearRuntimeVariables() {
+	s.runtimeMu.Lock()
+	s.runtimeVars = nil
+	s.runtimeMu.Unlock()
+}
+
+// runScript executes a pre-request script when response is nil and a post-response script
+// otherwise. Changes a pre-request script makes to pm.request are written back into request.
+func (s *RequestCRUDService) runScript(script string, requestID int, environment string, request *scriptRequest, response *scriptResponse) ScriptResult {
+	result := ScriptResult{Tests: []ScriptTestResult{}, Logs: []string{}}
+
+	vm := goja.New()
+	timer := time.AfterFunc(scriptTimeout, func() {
+		vm.Interrupt(fmt.Sprintf("script exceeded %s", scriptTimeout))
+	})
+	defer timer.Stop()
+
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	responseJSON := []byte("null")
+	eventName := "prerequest"
+	if response != nil {
+		eventName = "test"
+		if responseJSON, err = json.Marshal(response); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	host := map[string]interface{}{
+		"getVariable": func(scope string, key string) (goja.Value, error) {
+			value, ok, err := s.scriptVariable(scope, key, requestID, environment)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return goja.Undefined(), nil
+			}
+			return vm.ToValue(value), nil
+		},
+		"setVariable": func(scope string, key string, value string) error {
+			return s.setScriptVariable(scope, key, value, environment, false)
+		},
+		"unsetVariable": func(scope string, key string) error {
+			return s.setScriptVariable(scope, key, "", environment, true)
+		},
+		"log": func(level string, message string) {
+			if level != "log" {
+				message = "[" + level + "] " + message
+			}
+			result.Logs = append(result.Logs, message)
+		},
+		"recordTest": func(name string, passed bool, message string) {
+			result.Tests = append(result.Tests, ScriptTestResult{Name: name, Passed: passed, Error: message})
+		},
+	}
+
+	globals := map[string]interface{}{
+		"__host":        host,
+		"__request":     string(requestJSON),
+		"__response":    string(responseJSON),
+		"__environment": environment,
+		"__event":       eventName,
+		"__requestId":   requestID,
+	}
+	for name, value := range globals {
+		if err := vm.Set(name, value); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	if _, err := vm.RunString(scriptPrelude); err != nil {
+		result.Error = fmt.Sprintf("failed to initialise script runtime: %v", err)
+		return result
+	}
+	if _, err := vm.RunString(script); err != nil {
+		result.Error = scriptErrorMessage(err)
+		return result
+	}
+
+	if response == nil {
+		exported, err := vm.RunString("__exportRequest()")
+		if err != nil {
+			result.Error = scriptErrorMessage(err)
+			return result
+		}
+		var updated scriptRequest
+		if err := json.Unmarshal([]byte(exported.String()), &updated); err != nil {
+			result.Error = fmt.Sprintf("script produced an invalid request: %v", err)
+			return result
+		}
+		*request = updated
+	}
+	return result
+}
+
+func scriptErrorMessage(err error) string {
+	if exception, ok := err.(*goja.Exception); ok {
+		return exception.Error()
+	}
+	if interrupted, ok := err.(*goja.InterruptedError); ok {
+		return fmt.Sprint(interrupted.Value())
+	}
+	return err.Error()
+}
+
+// scriptVariable reads a variable for pm.variables, pm.environment or pm.globals. pm.variables
+// sees every scope the request resolves placeholders from.
+func (s *RequestCRUDService) scriptVariable(scope string, key string, requestID int, environment string) (string, bool, error) {
+	switch scope {
+	case "variables":
+		return s.variableLookup(requestID, environment)(key)
+	case "environment", "globals":
+		if s.envars == nil {
+			return "", false, nil
+		}
+		name := environment
+		if scope == "globals" {
+			name = ""
+		} else if name == "" {
+			return "", false, nil
+		}
+		raw, ok := resolveEnvironment(name).vars[key]
+		if !ok {
+			return "", false, nil
+		}
+		value, err := s.envars.revealValue(raw)
+		return value, err == nil, err
+	}
+	return "", false, fmt.Errorf("unknown variable scope %s", scope)
+}
+
+func (s *RequestCRUDService) setScriptVariable(scope string, key string, value string, environment string, unset bool) error {
+	if scope == "variables" {
+		s.runtimeMu.Lock()
+		defer s.runtimeMu.Unlock()
+		if unset {
+			delete(s.runtimeVars, key)
+			return nil
+		}
+		if s.runtimeVars == nil {
+			s.runtimeVars = make(map[string]string)
+		}
+		s.runtimeVars[key] = value
+		return nil
+	}
+
+	if s.envars == nil {
+		return fmt.Errorf("environments are not available")
+	}
+	var filename string
+	switch scope {
+	case "environment":
+		if environment == "" {
+			return fmt.Errorf("no environment is selected")
+		}
+		filename = environment
+	case "globals":
+		filename = globalEnvironmentFile()
+	default:
+		return fmt.Errorf("unknown variable scope %s", scope)
+	}
+	if unset {
+		return s.envars.unsetVariable(filename, key)
+	}
+	return s.envars.setVariable(filename, key, value)
+}
+
+// scriptPrelude builds the Postman-style pm object on top of the __host bindings.
+const scriptPrelude = `
+var pm = (function () {
+    function text(value) {
+        if (value === undefined || value === null) return "";
+        return typeof value === "string" ? value : JSON.stringify(value);
+    }
+
+    function scope(name) {
+        return {
+            get: function (key) { return __host.getVariable(name, String(key)); },
+            has: function (key) { return __host.getVariable(name, String(key)) !== undefined; },
+            set: function (key, value) { __host.setVariable(name, String(key), text(value)); },
+            unset: function (key) { __host.unsetVariable(name, String(key)); }
+        };
+    }
+
+    // propertyList wraps a list of {key, value} members the way Postman's PropertyList does.
+    // Header names match case-insensitively, query keys exactly.
+    function propertyList(list, ignoreCase) {
+        function same(a, b) {
+            a = String(a);
+            b = String(b);
+            return ignoreCase ? a.toLowerCase() === b.toLowerCase() : a === b;
+        }
+        function find(key) {
+            for (var i = 0; i < list.length; i++) {
+                if (same(list[i].key, key)) return i;
+            }
+            return -1;
+        }
+        return {
+            members: list,
+            get: function (key) { var i = find(key); return i === -1 ? undefined : list[i].value; },
+            has: function (key) { return find(key) !== -1; },
+            add: function (h) { list.push({ key: String(h.key), value: text(h.value) }); },
+            upsert: function (h) {
+                var i = find(h.key);
+                if (i === -1) this.add(h); else list[i].value = text(h.value);
+            },
+            remove: function (key) {
+                for (var i = list.length - 1; i >= 0; i--) {
+                    if (same(list[i].key, key)) list.splice(i, 1);
+                }
+            },
+            each: function (fn) { list.forEach(fn); },
+            count: function () { return list.length; },
+            toObject: function () {
+                var o = {};
+                list.forEach(function (h) { o[h.key] = h.value; });
+                return o;
+            }
+        };
+    }
+
+    function headerList(list) {
+        return propertyList(list, true);
+    }
+
+    // Url mirrors Postman's Url object. Query keys and values are kept as written, and a key
+    // without "=" has a null value, so an unmodified URL converts back to the same string.
+    function Url(raw) {
+        this.update(raw);
+    }
+    Url.prototype.update = function (raw) {
+        var rest = text(raw), index, match;
+        this.hash = undefined;
+        index = rest.indexOf("#");
+        if (index !== -1) {
+            this.hash = rest.slice(index + 1);
+            rest = rest.slice(0, index);
+        }
+        var members = [];
+        index = rest.indexOf("?");
+        if (index !== -1) {
+            rest.slice(index + 1).split("&").forEach(function (part) {
+                if (part === "") return;
+                var eq = part.indexOf("=");
+                members.push(eq === -1 ? { key: part, value: null } : { key: part.slice(0, eq), value: part.slice(eq + 1) });
+            });
+            rest = rest.slice(0, index);
+        }
+        this.query = propertyList(members, false);
+        match = /^([A-Za-z][A-Za-z0-9+.-]*):\/\//.exec(rest);
+        this.protocol = match ? match[1] : undefined;
+        if (match) rest = rest.slice(match[0].length);
+        index = rest.indexOf("/");
+        var authority = index === -1 ? rest : rest.slice(0, index);
+        this.path = index === -1 ? [] : rest.slice(index + 1).split("/");
+        index = authority.lastIndexOf("@");
+        this.auth = index === -1 ? undefined : authority.slice(0, index);
+        if (index !== -1) authority = authority.slice(index + 1);
+        match = /:(\d+|\{\{[^{}]+\}\})$/.exec(authority);
+        this.port = match ? match[1] : undefined;
+        if (match) authority = authority.slice(0, match.index);
+        this.host = authority === "" ? [] : authority.split(".");
+    };
+    Url.prototype.getHost = function () { return this.host.join("."); };
+    Url.prototype.getRemote = function () { return this.getHost() + (this.port ? ":" + this.port : ""); };
+    Url.prototype.getPath = function () { return "/" + this.path.join("/"); };
+    Url.prototype.getQueryString = function () {
+        return this.query.members.map(function (p) {
+            return p.value === null || p.value === undefined ? String(p.key) : p.key + "=" + p.value;
+        }).join("&");
+    };
+    Url.prototype.getPathWithQuery = function () {
+        var query = this.getQueryString();
+        return this.getPath() + (query ? "?" + query : "");
+    };
+    Url.prototype.addQueryParams = function (params) {
+        var query = this.query;
+        if (typeof params === "string") {
+            Array.prototype.push.apply(query.members, new Url("?" + params).query.members);
+            return;
+        }
+        [].concat(params).forEach(function (p) { query.add(p); });
+    };
+    Url.prototype.removeQueryParams = function (keys) {
+        var query = this.query;
+        [].concat(keys).forEach(function (key) { query.remove(key && key.key !== undefined ? key.key : key); });
+    };
+    Url.prototype.toString = function () {
+        var url = this.protocol ? this.protocol + "://" : "";
+        if (this.auth !== undefined) url += this.auth + "@";
+        url += this.getRemote();
+        if (this.path.length > 0) url += this.getPath();
+        var query = this.getQueryString();
+        if (query) url += "?" + query;
+        if (this.hash !== undefined) url += "#" + this.hash;
+        return url;
+    };
+
+    function Assertion(actual, negate) {
+        this.actual = actual;
+        this.negate = !!negate;
+    }
+    ["to", "be", "been", "is", "that", "which", "and", "has", "have", "with", "at", "of", "same", "deep", "does"].forEach(function (word) {
+        Object.defineProperty(Assertion.prototype, word, { get: function () { return this; } });
+    });
+    Object.defineProperty(Assertion.prototype, "not", {
+        get: function () { return new Assertion(this.actual, !this.negate); }
+    });
+    Assertion.prototype.assert = function (passed, message) {
+        if (this.negate ? passed : !passed) {
+            throw new Error((this.negate ? "expected not: " : "expected: ") + message);
+        }
+        return this;
+    };
+    function show(value) {
+        try { return JSON.stringify(value); } catch (e) { return String(value); }
+    }
+    function deepEqual(a, b) { return show(a) === show(b); }
+    Assertion.prototype.equal = function (v) { return this.assert(this.actual === v, show(this.actual) + " to equal " + show(v)); };
+    Assertion.prototype.equals = Assertion.prototype.equal;
+    Assertion.prototype.eq = Assertion.prototype.equal;
+    Assertion.prototype.eql = function (v) { return this.assert(deepEqual(this.actual, v), show(this.actual) + " to deeply equal " + show(v)); };
+    Assertion.prototype.include = function (v) {
+        var a = this.actual, found = false;
+        if (typeof a === "string") found = a.indexOf(v) !== -1;
+        else if (Array.isArray(a)) found = a.some(function (item) { return deepEqual(item, v); });
+        else if (a && typeof a === "object" && v && typeof v === "object") {
+            found = Object.keys(v).every(function (k) { return deepEqual(a[k], v[k]); });
+        }
+        return this.assert(found, show(a) + " to include " + show(v));
+    };
+    Assertion.prototype.contain = Assertion.prototype.include;
+    Assertion.prototype.includes = Assertion.prototype.include;
+    Assertion.prototype.contains = Assertion.prototype.include;
+    Assertion.prototype.a = function (type) {
+        var actual = Array.isArray(this.actual) ? "array" : this.actual === null ? "null" : typeof this.actual;
+        return this.assert(actual === String(type).toLowerCase(), show(this.actual) + " to be a " + type);
+    };
+    Assertion.prototype.an = Assertion.prototype.a;
+    Assertion.prototype.above = function (n) { return this.assert(this.actual > n, show(this.actual) + " to be above " + n); };
+    Assertion.prototype.below = function (n) { return this.assert(this.actual < n, show(this.actual) + " to be below " + n); };
+    Assertion.prototype.least = function (n) { return this.assert(this.actual >= n, show(this.actual) + " to be at least " + n); };
+    Assertion.prototype.most = function (n) { return this.assert(this.actual <= n, show(this.actual) + " to be at most " + n); };
+    Assertion.prototype.gt = Assertion.prototype.above;
+    Assertion.prototype.lt = Assertion.prototype.below;
+    Assertion.prototype.gte = Assertion.prototype.least;
+    Assertion.prototype.lte = Assertion.prototype.most;
+    Assertion.prototype.lengthOf = function (n) {
+        var length = this.actual === undefined || this.actual === null ? undefined : this.actual.length;
+        return this.assert(length === n, show(this.actual) + " to have length " + n);
+    };
+    Assertion.prototype.property = function (name, value) {
+        var has = this.actual !== undefined && this.actual !== null && Object.prototype.hasOwnProperty.call(Object(this.actual), name);
+        if (arguments.length > 1) {
+            return this.assert(has && deepEqual(this.actual[name], value), show(this.actual) + " to have property " + name + " of " + show(value));
+        }
+        return this.assert(has, show(this.actual) + " to have property " + name);
+    };
+    Assertion.prototype.match = function (re) { return this.assert(re.test(String(this.actual)), show(this.actual) + " to match " + re); };
+    Assertion.prototype.oneOf = function (list) {
+        var actual = this.actual;
+        return this.assert(list.some(function (item) { return deepEqual(item, actual); }), show(actual) + " to be one of " + show(list));
+    };
+    Assertion.prototype.status = function (code) {
+        var actual = this.actual && this.actual.code;
+        return this.assert(actual === code, "status " + actual + " to be " + code);
+    };
+    var flags = {
+        ok: function (a) { return !!a; },
+        true: function (a) { return a === true; },
+        false: function (a) { return a === false; },
+        null: function (a) { return a === null; },
+        undefined: function (a) { return a === undefined; },
+        exist: function (a) { return a !== null && a !== undefined; },
+        empty: function (a) {
+            if (a === null || a === undefined) return true;
+            if (typeof a === "string" || Array.isArray(a)) return a.length === 0;
+            return typeof a === "object" && Object.keys(a).length === 0;
+        }
+    };
+    Object.keys(flags).forEach(function (name) {
+        Object.defineProperty(Assertion.prototype, name, {
+            get: function () { return this.assert(flags[name](this.actual), show(this.actual) + " to be " + name); }
+        });
+    });
+
+    var rawRequest = JSON.parse(__request);
+    var request = {
+        method: rawRequest.method,
+        url: new Url(rawRequest.url),
+        auth: rawRequest.auth,
+        headers: headerList(rawRequest.headers || []),
+        body: {
+            raw: rawRequest.body,
+            update: function (value) { this.raw = text(value); },
+            toString: function () { return this.raw; }
+        }
+    };
+
+    var rawResponse = JSON.parse(__response);
+    var response;
+    if (rawResponse) {
+        var responseHeaders = [];
+        Object.keys(rawResponse.headers || {}).forEach(function (key) {
+            responseHeaders.push({ key: key, value: rawResponse.headers[key].join(", ") });
+        });
+        response = {
+            code: rawResponse.code,
+            status: rawResponse.status,
+            responseTime: rawResponse.responseTime,
+            headers: headerList(responseHeaders),
+            text: function () { return rawResponse.body; },
+            json: function () { return JSON.parse(rawResponse.body); }
+        };
+        response.to = {
+            have: {
+                status: function (code) { new Assertion(response).status(code); },
+                header: function (key) {
+                    if (!response.headers.has(key)) throw new Error("expected response to have header " + key);
+                }
+            },
+            be: {}
+        };
+        Object.defineProperty(response.to.be, "ok", {
+            get: function () { return new Assertion(response.code).assert(response.code >= 200 && response.code < 300, "status " + response.code + " to be 2xx"); }
+        });
+    }
+
+    var log = function (level) {
+        return function () {
+            var parts = [];
+            for (var i = 0; i < arguments.length; i++) parts.push(text(arguments[i]));
+            __host.log(level, parts.join(" "));
+        };
+    };
+    console = { log: log("log"), info: log("info"), warn: log("warn"), error: log("error"), debug: log("debug") };
+
+    return {
+        info: { eventName: __event, requestId: __requestId },
+        variables: scope("variables"),
+        environment: (function () { var s = scope("environment"); s.name = __environment; return s; })(),
+        globals: scope("globals"),
+        request: request,
+        response: response,
+        expect: function (actual) { return new Assertion(actual); },
+        test: function (name, fn) {
+            try {
+                fn();
+                __host.recordTest(String(name), true, "");
+            } catch (e) {
+                __host.recordTest(String(name), false, e && e.message ? e.message : String(e));
+            }
+        }
+    };
+})();
+
+function __exportRequest() {
+    var body = pm.request.body;
+    return JSON.stringify({
+        method: String(pm.request.method),
+        url: String(pm.request.url),
+        auth: pm.request.auth === undefined || pm.request.auth === null ? "" : String(pm.request.auth),
+        headers: pm.request.headers.members.map(function (h) { return { key: String(h.key), value: String(h.value) }; }),
+        body: body && typeof body === "object" ? String(body.raw === undefined ? "" : body.raw) : String(body === undefined || body === null ? "" : body)
+    });
+}
+`
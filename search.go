@@ -0,0 +1,283 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// searchIndexVersion is bumped whenever the FTS tables change shape so Init rebuilds them.
+	searchIndexVersion    = "1"
+	searchIndexVersionKey = "search_index_version"
+
+	defaultSearchLimit = 50
+
+	// Snippets wrap matched terms in these control characters so the frontend can highlight
+	// them without interpreting the snippet as markup.
+	searchHighlightStart = "\x02"
+	searchHighlightEnd   = "\x03"
+
+	SearchKindRequest  = "request"
+	SearchKindResponse = "response"
+)
+
+type SearchResult struct {
+	Kind           string  `json:"kind"`
+	RequestID      int     `json:"requestId"`
+	ResponseID     *int    `json:"responseId,omitempty"`
+	Name           *string `json:"name"`
+	Method         *string `json:"method"`
+	URL            *string `json:"url"`
+	CollectionID   *string `json:"collectionId"`
+	CollectionName *string `json:"collectionName"`
+	Snippet        string  `json:"snippet"`
+	// Rank orders results, lower being better. When responses are included it is relative to
+	// the best match of the same kind.
+	Rank float64 `json:"rank"`
+}
+
+// ensureSearchIndex fills the FTS tables from existing rows the first time they are created;
+// afterwards the triggers keep them in sync.
+func (s *RequestCRUDService) ensureSearchIndex() {
+	var version string
+	err := s.db.QueryRow("SELECT value FROM app_state WHERE key = ?", searchIndexVersionKey).Scan(&version)
+	if err == nil && version == searchIndexVersion {
+		return
+	}
+	if err != nil && err != sql.ErrNoRows {
+		fmt.Println("Failed to read search index version:", err)
+		return
+	}
+
+	for _, table := range []string{"requests_fts", "responses_fts"} {
+		if _, err := s.db.Exec(fmt.Sprintf("INSERT INTO %s (%s) VALUES ('rebuild')", table, table)); err != nil {
+			fmt.Printf("Failed to rebuild %s: %v\n", table, err)
+			return
+		}
+	}
+	if _, err := s.db.Exec(
+		`INSERT INTO app_state (key, value) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		searchIndexVersionKey,
+		searchIndexVersion,
+	); err != nil {
+		fmt.Println("Failed to store search index version:", err)
+	}
+}
+
+// FullTextSearch ranks requests, and response bodies when includeResponses is set, against a
+// query. Bare words match whole terms, word* matches a prefix and "quoted words" match a phrase.
+// Filters such as method:POST or status:5xx narrow the results as described in parseSearchQuery.
+func (s *RequestCRUDService) FullTextSearch(query string, includeResponses bool, limit int) ([]SearchResult, error) {
+	q, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	match := q.match(false)
+	if match == "" && len(q.conditions) == 0 {
+		return []SearchResult{}, nil
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	results, err := s.searchRequestIndex(q, match, limit)
+	if err != nil {
+		return nil, err
+	}
+	// Response bodies are only searched for text; filters alone describe requests.
+	if includeResponses && match != "" {
+		responses, err := s.searchResponseIndex(q, match, limit)
+		if err != nil {
+			return nil, err
+		}
+		results = mergeSearchResults(results, responses, limit)
+	}
+	return results, nil
+}
+
+func (s *RequestCRUDService) searchRequestIndex(q searchQuery, match string, limit int) ([]SearchResult, error) {
+	columns := "r.id, r.name, r.method, r.url, r.collection_id, c.name, snippet(requests_fts, -1, ?, ?, '…', 12), " + requestRank
+	args := []interface{}{searchHighlightStart, searchHighlightEnd}
+	if match == "" {
+		columns = "r.id, r.name, r.method, r.url, r.collection_id, c.name, '', 0.0"
+		args = nil
+	}
+	query, queryArgs := q.requestSQL(columns, match, limit)
+	rows, err := s.db.Query(query, append(args, queryArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search requests: %w", err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		result := SearchResult{Kind: SearchKindRequest}
+		var name, method, url, collectionID, collectionName sql.NullString
+		if err := rows.Scan(&result.RequestID, &name, &method, &url, &collectionID, &collectionName, &result.Snippet, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.Name = nullStringToPointer(name)
+		result.Method = nullStringToPointer(method)
+		result.URL = nullStringToPointer(url)
+		result.CollectionID = nullStringToPointer(collectionID)
+		result.CollectionName = nullStringToPointer(collectionName)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func (s *RequestCRUDService) searchResponseIndex(q searchQuery, match string, limit int) ([]SearchResult, error) {
+	conditions := append([]string{"responses_fts MATCH ?", "r.deleted_at IS NULL"}, q.conditions...)
+	args := append([]interface{}{searchHighlightStart, searchHighlightEnd, match}, q.args...)
+	rows, err := s.db.Query(
+		`SELECT resp.id, r.id, r.name, r.method, r.url, r.collection_id, c.name,
+		        snippet(responses_fts, -1, ?, ?, '…', 12),
+		        bm25(responses_fts, 1.0, 2.0) AS rank
+		 FROM responses_fts
+		 JOIN responses resp ON resp.id = responses_fts.rowid
+		 JOIN requests r ON r.id = resp.request_id`+searchFilterJoins+`
+		 WHERE `+strings.Join(conditions, " AND ")+`
+		 ORDER BY rank
+		 LIMIT ?`,
+		append(args, limit)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search responses: %w", err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		result := SearchResult{Kind: SearchKindResponse}
+		var responseID int
+		var name, method, url, collectionID, collectionName sql.NullString
+		if err := rows.Scan(&responseID, &result.RequestID, &name, &method, &url, &collectionID, &collectionName, &result.Snippet, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.ResponseID = &responseID
+		result.Name = nullStringToPointer(name)
+		result.Method = nullStringToPointer(method)
+		result.URL = nullStringToPointer(url)
+		result.CollectionID = nullStringToPointer(collectionID)
+		result.CollectionName = nullStringToPointer(collectionName)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// mergeSearchResults interleaves the request and response results. bm25 scores from different
+// FTS tables are not comparable, so each list is first scaled against its own best match.
+func mergeSearchResults(a []SearchResult, b []SearchResult, limit int) []SearchResult {
+	normalizeSearchRanks(a)
+	normalizeSearchRanks(b)
+
+	merged := make([]SearchResult, 0, len(a)+len(b))
+	i, j := 0, 0
+	for len(merged) < limit && (i < len(a) || j < len(b)) {
+		if j >= len(b) || (i < len(a) && a[i].Rank <= b[j].Rank) {
+			merged = append(merged, a[i])
+			i++
+		} else {
+			merged = append(merged, b[j])
+			j++
+		}
+	}
+	return merged
+}
+
+// normalizeSearchRanks rescales bm25 ranks, which are negative with lower being better, so the
+// best result of the list ranks -1 and the others between -1 and 0.
+func normalizeSearchRanks(results []SearchResult) {
+	best := 0.0
+	for _, result := range results {
+		best = min(best, result.Rank)
+	}
+	if best == 0 {
+		return
+	}
+	for i := range results {
+		results[i].Rank /= -best
+	}
+}
+
+// ftsMatchExpression turns search tokens into an FTS5 MATCH expression. Every term is quoted so
+// punctuation in URLs and headers cannot produce syntax errors; a trailing * on a word and
+// double-quoted phrases are kept. With prefixAll every bare word matches as a prefix, which
+// suits search-as-you-type.
+func ftsMatchExpression(tokens []searchToken, prefixAll bool) string {
+	var terms []string
+	for _, token := range tokens {
+		if token.phrase {
+			if words := searchWords(token.text); len(words) > 0 {
+				terms = append(terms, quoteFTSString(strings.Join(words, " ")))
+			}
+			continue
+		}
+		// A word such as api.example.com becomes a phrase of its parts.
+		words := searchWords(strings.TrimSuffix(token.text, "*"))
+		if len(words) == 0 {
+			continue
+		}
+		term := quoteFTSString(strings.Join(words, " "))
+		if prefixAll || strings.HasSuffix(token.text, "*") {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
+
+type searchToken struct {
+	text   string
+	phrase bool
+}
+
+func tokenizeSearchInput(input string) []searchToken {
+	var tokens []searchToken
+	for input = strings.TrimSpace(input); input != ""; input = strings.TrimSpace(input) {
+		if input[0] == '"' {
+			end := strings.IndexByte(input[1:], '"')
+			if end == -1 {
+				tokens = append(tokens, searchToken{text: input[1:], phrase: true})
+				break
+			}
+			tokens = append(tokens, searchToken{text: input[1 : end+1], phrase: true})
+			input = input[end+2:]
+			continue
+		}
+		end := bareTokenEnd(input)
+		tokens = append(tokens, searchToken{text: input[:end]})
+		input = input[end:]
+	}
+	return tokens
+}
+
+// bareTokenEnd finds the whitespace ending a bare token. Quotes inside the token, as in
+// in:"Billing Team", keep their spaces in the token.
+func bareTokenEnd(input string) int {
+	quoted := false
+	for i, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			return i
+		}
+	}
+	return len(input)
+}
+
+// searchWords splits text the way the unicode61 tokenizer does, on anything that is not a
+// letter or digit, so "api.example.com" searches for its three parts.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func quoteFTSString(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
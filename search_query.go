@@ -0,0 +1,251 @@
+package main
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"modernc.org/sqlite"
+)
+
+// searchFilterJoins makes the request's collection available as c and its latest response as
+// lr to the conditions built by parseSearchQuery.
+const searchFilterJoins = `
+	LEFT JOIN collections c ON c.id = r.collection_id
+	LEFT JOIN responses lr ON lr.id = (
+	    SELECT id FROM responses WHERE request_id = r.id
+	    ORDER BY COALESCE(created_at, CURRENT_TIMESTAMP) DESC, id DESC
+	    LIMIT 1
+	)`
+
+// requestRank orders request matches; the weights follow the requests_fts column order: name,
+// description, url, headers, body.
+const requestRank = "bm25(requests_fts, 10.0, 3.0, 5.0, 1.0, 1.0)"
+
+func init() {
+	// url_host lets host: filters compare the host of a stored URL.
+	sqlite.MustRegisterDeterministicScalarFunction("url_host", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		value, ok := args[0].(string)
+		if !ok {
+			return "", nil
+		}
+		return urlHost(value), nil
+	})
+}
+
+func urlHost(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// searchQuery is search input split into structured filters and the remaining free text.
+type searchQuery struct {
+	terms      []searchToken
+	conditions []string
+	args       []interface{}
+}
+
+// parseSearchQuery understands key:value filters alongside free text, for example
+// method:POST host:api.internal status:5xx in:"Billing" has:auth tag:smoke is:favorite. Values
+// may be quoted, method, status and tag take comma-separated lists, and a leading - negates a
+// filter. Anything that is not a known filter is searched as text.
+func parseSearchQuery(input string) (searchQuery, error) {
+	var q searchQuery
+	for _, token := range tokenizeSearchInput(input) {
+		if token.phrase {
+			q.terms = append(q.terms, token)
+			continue
+		}
+		key, value, found := strings.Cut(token.text, ":")
+		negate := strings.HasPrefix(key, "-")
+		key = strings.ToLower(strings.TrimPrefix(key, "-"))
+		if !found || !isSearchFilter(key) {
+			q.terms = append(q.terms, token)
+			continue
+		}
+		value = strings.TrimSpace(unquoteSearchValue(value))
+		if value == "" {
+			continue
+		}
+
+		condition, args, err := searchFilterCondition(key, value)
+		if err != nil {
+			return searchQuery{}, err
+		}
+		if negate {
+			// Requests without a response or collection have NULL here and still count as not matching.
+			condition = "NOT COALESCE((" + condition + "), 0)"
+		}
+		q.conditions = append(q.conditions, condition)
+		q.args = append(q.args, args...)
+	}
+	return q, nil
+}
+
+// match returns the FTS5 expression for the free text, or "" when there is none.
+func (q searchQuery) match(prefixAll bool) string {
+	return ftsMatchExpression(q.terms, prefixAll)
+}
+
+// requestSQL selects columns from the requests matching q, best match first. The columns may
+// use requests_fts only when match is not empty; without text, requests are ordered by name.
+func (q searchQuery) requestSQL(columns string, match string, limit int) (string, []interface{}) {
+	from := "requests r"
+	order := "r.name COLLATE NOCASE, r.id"
+	conditions := []string{"r.deleted_at IS NULL"}
+	var args []interface{}
+	if match != "" {
+		from = "requests_fts JOIN requests r ON r.id = requests_fts.rowid"
+		order = requestRank
+		conditions = append(conditions, "requests_fts MATCH ?")
+		args = append(args, match)
+	}
+	conditions = append(conditions, q.conditions...)
+	args = append(args, q.args...)
+
+	query := "SELECT " + columns + " FROM " + from + searchFilterJoins + " WHERE " + strings.Join(conditions, " AND ")
+	query += " ORDER BY " + order + " LIMIT ?"
+	return query, append(args, limit)
+}
+
+func isSearchFilter(key string) bool {
+	switch key {
+	case "method", "host", "status", "in", "has", "tag", "is":
+		return true
+	}
+	return false
+}
+
+func unquoteSearchValue(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		value = strings.TrimPrefix(value, `"`)
+		value = strings.TrimSuffix(value, `"`)
+	}
+	return value
+}
+
+func searchFilterCondition(key string, value string) (string, []interface{}, error) {
+	switch key {
+	case "method":
+		var placeholders []string
+		var args []interface{}
+		for _, method := range splitSearchList(value) {
+			placeholders = append(placeholders, "?")
+			args = append(args, strings.ToUpper(method))
+		}
+		if len(placeholders) == 0 {
+			return "", nil, fmt.Errorf("method filter %q has no values", value)
+		}
+		return "UPPER(r.method) IN (" + strings.Join(placeholders, ", ") + ")", args, nil
+
+	case "host":
+		// The latest response records the host after variables were resolved, which catches
+		// requests whose URL starts with a placeholder.
+		pattern := "%" + escapeLikePattern(strings.ToLower(value)) + "%"
+		return `(url_host(r.url) LIKE ? ESCAPE '\' OR lr.host LIKE ? ESCAPE '\')`, []interface{}{pattern, pattern}, nil
+
+	case "status":
+		return statusFilterCondition(value)
+
+	case "in":
+		// A collection matches by id or case-insensitive name and includes its sub-collections.
+		return `r.collection_id IN (
+			WITH RECURSIVE scoped(id) AS (
+			    SELECT id FROM collections WHERE (id = ? OR LOWER(name) = LOWER(?)) AND deleted_at IS NULL
+			    UNION SELECT collections.id FROM collections JOIN scoped ON collections.parent_collection = scoped.id
+			)
+			SELECT id FROM scoped
+		)`, []interface{}{value, value}, nil
+
+	case "has":
+		switch strings.ToLower(value) {
+		case "auth":
+			return `(COALESCE(r.auth, '') NOT IN ('', 'null', '` + authNone + `') OR LOWER(COALESCE(r.headers, '')) LIKE '%"authorization"%')`, nil, nil
+		case "body":
+			return "COALESCE(r.body, '') != ''", nil, nil
+		case "headers":
+			return "COALESCE(r.headers, '') NOT IN ('', '[]', '{}', 'null')", nil, nil
+		case "description":
+			return "COALESCE(r.description, '') != ''", nil, nil
+		case "response":
+			return "lr.id IS NOT NULL", nil, nil
+		case "script":
+			return `EXISTS (
+				SELECT 1 FROM request_scripts rs
+				WHERE rs.request_id = r.id AND (COALESCE(rs.pre_request, '') != '' OR COALESCE(rs.post_response, '') != '')
+			)`, nil, nil
+		}
+		return "", nil, fmt.Errorf("unknown has: filter %q, expected auth, body, headers, description, response or script", value)
+
+	case "tag":
+		// Several tags match requests carrying any of them.
+		var alternatives []string
+		var args []interface{}
+		for _, tag := range splitSearchList(value) {
+			alternatives = append(alternatives, taggedRequestCondition)
+			args = append(args, tag, tag)
+		}
+		if len(alternatives) == 0 {
+			return "", nil, fmt.Errorf("tag filter %q has no values", value)
+		}
+		return "(" + strings.Join(alternatives, " OR ") + ")", args, nil
+
+	case "is":
+		if strings.EqualFold(value, "favorite") {
+			return "r.favorite = 1", nil, nil
+		}
+		return "", nil, fmt.Errorf("unknown is: filter %q, expected favorite", value)
+	}
+	return "", nil, fmt.Errorf("unknown search filter %q", key)
+}
+
+// statusFilterCondition matches the latest response against codes such as 404, classes such
+// as 5xx, or none for requests that were never sent.
+func statusFilterCondition(value string) (string, []interface{}, error) {
+	var alternatives []string
+	var args []interface{}
+	for _, status := range splitSearchList(strings.ToLower(value)) {
+		switch {
+		case status == "none":
+			alternatives = append(alternatives, "lr.id IS NULL")
+		case len(status) == 3 && strings.HasSuffix(status, "xx") && status[0] >= '1' && status[0] <= '5':
+			low := int(status[0]-'0') * 100
+			alternatives = append(alternatives, "(lr.status_code >= ? AND lr.status_code < ?)")
+			args = append(args, low, low+100)
+		default:
+			code, err := strconv.Atoi(status)
+			if err != nil || code < 100 || code > 599 {
+				return "", nil, fmt.Errorf("invalid status filter %q, expected a code such as 404, a class such as 5xx, or none", status)
+			}
+			alternatives = append(alternatives, "lr.status_code = ?")
+			args = append(args, code)
+		}
+	}
+	if len(alternatives) == 0 {
+		return "", nil, fmt.Errorf("status filter %q has no values", value)
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args, nil
+}
+
+func splitSearchList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"database/sql"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		terms      []string
+		conditions int
+		args       []interface{}
+		negated    bool
+		err        string
+	}{
+		{name: "free text", input: "create invoice", terms: []string{"create", "invoice"}},
+		{name: "unknown key is text", input: "foo:bar", terms: []string{"foo:bar"}},
+		{name: "method list", input: "method:post,get", conditions: 1, args: []interface{}{"POST", "GET"}},
+		{name: "filter and text", input: "invoice method:GET", terms: []string{"invoice"}, conditions: 1, args: []interface{}{"GET"}},
+		{name: "quoted in", input: `in:"Billing Team"`, conditions: 1, args: []interface{}{"Billing Team", "Billing Team"}},
+		{name: "quoted in with text", input: `in:"Billing Team" refund`, terms: []string{"refund"}, conditions: 1, args: []interface{}{"Billing Team", "Billing Team"}},
+		{name: "status class", input: "status:5xx", conditions: 1, args: []interface{}{500, 600}},
+		{name: "status code and class", input: "status:404,2xx", conditions: 1, args: []interface{}{404, 200, 300}},
+		{name: "status none", input: "status:none", conditions: 1},
+		{name: "negated status", input: "-status:5xx", conditions: 1, args: []interface{}{500, 600}, negated: true},
+		{name: "negated in", input: "-in:billing", conditions: 1, args: []interface{}{"billing", "billing"}, negated: true},
+		{name: "empty value is ignored", input: "method: in:"},
+		{name: "empty quoted value is ignored", input: `in:""`},
+		{name: "empty method list", input: "method:,", err: "has no values"},
+		{name: "empty status list", input: "status:,", err: "has no values"},
+		{name: "empty tag list", input: "tag:, ,", err: "has no values"},
+		{name: "unknown status class", input: "status:6xx", err: "invalid status filter"},
+		{name: "status is not a number", input: "status:ok", err: "invalid status filter"},
+		{name: "unknown has", input: "has:nope", err: "unknown has: filter"},
+		{name: "unknown is", input: "is:pinned", err: "unknown is: filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := parseSearchQuery(tt.input)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("parseSearchQuery(%q) error = %v, want %q", tt.input, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSearchQuery(%q) error = %v", tt.input, err)
+			}
+
+			var terms []string
+			for _, term := range q.terms {
+				terms = append(terms, term.text)
+			}
+			if !reflect.DeepEqual(terms, tt.terms) {
+				t.Errorf("terms = %q, want %q", terms, tt.terms)
+			}
+			if len(q.conditions) != tt.conditions {
+				t.Fatalf("conditions = %q, want %d", q.conditions, tt.conditions)
+			}
+			if !reflect.DeepEqual(q.args, tt.args) {
+				t.Errorf("args = %v, want %v", q.args, tt.args)
+			}
+			if tt.conditions > 0 && strings.HasPrefix(q.conditions[0], "NOT ") != tt.negated {
+				t.Errorf("condition %q negated = %v, want %v", q.conditions[0], !tt.negated, tt.negated)
+			}
+		})
+	}
+}
+
+// TestSearchFilterRows runs the filters against rows, including requests without a collection
+// or a response, whose joins are NULL.
+func TestSearchFilterRows(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	for _, statement := range []string{
+		`CREATE TABLE collections (id TEXT PRIMARY KEY, name TEXT, parent_collection TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE requests (id INTEGER PRIMARY KEY, collection_id TEXT, name TEXT, description TEXT, method TEXT, url TEXT,
+			headers TEXT, body TEXT, auth TEXT, favorite INTEGER NOT NULL DEFAULT 0, deleted_at DATETIME)`,
+		`CREATE TABLE responses (id INTEGER PRIMARY KEY, request_id INTEGER, status_code INTEGER, host TEXT, created_at DATETIME)`,
+		`INSERT INTO collections (id, name, parent_collection) VALUES ('billing', 'Billing', NULL), ('invoices', 'Invoices', 'billing'), ('team', 'Billing Team', NULL)`,
+		`INSERT INTO requests (id, collection_id, name, method, url, auth) VALUES
+			(1, 'invoices', 'Create invoice', 'POST', 'https://api.internal/invoices', 'Bearer x'),
+			(2, NULL, 'Health', 'get', '{{base}}/health', NULL),
+			(3, 'team', 'Team', 'GET', 'http://other.host/team', 'noauth'),
+			(4, 'billing', 'Deleted', 'GET', 'https://api.internal/old', NULL)`,
+		`UPDATE requests SET deleted_at = CURRENT_TIMESTAMP WHERE id = 4`,
+		`INSERT INTO responses (id, request_id, status_code, host, created_at) VALUES
+			(1, 1, 200, 'api.internal', '2024-01-01 00:00:00'),
+			(2, 1, 503, 'api.internal', '2024-01-02 00:00:00'),
+			(3, 3, 201, 'other.host', '2024-01-01 00:00:00'),
+			(4, 2, 404, 'api.internal', '2024-01-01 00:00:00')`,
+		`UPDATE responses SET request_id = NULL WHERE id = 4`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+
+	tests := []struct {
+		input string
+		want  []int
+	}{
+		{"method:post", []int{1}},
+		{"method:GET,post", []int{1, 2, 3}},
+		{"-method:get", []int{1}},
+		{"status:5xx", []int{1}},
+		{"status:2xx", []int{3}},
+		{"status:200", nil},
+		{"status:none", []int{2}},
+		{"-status:5xx", []int{2, 3}},
+		{"-status:none", []int{1, 3}},
+		{"in:billing", []int{1}},
+		{`in:"Billing Team"`, []int{3}},
+		{"-in:billing", []int{2, 3}},
+		{"-in:invoices", []int{2, 3}},
+		{"host:api.internal", []int{1}},
+		{"-host:api.internal", []int{2, 3}},
+		{"has:auth", []int{1}},
+		{"-has:auth", []int{2, 3}},
+		{"has:response", []int{1, 3}},
+		{"-has:response", []int{2}},
+		{"in:billing status:5xx method:post", []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parseSearchQuery(tt.input)
+			if err != nil {
+				t.Fatalf("parseSearchQuery(%q) error = %v", tt.input, err)
+			}
+			query, args := q.requestSQL("r.id", "", 10)
+			rows, err := db.Query(query, args...)
+			if err != nil {
+				t.Fatalf("query for %q failed: %v", tt.input, err)
+			}
+			defer rows.Close()
+
+			var got []int
+			for rows.Next() {
+				var id int
+				if err := rows.Scan(&id); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, id)
+			}
+			sort.Ints(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q matched %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	secretValuePrefix = "secret:v1:"
+	secretMask        = "••••••••"
+	secretKeyFile     = "./data/secret.key"
+	secretKDFFile     = "./data/secret.kdf"
+	secretKeySize     = 32
+	secretCheckValue  = "curlew-secrets"
+
+	SecretModeKeyFile    = "keyfile"
+	SecretModePassphrase = "passphrase"
+)
+
+type SecretsStatus struct {
+	Mode     string `json:"mode"`
+	Unlocked bool   `json:"unlocked"`
+}
+
+// secretKDFParams is persisted in secretKDFFile when secrets are protected by a passphrase.
+// Check holds a known value encrypted under the derived key so a wrong passphrase is rejected
+// before it is used to decrypt anything.
+type secretKDFParams struct {
+	Salt  string `json:"salt"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Check string `json:"check"`
+}
+
+func isSecretValue(value string) bool {
+	return strings.HasPrefix(value, secretValuePrefix)
+}
+
+func hasSecretValues(content string) bool {
+	for _, value := range parseDotenv(content).variables() {
+		if isSecretValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func encryptSecret(key []byte, plaintext string) (string, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return secretValuePrefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key []byte, value string) (string, error) {
+	if !isSecretValue(value) {
+		return value, nil
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, secretValuePrefix))
+	if err != nil {
+		return "", fmt.Errorf("malformed secret value: %w", err)
+	}
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("malformed secret value")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret, the key may have changed")
+	}
+	return string(plaintext), nil
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func loadOrCreateSecretKeyFile() ([]byte, error) {
+	raw, err := os.ReadFile(secretKeyFile)
+	if err == nil {
+		key, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+		if decodeErr != nil || len(key) != secretKeySize {
+			return nil, fmt.Errorf("secret key file %s is corrupt", secretKeyFile)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read secret key file: %w", err)
+	}
+
+	key := make([]byte, secretKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	if err := writeSecretKeyFile(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func writeSecretKeyFile(key []byte) error {
+	if err := os.WriteFile(secretKeyFile, encodeSecretKey(key), 0o600); err != nil {
+		return fmt.Errorf("failed to write secret key file: %w", err)
+	}
+	return nil
+}
+
+func encodeSecretKey(key []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(key))
+}
+
+func loadSecretKDFParams() (*secretKDFParams, error) {
+	raw, err := os.ReadFile(secretKDFFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret passphrase settings: %w", err)
+	}
+	var params secretKDFParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("secret passphrase settings are corrupt: %w", err)
+	}
+	return &params, nil
+}
+
+func deriveSecretKey(passphrase string, params *secretKDFParams) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("secret passphrase salt is corrupt: %w", err)
+	}
+	return scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, secretKeySize)
+}
+
+func newSecretKDFParams(passphrase string) (*secretKDFParams, []byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	params := &secretKDFParams{
+		Salt: base64.StdEncoding.EncodeToString(salt),
+		N:    1 << 15,
+		R:    8,
+		P:    1,
+	}
+	key, err := deriveSecretKey(passphrase, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	check, err := encryptSecret(key, secretCheckValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.Check = check
+	return params, key, nil
+}
+
+func verifySecretKey(key []byte, params *secretKDFParams) bool {
+	value, err := decryptSecret(key, params.Check)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(value), []byte(secretCheckValue)) == 1
+}
+
+// reencryptSecrets decrypts the secret values in an environment file's content with oldKey and
+// encrypts them again with newKey. changed is false when the file holds no secrets.
+func reencryptSecrets(content string, oldKey []byte, newKey []byte) (string, bool, error) {
+	changed := false
+	for key, value := range parseDotenv(content).variables() {
+		if !isSecretValue(value) {
+			continue
+		}
+		plaintext, err := decryptSecret(oldKey, value)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to decrypt %s: %w", key, err)
+		}
+		encrypted, err := encryptSecret(newKey, plaintext)
+		if err != nil {
+			return "", false, err
+		}
+		content = setEnvFileValue(content, key, encrypted)
+		changed = true
+	}
+	return content, changed, nil
+}
+
+// writeSecretTempFile writes content next to the data directory's files so it can later be
+// renamed over target in one step.
+func writeSecretTempFile(content []byte, perm os.FileMode) (string, error) {
+	f, err := os.CreateTemp("./data", ".rekey-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	_, writeErr := f.Write(content)
+	closeErr := f.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Chmod(f.Name(), perm)
+	}
+	if writeErr != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write temporary file: %w", writeErr)
+	}
+	return f.Name(), nil
+}
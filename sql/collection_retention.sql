@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS collection_retention (
+    collection_id TEXT PRIMARY KEY,
+    max_entries INTEGER,
+    max_age_days INTEGER,
+    FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS collection_scripts (
+    collection_id TEXT PRIMARY KEY,
+    pre_request TEXT,
+    post_response TEXT,
+    FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS collection_variables (
+    collection_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    sort_order INTEGER,
+    PRIMARY KEY (collection_id, key),
+    FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE
+);
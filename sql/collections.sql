@@ -7,5 +7,12 @@ CREATE TABLE IF NOT EXISTS collections (
     version_minor INTEGER,
     version_patch INTEGER,
     version_identifier TEXT,
-    parent_collection TEXT
+    parent_collection TEXT,
+    headers TEXT,
+    auth TEXT,
+    favorite INTEGER NOT NULL DEFAULT 0,
+    sort_order INTEGER,
+    deleted_at DATETIME,
+    deleted_with TEXT,
+    FOREIGN KEY (parent_collection) REFERENCES collections (id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
 );
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS cookies (
+    environment TEXT NOT NULL DEFAULT '',
+    domain TEXT NOT NULL,
+    path TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT,
+    expires INTEGER,
+    secure INTEGER NOT NULL DEFAULT 0,
+    http_only INTEGER NOT NULL DEFAULT 0,
+    same_site TEXT,
+    host_only INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (environment, domain, path, name)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS item_tags (
+    tag TEXT NOT NULL COLLATE NOCASE,
+    request_id INTEGER,
+    collection_id TEXT,
+    CHECK ((request_id IS NULL) != (collection_id IS NULL)),
+    FOREIGN KEY (tag) REFERENCES tags (name) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (request_id) REFERENCES requests (id) ON DELETE CASCADE,
+    FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_item_tags_request ON item_tags (tag, request_id) WHERE request_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_item_tags_collection ON item_tags (tag, collection_id) WHERE collection_id IS NOT NULL;
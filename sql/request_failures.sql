@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS request_failures (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id INTEGER NOT NULL,
+    method TEXT,
+    url TEXT,
+    host TEXT,
+    environment TEXT,
+    error TEXT NOT NULL,
+    request_snapshot TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (request_id) REFERENCES requests (id) ON DELETE CASCADE
+);
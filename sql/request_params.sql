@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS request_params (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    description TEXT,
+    sort_order INTEGER,
+    FOREIGN KEY (request_id) REFERENCES requests (id) ON DELETE CASCADE
+);
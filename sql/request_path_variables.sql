@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS request_path_variables (
+    request_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT,
+    description TEXT,
+    PRIMARY KEY (request_id, key),
+    FOREIGN KEY (request_id) REFERENCES requests (id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS request_scripts (
+    request_id INTEGER PRIMARY KEY,
+    pre_request TEXT,
+    post_response TEXT,
+    FOREIGN KEY (request_id) REFERENCES requests (id) ON DELETE CASCADE
+);
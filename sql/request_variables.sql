@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS request_variables (
+    request_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    sort_order INTEGER,
+    PRIMARY KEY (request_id, key),
+    FOREIGN KEY (request_id) REFERENCES requests (id) ON DELETE CASCADE
+);
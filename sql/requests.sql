@@ -11,5 +11,8 @@ CREATE TABLE IF NOT EXISTS requests (
     auth TEXT,
     body_format TEXT,
     sort_order INTEGER,
-    FOREIGN KEY (collection_id) REFERENCES collections (id)
+    favorite INTEGER NOT NULL DEFAULT 0,
+    deleted_at DATETIME,
+    deleted_with TEXT,
+    FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE
 );
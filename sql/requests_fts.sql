@@ -0,0 +1,27 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS requests_fts USING fts5 (
+    name,
+    description,
+    url,
+    headers,
+    body,
+    content = 'requests',
+    content_rowid = 'id',
+    prefix = '2 3'
+);
+
+CREATE TRIGGER IF NOT EXISTS requests_fts_insert AFTER INSERT ON requests BEGIN
+    INSERT INTO requests_fts (rowid, name, description, url, headers, body)
+    VALUES (new.id, new.name, new.description, new.url, new.headers, new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS requests_fts_delete AFTER DELETE ON requests BEGIN
+    INSERT INTO requests_fts (requests_fts, rowid, name, description, url, headers, body)
+    VALUES ('delete', old.id, old.name, old.description, old.url, old.headers, old.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS requests_fts_update AFTER UPDATE OF name, description, url, headers, body ON requests BEGIN
+    INSERT INTO requests_fts (requests_fts, rowid, name, description, url, headers, body)
+    VALUES ('delete', old.id, old.name, old.description, old.url, old.headers, old.body);
+    INSERT INTO requests_fts (rowid, name, description, url, headers, body)
+    VALUES (new.id, new.name, new.description, new.url, new.headers, new.body);
+END;
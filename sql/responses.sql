@@ -5,5 +5,14 @@ CREATE TABLE IF NOT EXISTS responses (
     body TEXT,
     runtime_ms INTEGER,
     request_id INTEGER,
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    pinned INTEGER NOT NULL DEFAULT 0,
+    request_snapshot TEXT,
+    method TEXT,
+    url TEXT,
+    host TEXT,
+    environment TEXT,
+    FOREIGN KEY (request_id) REFERENCES requests (id) ON DELETE CASCADE
 );
+
+CREATE INDEX IF NOT EXISTS idx_responses_request_id ON responses (request_id);
@@ -0,0 +1,20 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS responses_fts USING fts5 (
+    headers,
+    body,
+    content = 'responses',
+    content_rowid = 'id',
+    prefix = '2 3'
+);
+
+CREATE TRIGGER IF NOT EXISTS responses_fts_insert AFTER INSERT ON responses BEGIN
+    INSERT INTO responses_fts (rowid, headers, body) VALUES (new.id, new.headers, new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS responses_fts_delete AFTER DELETE ON responses BEGIN
+    INSERT INTO responses_fts (responses_fts, rowid, headers, body) VALUES ('delete', old.id, old.headers, old.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS responses_fts_update AFTER UPDATE OF headers, body ON responses BEGIN
+    INSERT INTO responses_fts (responses_fts, rowid, headers, body) VALUES ('delete', old.id, old.headers, old.body);
+    INSERT INTO responses_fts (rowid, headers, body) VALUES (new.id, new.headers, new.body);
+END;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS tags (
+    name TEXT PRIMARY KEY COLLATE NOCASE,
+    color TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Tag is a label shared by requests and collections. A request also carries the tags of the
+// collections it is in when listing or searching by tag.
+type Tag struct {
+	Name            string  `json:"name"`
+	Color           *string `json:"color"`
+	RequestCount    int     `json:"requestCount"`
+	CollectionCount int     `json:"collectionCount"`
+}
+
+type Favorites struct {
+	Requests    []Request    `json:"requests"`
+	Collections []Collection `json:"collections"`
+}
+
+// taggedRequestCondition matches requests, aliased r, that carry a tag directly or through
+// one of their collections. It takes the tag name twice.
+const taggedRequestCondition = `(
+	r.id IN (SELECT request_id FROM item_tags WHERE tag = ? AND request_id IS NOT NULL)
+	OR r.collection_id IN (
+	    WITH RECURSIVE tagged(id) AS (
+	        SELECT collection_id FROM item_tags WHERE tag = ? AND collection_id IS NOT NULL
+	        UNION SELECT collections.id FROM collections JOIN tagged ON collections.parent_collection = tagged.id
+	    )
+	    SELECT id FROM tagged
+	)
+)`
+
+func (s *RequestCRUDService) ensureFavoriteColumns() {
+	if s.db == nil {
+		return
+	}
+	ensureColumn(s.db, "requests", "favorite", "INTEGER NOT NULL DEFAULT 0")
+	ensureColumn(s.db, "collections", "favorite", "INTEGER NOT NULL DEFAULT 0")
+}
+
+func (s *RequestCRUDService) GetTags() ([]Tag, error) {
+	rows, err := s.db.Query(
+		`SELECT t.name, t.color,
+		        (SELECT COUNT(*) FROM item_tags WHERE tag = t.name AND request_id IS NOT NULL),
+		        (SELECT COUNT(*) FROM item_tags WHERE tag = t.name AND collection_id IS NOT NULL)
+		 FROM tags t
+		 ORDER BY t.name COLLATE NOCASE`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var tag Tag
+		var color sql.NullString
+		if err := rows.Scan(&tag.Name, &color, &tag.RequestCount, &tag.CollectionCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tag.Color = nullStringToPointer(color)
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func (s *RequestCRUDService) SetTagColor(name string, color string) error {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return err
+	}
+	if err := ensureTag(s.db, name); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("UPDATE tags SET color = ? WHERE name = ?", emptyStringToNullString(color), name); err != nil {
+		return fmt.Errorf("failed to set color of tag %q: %w", name, err)
+	}
+	return nil
+}
+
+// RenameTag renames a tag everywhere it is used. Renaming onto an existing tag merges the two.
+func (s *RequestCRUDService) RenameTag(oldName string, newName string) error {
+	oldName, err := normalizeTagName(oldName)
+	if err != nil {
+		return err
+	}
+	newName, err = normalizeTagName(newName)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start tag rename: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO tags (name, color) SELECT ?, color FROM tags WHERE name = ? ON CONFLICT(name) DO NOTHING", newName, oldName); err != nil {
+		return fmt.Errorf("failed to rename tag %q: %w", oldName, err)
+	}
+	// Case-only renames update the row in place; the two names compare equal.
+	if strings.EqualFold(oldName, newName) {
+		if _, err := tx.Exec("UPDATE tags SET name = ? WHERE name = ?", newName, oldName); err != nil {
+			return fmt.Errorf("failed to rename tag %q: %w", oldName, err)
+		}
+	}
+	if _, err := tx.Exec("UPDATE OR IGNORE item_tags SET tag = ? WHERE tag = ?", newName, oldName); err != nil {
+		return fmt.Errorf("failed to rename tag %q: %w", oldName, err)
+	}
+	if !strings.EqualFold(oldName, newName) {
+		for _, query := range []string{"DELETE FROM item_tags WHERE tag = ?", "DELETE FROM tags WHERE name = ?"} {
+			if _, err := tx.Exec(query, oldName); err != nil {
+				return fmt.Errorf("failed to rename tag %q: %w", oldName, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *RequestCRUDService) DeleteTag(name string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start tag delete: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{"DELETE FROM item_tags WHERE tag = ?", "DELETE FROM tags WHERE name = ?"} {
+		if _, err := tx.Exec(query, strings.TrimSpace(name)); err != nil {
+			return fmt.Errorf("failed to delete tag %q: %w", name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *RequestCRUDService) AddRequestTag(requestID int, tag string) error {
+	return s.addItemTag("request_id", requestID, tag)
+}
+
+func (s *RequestCRUDService) RemoveRequestTag(requestID int, tag string) error {
+	return s.removeItemTag("request_id", requestID, tag)
+}
+
+func (s *RequestCRUDService) AddCollectionTag(collectionID string, tag string) error {
+	return s.addItemTag("collection_id", collectionID, tag)
+}
+
+func (s *RequestCRUDService) RemoveCollectionTag(collectionID string, tag string) error {
+	return s.removeItemTag("collection_id", collectionID, tag)
+}
+
+// SetRequestTags replaces the tags of a request.
+func (s *RequestCRUDService) SetRequestTags(requestID int, tags []string) error {
+	return s.setItemTags("request_id", requestID, tags)
+}
+
+// SetCollectionTags replaces the tags of a collection.
+func (s *RequestCRUDService) SetCollectionTags(collectionID string, tags []string) error {
+	return s.setItemTags("collection_id", collectionID, tags)
+}
+
+func (s *RequestCRUDService) GetRequestTags(requestID int) []string {
+	return s.itemTags("request_id", requestID)
+}
+
+func (s *RequestCRUDService) GetCollectionTags(collectionID string) []string {
+	return s.itemTags("collection_id", collectionID)
+}
+
+// GetRequestsByTag lists the requests tagged directly or through one of their collections.
+func (s *RequestCRUDService) GetRequestsByTag(tag string) []Request {
+	return s.listRequests([]string{tag})
+}
+
+func (s *RequestCRUDService) SetRequestFavorite(requestID int, favorite bool) error {
+	if _, err := s.db.Exec("UPDATE requests SET favorite = ? WHERE id = ?", favorite, requestID); err != nil {
+		return fmt.Errorf("failed to update favorite for request %d: %w", requestID, err)
+	}
+	return nil
+}
+
+func (s *RequestCRUDService) SetCollectionFavorite(collectionID string, favorite bool) error {
+	if _, err := s.db.Exec("UPDATE collections SET favorite = ? WHERE id = ?", favorite, collectionID); err != nil {
+		return fmt.Errorf("failed to update favorite for collection %s: %w", collectionID, err)
+	}
+	return nil
+}
+
+func (s *RequestCRUDService) GetFavorites() Favorites {
+	favorites := Favorites{Requests: []Request{}, Collections: []Collection{}}
+	for _, request := range s.GetAllRequestsList(nil) {
+		if request.Favorite {
+			favorites.Requests = append(favorites.Requests, request)
+		}
+	}
+	for _, collection := range s.GetAllCollections() {
+		if collection.Favorite {
+			favorites.Collections = append(favorites.Collections, collection)
+		}
+	}
+	return favorites
+}
+
+func (s *RequestCRUDService) addItemTag(column string, id interface{}, tag string) error {
+	tag, err := normalizeTagName(tag)
+	if err != nil {
+		return err
+	}
+	if err := ensureTag(s.db, tag); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("INSERT OR IGNORE INTO item_tags (tag, "+column+") VALUES (?, ?)", tag, id); err != nil {
+		return fmt.Errorf("failed to add tag %q: %w", tag, err)
+	}
+	return nil
+}
+
+func (s *RequestCRUDService) removeItemTag(column string, id interface{}, tag string) error {
+	if _, err := s.db.Exec("DELETE FROM item_tags WHERE tag = ? AND "+column+" = ?", strings.TrimSpace(tag), id); err != nil {
+		return fmt.Errorf("failed to remove tag %q: %w", tag, err)
+	}
+	return nil
+}
+
+func (s *RequestCRUDService) setItemTags(column string, id interface{}, tags []string) error {
+	var names []string
+	for _, tag := range tags {
+		name, err := normalizeTagName(tag)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start tag update: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM item_tags WHERE "+column+" = ?", id); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+	for _, name := range names {
+		if err := ensureTag(tx, name); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT OR IGNORE INTO item_tags (tag, "+column+") VALUES (?, ?)", name, id); err != nil {
+			return fmt.Errorf("failed to add tag %q: %w", name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *RequestCRUDService) itemTags(column string, id interface{}) []string {
+	tags := []string{}
+	rows, err := s.db.Query("SELECT tag FROM item_tags WHERE "+column+" = ? ORDER BY tag COLLATE NOCASE", id)
+	if err != nil {
+		fmt.Println("Failed to load tags:", err)
+		return tags
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err == nil {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// tagsByItem loads every direct tag assignment for one kind of item, keyed by item id.
+func (s *RequestCRUDService) tagsByItem(column string) map[string][]string {
+	tags := make(map[string][]string)
+	rows, err := s.db.Query("SELECT " + column + ", tag FROM item_tags WHERE " + column + " IS NOT NULL ORDER BY tag COLLATE NOCASE")
+	if err != nil {
+		fmt.Println("Failed to load tags:", err)
+		return tags
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, tag string
+		if err := rows.Scan(&id, &tag); err == nil {
+			tags[id] = append(tags[id], tag)
+		}
+	}
+	return tags
+}
+
+func ensureTag(db sqlExecer, name string) error {
+	if _, err := db.Exec("INSERT INTO tags (name) VALUES (?) ON CONFLICT(name) DO NOTHING", name); err != nil {
+		return fmt.Errorf("failed to create tag %q: %w", name, err)
+	}
+	return nil
+}
+
+// normalizeTagName trims a tag name. Commas are rejected because filters take tag lists.
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("tag name is required")
+	}
+	if strings.Contains(name, ",") {
+		return "", fmt.Errorf("tag name %q must not contain commas", name)
+	}
+	return name, nil
+}
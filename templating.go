@@ -0,0 +1,488 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// templateEvaluator resolves the expression inside a {{...}} placeholder. An expression is
+// either a variable name, a dynamic variable such as $uuid, or a function call whose
+// arguments are quoted strings, numbers, variables, dynamic variables or parenthesised calls:
+//
+//	{{hmacSHA256 signingKey (concat method ":" $timestamp)}}
+//
+// Arguments cannot contain braces, so JSON payloads for functions like jwt come from variables.
+type templateEvaluator struct {
+	lookup variableLookup
+	// now is fixed for the whole evaluation so every $timestamp in one request agrees.
+	now time.Time
+}
+
+type templateFunc func(e *templateEvaluator, args []string) (string, error)
+
+var templateFuncs map[string]templateFunc
+
+func init() {
+	templateFuncs = map[string]templateFunc{
+		"base64":           fnBase64,
+		"base64url":        fnBase64URL,
+		"base64Decode":     fnBase64Decode,
+		"urlEncode":        fnURLEncode,
+		"urlDecode":        fnURLDecode,
+		"sha256":           fnSHA256,
+		"hmacSHA256":       fnHMACSHA256,
+		"hmacSHA256Base64": fnHMACSHA256Base64,
+		"jwt":              fnJWT,
+		"now":              fnNow,
+		"dateAdd":          fnDateAdd,
+		"concat":           fnConcat,
+		"lower":            fnLower,
+		"upper":            fnUpper,
+	}
+}
+
+func newTemplateEvaluator(lookup variableLookup) *templateEvaluator {
+	return &templateEvaluator{lookup: lookup, now: time.Now()}
+}
+
+// errUnresolved marks an expression that names a variable or dynamic variable with no value.
+// Such placeholders are left in place, as plain unknown variables are.
+var errUnresolved = errors.New("unresolved")
+
+// evaluate resolves an expression. ok is false, and the placeholder is left untouched, unless
+// the expression is a known variable, a known dynamic variable or a call to a known function;
+// only a malformed call to a known function is an error. This keeps {{...}} text meant for
+// other tools, such as Handlebars bodies or Postman's own dynamic variables, intact.
+func (e *templateEvaluator) evaluate(expr string) (string, bool, error) {
+	tokens, err := tokenizeTemplate(expr)
+	if err != nil {
+		if fields := strings.Fields(expr); len(fields) > 0 && templateFuncs[fields[0]] != nil {
+			return "", false, fmt.Errorf("invalid expression {{%s}}: %w", expr, err)
+		}
+		return "", false, nil
+	}
+	if len(tokens) == 0 {
+		return "", false, nil
+	}
+
+	head := tokens[0]
+	switch {
+	case len(tokens) == 1 && head.kind == templateTokenDynamic:
+		value, err := e.dynamic(head.text)
+		if err == errUnresolved {
+			return "", false, nil
+		}
+		return value, err == nil, err
+	case head.kind != templateTokenIdent:
+		return "", false, nil
+	}
+
+	// A lone name is a variable first; it only counts as a call when no variable matches.
+	if len(tokens) == 1 {
+		value, ok, err := e.lookup(head.text)
+		if err != nil || ok {
+			return value, ok, err
+		}
+	}
+	if _, isFunc := templateFuncs[head.text]; !isFunc {
+		return "", false, nil
+	}
+
+	p := &templateParser{tokens: tokens}
+	value, err := e.evalCall(p, false)
+	if err == errUnresolved {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("{{%s}}: %w", expr, err)
+	}
+	if p.pos != len(p.tokens) {
+		return "", false, fmt.Errorf("{{%s}}: unexpected %q", expr, p.tokens[p.pos].text)
+	}
+	return value, true, nil
+}
+
+func (e *templateEvaluator) evalCall(p *templateParser, nested bool) (string, error) {
+	head := p.next()
+	if head.kind != templateTokenIdent {
+		if nested || p.pos < len(p.tokens) {
+			return "", fmt.Errorf("expected a function name, got %q", head.text)
+		}
+		return e.evalTerm(head, p)
+	}
+
+	fn, ok := templateFuncs[head.text]
+	if !ok {
+		if p.pos == len(p.tokens) || (nested && p.peek().kind == templateTokenClose) {
+			return e.evalTerm(head, p)
+		}
+		return "", fmt.Errorf("unknown function %s", head.text)
+	}
+
+	var args []string
+	for p.pos < len(p.tokens) && p.peek().kind != templateTokenClose {
+		arg, err := e.evalTerm(p.next(), p)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+	}
+	return fn(e, args)
+}
+
+func (e *templateEvaluator) evalTerm(tok templateToken, p *templateParser) (string, error) {
+	switch tok.kind {
+	case templateTokenString, templateTokenNumber:
+		return tok.text, nil
+	case templateTokenDynamic:
+		return e.dynamic(tok.text)
+	case templateTokenIdent:
+		value, ok, err := e.lookup(tok.text)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", errUnresolved
+		}
+		return value, nil
+	case templateTokenOpen:
+		value, err := e.evalCall(p, true)
+		if err != nil {
+			return "", err
+		}
+		if p.pos >= len(p.tokens) || p.next().kind != templateTokenClose {
+			return "", fmt.Errorf("missing closing parenthesis")
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("unexpected %q", tok.text)
+}
+
+func (e *templateEvaluator) dynamic(name string) (string, error) {
+	switch name {
+	case "$uuid", "$guid", "$randomUUID":
+		return uuid.New().String(), nil
+	case "$timestamp":
+		return strconv.FormatInt(e.now.Unix(), 10), nil
+	case "$isoTimestamp":
+		return e.now.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	case "$randomInt":
+		n, err := randomInt(1001)
+		return strconv.Itoa(n), err
+	case "$randomEmail":
+		suffix, err := randomString(10)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("user.%s@example.com", suffix), nil
+	}
+	return "", errUnresolved
+}
+
+type templateTokenKind int
+
+const (
+	templateTokenIdent templateTokenKind = iota
+	templateTokenDynamic
+	templateTokenString
+	templateTokenNumber
+	templateTokenOpen
+	templateTokenClose
+)
+
+type templateToken struct {
+	kind templateTokenKind
+	text string
+}
+
+type templateParser struct {
+	tokens []templateToken
+	pos    int
+}
+
+func (p *templateParser) next() templateToken {
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok
+}
+
+func (p *templateParser) peek() templateToken {
+	return p.tokens[p.pos]
+}
+
+func tokenizeTemplate(expr string) ([]templateToken, error) {
+	var tokens []templateToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, templateToken{kind: templateTokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, templateToken{kind: templateTokenClose, text: ")"})
+			i++
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, templateToken{kind: templateTokenString, text: b.String()})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && runes[j] != '(' && runes[j] != ')' && runes[j] != '"' && runes[j] != '\'' {
+				j++
+			}
+			word := string(runes[i:j])
+			kind := templateTokenIdent
+			if strings.HasPrefix(word, "$") {
+				kind = templateTokenDynamic
+			} else if _, err := strconv.ParseFloat(word, 64); err == nil || strings.HasPrefix(word, "+") || strings.HasPrefix(word, "-") {
+				kind = templateTokenNumber
+			}
+			tokens = append(tokens, templateToken{kind: kind, text: word})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+func requireArgs(name string, args []string, min int, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		if min == max {
+			return fmt.Errorf("%s expects %d argument(s), got %d", name, min, len(args))
+		}
+		return fmt.Errorf("%s expects between %d and %d arguments, got %d", name, min, max, len(args))
+	}
+	return nil
+}
+
+func fnBase64(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("base64", args, 1, 1); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString([]byte(args[0])), nil
+}
+
+func fnBase64URL(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("base64url", args, 1, 1); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(args[0])), nil
+}
+
+func fnBase64Decode(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("base64Decode", args, 1, 1); err != nil {
+		return "", err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(args[0])
+	if err != nil {
+		return "", fmt.Errorf("base64Decode: %w", err)
+	}
+	return string(decoded), nil
+}
+
+func fnURLEncode(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("urlEncode", args, 1, 1); err != nil {
+		return "", err
+	}
+	return url.QueryEscape(args[0]), nil
+}
+
+func fnURLDecode(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("urlDecode", args, 1, 1); err != nil {
+		return "", err
+	}
+	decoded, err := url.QueryUnescape(args[0])
+	if err != nil {
+		return "", fmt.Errorf("urlDecode: %w", err)
+	}
+	return decoded, nil
+}
+
+func fnSHA256(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("sha256", args, 1, 1); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(args[0]))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func fnHMACSHA256(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("hmacSHA256", args, 2, 2); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hmacSHA256([]byte(args[0]), []byte(args[1]))), nil
+}
+
+func fnHMACSHA256Base64(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("hmacSHA256Base64", args, 2, 2); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hmacSHA256([]byte(args[0]), []byte(args[1]))), nil
+}
+
+func hmacSHA256(key []byte, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// fnJWT signs an HS256 token: jwt key claims [expiresIn]. claims must be a JSON object; iat is
+// added when missing and exp is set when an expiry offset such as "1h" is given.
+func fnJWT(e *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("jwt", args, 2, 3); err != nil {
+		return "", err
+	}
+
+	claims := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(args[1]), &claims); err != nil {
+		return "", fmt.Errorf("jwt claims must be a JSON object: %w", err)
+	}
+	if _, ok := claims["iat"]; !ok {
+		claims["iat"] = e.now.Unix()
+	}
+	if len(args) == 3 {
+		offset, err := parseTemplateOffset(args[2])
+		if err != nil {
+			return "", err
+		}
+		claims["exp"] = e.now.Add(offset).Unix()
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode jwt claims: %w", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := hmacSHA256([]byte(args[0]), []byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// fnNow formats the evaluation time: now [format].
+func fnNow(e *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("now", args, 0, 1); err != nil {
+		return "", err
+	}
+	format := ""
+	if len(args) == 1 {
+		format = args[0]
+	}
+	return formatTemplateTime(e.now, format), nil
+}
+
+// fnDateAdd shifts the evaluation time: dateAdd offset [format], e.g. dateAdd "-7d" "unix".
+func fnDateAdd(e *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("dateAdd", args, 1, 2); err != nil {
+		return "", err
+	}
+	offset, err := parseTemplateOffset(args[0])
+	if err != nil {
+		return "", err
+	}
+	format := ""
+	if len(args) == 2 {
+		format = args[1]
+	}
+	return formatTemplateTime(e.now.Add(offset), format), nil
+}
+
+func fnConcat(_ *templateEvaluator, args []string) (string, error) {
+	return strings.Join(args, ""), nil
+}
+
+func fnLower(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("lower", args, 1, 1); err != nil {
+		return "", err
+	}
+	return strings.ToLower(args[0]), nil
+}
+
+func fnUpper(_ *templateEvaluator, args []string) (string, error) {
+	if err := requireArgs("upper", args, 1, 1); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(args[0]), nil
+}
+
+// parseTemplateOffset accepts Go durations plus d (days) and w (weeks), e.g. "90m", "-7d", "+2w".
+func parseTemplateOffset(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		amount, err := strconv.ParseFloat(value[:n-1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid offset %q", value)
+		}
+		unit := 24 * time.Hour
+		if value[n-1] == 'w' {
+			unit *= 7
+		}
+		return time.Duration(amount * float64(unit)), nil
+	}
+	d, err := time.ParseDuration(strings.TrimPrefix(value, "+"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	return d, nil
+}
+
+// formatTemplateTime renders t as "iso" (the default), "unix", "unixMs", "date" or a Go layout.
+func formatTemplateTime(t time.Time, format string) string {
+	switch format {
+	case "", "iso":
+		return t.UTC().Format("2006-01-02T15:04:05.000Z")
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixMs":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case "date":
+		return t.UTC().Format("2006-01-02")
+	}
+	return t.UTC().Format(format)
+}
+
+func randomInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
+
+func randomString(length int) (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, length)
+	for i := range b {
+		n, err := randomInt(len(alphabet))
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphabet[n]
+	}
+	return string(b), nil
+}
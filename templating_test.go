@@ -0,0 +1,136 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenizeTemplate(t *testing.T) {
+	tests := []struct {
+		expr  string
+		kinds []templateTokenKind
+		texts []string
+		err   string
+	}{
+		{expr: "token", kinds: []templateTokenKind{templateTokenIdent}, texts: []string{"token"}},
+		{expr: "  $timestamp  ", kinds: []templateTokenKind{templateTokenDynamic}, texts: []string{"$timestamp"}},
+		{
+			expr:  `hmacSHA256 key (concat method ":" $timestamp)`,
+			kinds: []templateTokenKind{templateTokenIdent, templateTokenIdent, templateTokenOpen, templateTokenIdent, templateTokenIdent, templateTokenString, templateTokenDynamic, templateTokenClose},
+			texts: []string{"hmacSHA256", "key", "(", "concat", "method", ":", "$timestamp", ")"},
+		},
+		{
+			expr:  `dateAdd -7d 'unix' 1.5`,
+			kinds: []templateTokenKind{templateTokenIdent, templateTokenNumber, templateTokenString, templateTokenNumber},
+			texts: []string{"dateAdd", "-7d", "unix", "1.5"},
+		},
+		{expr: `concat "a \"b\" c" ''`, kinds: []templateTokenKind{templateTokenIdent, templateTokenString, templateTokenString}, texts: []string{"concat", `a "b" c`, ""}},
+		{expr: `concat "it's"`, kinds: []templateTokenKind{templateTokenIdent, templateTokenString}, texts: []string{"concat", "it's"}},
+		{expr: ""},
+		{expr: `upper "abc`, err: "unterminated string"},
+		{expr: `upper 'abc\'`, err: "unterminated string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			tokens, err := tokenizeTemplate(tt.expr)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("tokenizeTemplate(%q) error = %v, want %q", tt.expr, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tokenizeTemplate(%q) error = %v", tt.expr, err)
+			}
+
+			var kinds []templateTokenKind
+			var texts []string
+			for _, tok := range tokens {
+				kinds = append(kinds, tok.kind)
+				texts = append(texts, tok.text)
+			}
+			if !reflect.DeepEqual(kinds, tt.kinds) || !reflect.DeepEqual(texts, tt.texts) {
+				t.Errorf("tokenizeTemplate(%q) = %v %q, want %v %q", tt.expr, kinds, texts, tt.kinds, tt.texts)
+			}
+		})
+	}
+}
+
+func TestTemplateEvaluate(t *testing.T) {
+	vars := map[string]string{
+		"user":   "user",
+		"pass":   "pass",
+		"method": "post",
+		"upper":  "shadowed",
+	}
+	e := &templateEvaluator{
+		lookup: func(name string) (string, bool, error) {
+			value, ok := vars[name]
+			return value, ok, nil
+		},
+		now: time.Unix(1700000000, 0),
+	}
+
+	tests := []struct {
+		expr string
+		want string
+		ok   bool
+		err  string
+	}{
+		{expr: "user", want: "user", ok: true},
+		{expr: " user ", want: "user", ok: true},
+		{expr: "upper", want: "shadowed", ok: true},
+		{expr: "$timestamp", want: "1700000000", ok: true},
+		{expr: "$isoTimestamp", want: "2023-11-14T22:13:20.000Z", ok: true},
+		{expr: "now", want: "2023-11-14T22:13:20.000Z", ok: true},
+		{expr: `dateAdd "-1d" "unix"`, want: "1699913600", ok: true},
+		{expr: `dateAdd +2w "date"`, want: "2023-11-28", ok: true},
+		{expr: `base64 (concat user ":" pass)`, want: "dXNlcjpwYXNz", ok: true},
+		{expr: `concat (upper method) "-" $timestamp`, want: "POST-1700000000", ok: true},
+		{expr: `sha256 "abc"`, want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", ok: true},
+		{expr: `urlEncode "a b&c"`, want: "a+b%26c", ok: true},
+		{expr: `lower 'MiXeD'`, want: "mixed", ok: true},
+
+		// Anything that is not a known variable, dynamic variable or function is left untouched.
+		{expr: "missing"},
+		{expr: "$randomThing"},
+		{expr: "#each items"},
+		{expr: `"quoted"`},
+		{expr: `"unterminated`},
+		{expr: "else if"},
+		{expr: "concat missing"},
+		{expr: "upper (lower missing)"},
+		{expr: "base64 $randomThing"},
+
+		// Malformed calls to known functions are errors.
+		{expr: `upper "a" "b"`, err: "upper"},
+		{expr: `upper "abc`, err: "unterminated string"},
+		{expr: `concat (nope "x")`, err: "unknown function nope"},
+		{expr: `concat (upper method`, err: "missing closing parenthesis"},
+		{expr: `concat ("a")`, err: "expected a function name"},
+		{expr: `upper method)`, err: "unexpected"},
+		{expr: `dateAdd "soon"`, err: "invalid offset"},
+		{expr: `base64Decode "%%%"`, err: "base64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, ok, err := e.evaluate(tt.expr)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("evaluate(%q) error = %v, want %q", tt.expr, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluate(%q) error = %v", tt.expr, err)
+			}
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("evaluate(%q) = %q, %v, want %q, %v", tt.expr, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	trashRetentionDaysKey     = "trash_retention_days"
+	defaultTrashRetentionDays = 30
+
+	TrashKindRequest    = "request"
+	TrashKindCollection = "collection"
+)
+
+// Deleted requests and collections keep their rows with deleted_at set. Items removed together
+// with a collection also record that collection in deleted_with, so the trash lists and restores
+// them as one entry.
+
+// TrashItem is an entry in the trash. RequestID is set for requests and CollectionID for
+// collections; Location names the collection the item was in, if it still exists.
+type TrashItem struct {
+	Kind         string    `json:"kind"`
+	RequestID    int       `json:"requestId,omitempty"`
+	CollectionID string    `json:"collectionId,omitempty"`
+	Name         string    `json:"name"`
+	Method       *string   `json:"method"`
+	URL          *string   `json:"url"`
+	Location     *string   `json:"location"`
+	RequestCount int       `json:"requestCount"`
+	DeletedAt    time.Time `json:"deletedAt"`
+}
+
+type TrashPurgeResult struct {
+	Requests    int `json:"requests"`
+	Collections int `json:"collections"`
+	Responses   int `json:"responses"`
+}
+
+type TrashService struct {
+	db *sql.DB
+}
+
+func (s *RequestCRUDService) ensureTrashColumns() {
+	if s.db == nil {
+		return
+	}
+	for _, table := range []string{"requests", "collections"} {
+		ensureColumn(s.db, table, "deleted_at", "DATETIME")
+		ensureColumn(s.db, table, "deleted_with", "TEXT")
+	}
+}
+
+// loadTrashRetentionDays reads how long deleted items are kept; zero keeps them until the
+// trash is emptied.
+func loadTrashRetentionDays(db *sql.DB) (int, error) {
+	if db == nil {
+		return defaultTrashRetentionDays, fmt.Errorf("database not initialized")
+	}
+
+	var raw string
+	err := db.QueryRow(`SELECT value FROM app_state WHERE key = ?`, trashRetentionDaysKey).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return defaultTrashRetentionDays, nil
+	}
+	if err != nil {
+		return defaultTrashRetentionDays, err
+	}
+
+	val, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || val < 0 {
+		return defaultTrashRetentionDays, fmt.Errorf("invalid value %q for %s", raw, trashRetentionDaysKey)
+	}
+	return val, nil
+}
+
+func (s *TrashService) GetTrash() ([]TrashItem, error) {
+	rows, err := s.db.Query(
+		`SELECT r.id, r.name, r.method, r.url, c.name, r.deleted_at
+		 FROM requests r
+		 LEFT JOIN collections c ON c.id = r.collection_id AND c.deleted_at IS NULL
+		 WHERE r.deleted_at IS NOT NULL AND r.deleted_with IS NULL`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deleted requests: %w", err)
+	}
+	defer rows.Close()
+
+	items := []TrashItem{}
+	for rows.Next() {
+		item := TrashItem{Kind: TrashKindRequest}
+		var name, method, url, location sql.NullString
+		if err := rows.Scan(&item.RequestID, &name, &method, &url, &location, &item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted request: %w", err)
+		}
+		item.Name = name.String
+		item.Method = nullStringToPointer(method)
+		item.URL = nullStringToPointer(url)
+		item.Location = nullStringToPointer(location)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(
+		`SELECT c.id, c.name, p.name, c.deleted_at,
+		        (SELECT COUNT(*) FROM requests WHERE deleted_with = c.id)
+		 FROM collections c
+		 LEFT JOIN collections p ON p.id = c.parent_collection AND p.deleted_at IS NULL
+		 WHERE c.deleted_at IS NOT NULL AND c.deleted_with IS NULL`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deleted collections: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := TrashItem{Kind: TrashKindCollection}
+		var location sql.NullString
+		if err := rows.Scan(&item.CollectionID, &item.Name, &location, &item.DeletedAt, &item.RequestCount); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted collection: %w", err)
+		}
+		item.Location = nullStringToPointer(location)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Most recently deleted first.
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// RestoreRequest puts a deleted request back at its sort position in its collection. A request
+// whose collection is gone or deleted is restored to the end of the uncategorized requests.
+func (s *TrashService) RestoreRequest(requestID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start restore: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		collectionID sql.NullString
+		sortOrder    sql.NullInt64
+		deletedAt    sql.NullTime
+		deletedWith  sql.NullString
+	)
+	err = tx.QueryRow("SELECT collection_id, sort_order, deleted_at, deleted_with FROM requests WHERE id = ?", requestID).
+		Scan(&collectionID, &sortOrder, &deletedAt, &deletedWith)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("request %d not found", requestID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load request %d: %w", requestID, err)
+	}
+	if !deletedAt.Valid {
+		return fmt.Errorf("request %d is not in the trash", requestID)
+	}
+	if deletedWith.Valid {
+		return fmt.Errorf("request %d was deleted with its collection; restore the collection instead", requestID)
+	}
+
+	if collectionID.Valid {
+		active, err := activeCollection(tx, collectionID.String)
+		if err != nil {
+			return err
+		}
+		if !active {
+			collectionID = sql.NullString{}
+			sortOrder = sql.NullInt64{}
+		}
+	}
+	var target interface{}
+	if collectionID.Valid {
+		target = collectionID.String
+	}
+
+	if sortOrder.Valid {
+		if _, err := tx.Exec(
+			"UPDATE requests SET sort_order = sort_order + 1 WHERE collection_id IS ? AND deleted_at IS NULL AND sort_order >= ?",
+			target,
+			sortOrder.Int64,
+		); err != nil {
+			return fmt.Errorf("failed to make room for request %d: %w", requestID, err)
+		}
+	} else {
+		if err := tx.QueryRow("SELECT COALESCE(MAX(sort_order) + 1, 0) FROM requests WHERE collection_id IS ? AND deleted_at IS NULL", target).Scan(&sortOrder); err != nil {
+			return fmt.Errorf("failed to determine sort order: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE requests SET collection_id = ?, sort_order = ?, deleted_at = NULL WHERE id = ?",
+		target,
+		sortOrder.Int64,
+		requestID,
+	); err != nil {
+		return fmt.Errorf("failed to restore request %d: %w", requestID, err)
+	}
+	return tx.Commit()
+}
+
+// RestoreCollection restores a deleted collection with everything deleted along with it. It
+// returns to its original parent and position when the parent still exists, and to the end of
+// the top level otherwise.
+func (s *TrashService) RestoreCollection(collectionID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start restore: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		parent      sql.NullString
+		sortOrder   sql.NullInt64
+		deletedAt   sql.NullTime
+		deletedWith sql.NullString
+	)
+	err = tx.QueryRow("SELECT parent_collection, sort_order, deleted_at, deleted_with FROM collections WHERE id = ?", collectionID).
+		Scan(&parent, &sortOrder, &deletedAt, &deletedWith)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("collection %s not found", collectionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load collection %s: %w", collectionID, err)
+	}
+	if !deletedAt.Valid {
+		return fmt.Errorf("collection %s is not in the trash", collectionID)
+	}
+	if deletedWith.Valid {
+		return fmt.Errorf("collection %s was deleted with its parent; restore the parent instead", collectionID)
+	}
+
+	if parent.Valid {
+		active, err := activeCollection(tx, parent.String)
+		if err != nil {
+			return err
+		}
+		if !active {
+			parent = sql.NullString{}
+			sortOrder = sql.NullInt64{}
+		}
+	}
+	var target interface{}
+	if parent.Valid {
+		target = parent.String
+	}
+
+	if sortOrder.Valid {
+		if _, err := tx.Exec(
+			"UPDATE collections SET sort_order = sort_order + 1 WHERE parent_collection IS ? AND deleted_at IS NULL AND sort_order >= ?",
+			target,
+			sortOrder.Int64,
+		); err != nil {
+			return fmt.Errorf("failed to make room for collection %s: %w", collectionID, err)
+		}
+	} else {
+		if err := tx.QueryRow("SELECT "+nextCollectionSortOrder, target, collectionID).Scan(&sortOrder); err != nil {
+			return fmt.Errorf("failed to determine sort order: %w", err)
+		}
+	}
+
+	for _, statement := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{"UPDATE collections SET parent_collection = ?, sort_order = ?, deleted_at = NULL WHERE id = ?", []interface{}{target, sortOrder.Int64, collectionID}},
+		{"UPDATE collections SET deleted_at = NULL, deleted_with = NULL WHERE deleted_with = ?", []interface{}{collectionID}},
+		{"UPDATE requests SET deleted_at = NULL, deleted_with = NULL WHERE deleted_with = ?", []interface{}{collectionID}},
+	} {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			return fmt.Errorf("failed to restore collection %s: %w", collectionID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *TrashService) PurgeRequest(requestID int) (TrashPurgeResult, error) {
+	return s.purge(func(tx *sql.Tx, result *TrashPurgeResult) error {
+		requests, responses, err := deleteRequestsWhere(tx, "id = ? AND deleted_at IS NOT NULL AND deleted_with IS NULL", requestID)
+		if err != nil {
+			return err
+		}
+		if requests == 0 {
+			return fmt.Errorf("request %d is not in the trash", requestID)
+		}
+		result.Requests += requests
+		result.Responses += responses
+		return nil
+	})
+}
+
+func (s *TrashService) PurgeCollection(collectionID string) (TrashPurgeResult, error) {
+	return s.purge(func(tx *sql.Tx, result *TrashPurgeResult) error {
+		return purgeCollection(tx, collectionID, result)
+	})
+}
+
+// EmptyTrash permanently deletes everything in the trash.
+func (s *TrashService) EmptyTrash() (TrashPurgeResult, error) {
+	return s.purgeDeletedBefore("")
+}
+
+// PurgeExpiredTrash permanently deletes items that have been in the trash longer than the
+// configured number of days.
+func (s *TrashService) PurgeExpiredTrash() (TrashPurgeResult, error) {
+	days, err := loadTrashRetentionDays(s.db)
+	if err != nil {
+		return TrashPurgeResult{}, err
+	}
+	if days == 0 {
+		return TrashPurgeResult{}, nil
+	}
+	return s.purgeDeletedBefore(fmt.Sprintf("-%d days", days))
+}
+
+// runTrashJanitor purges expired trash at startup and then periodically until ctx is done.
+func (s *TrashService) runTrashJanitor(ctx context.Context) {
+	ticker := time.NewTicker(retentionJanitorInterval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.PurgeExpiredTrash(); err != nil {
+			fmt.Println("Failed to purge trash:", err)
+		} else if result.Requests+result.Collections > 0 {
+			fmt.Printf("Purged %d requests and %d collections from the trash\n", result.Requests, result.Collections)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeDeletedBefore purges the trash entries deleted before now shifted by the SQLite date
+// modifier age, or all of them when age is empty.
+func (s *TrashService) purgeDeletedBefore(age string) (TrashPurgeResult, error) {
+	cutoff := "deleted_at IS NOT NULL AND deleted_with IS NULL"
+	var args []interface{}
+	if age != "" {
+		cutoff += " AND deleted_at < datetime('now', ?)"
+		args = append(args, age)
+	}
+
+	return s.purge(func(tx *sql.Tx, result *TrashPurgeResult) error {
+		rows, err := tx.Query("SELECT id FROM collections WHERE "+cutoff, args...)
+		if err != nil {
+			return fmt.Errorf("failed to list deleted collections: %w", err)
+		}
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+
+		for _, id := range ids {
+			if err := purgeCollection(tx, id, result); err != nil {
+				return err
+			}
+		}
+		requests, responses, err := deleteRequestsWhere(tx, cutoff, args...)
+		result.Requests += requests
+		result.Responses += responses
+		return err
+	})
+}
+
+func (s *TrashService) purge(fn func(tx *sql.Tx, result *TrashPurgeResult) error) (TrashPurgeResult, error) {
+	var result TrashPurgeResult
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to start purge: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx, &result); err != nil {
+		return TrashPurgeResult{}, err
+	}
+	// Journaled operations may refer to the purged rows, so they can no longer be undone.
+	if result.Requests+result.Collections > 0 {
+		if err := clearJournal(tx); err != nil {
+			return TrashPurgeResult{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return TrashPurgeResult{}, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return result, nil
+}
+
+// purgeCollection permanently deletes a collection in the trash and everything deleted with
+// it. Items deleted on their own beforehand stay in the trash, detached from the purged
+// collections, so they restore to the top level.
+func purgeCollection(tx *sql.Tx, collectionID string, result *TrashPurgeResult) error {
+	var deleted bool
+	if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM collections WHERE id = ? AND deleted_at IS NOT NULL AND deleted_with IS NULL)", collectionID).Scan(&deleted); err != nil {
+		return fmt.Errorf("failed to load collection %s: %w", collectionID, err)
+	}
+	if !deleted {
+		return fmt.Errorf("collection %s is not in the trash", collectionID)
+	}
+
+	scope := "SELECT id FROM collections WHERE id = ? OR deleted_with = ?"
+	requests, responses, err := deleteRequestsWhere(tx, "deleted_with = ?", collectionID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE requests SET collection_id = NULL WHERE collection_id IN ("+scope+")", collectionID, collectionID); err != nil {
+		return fmt.Errorf("failed to detach requests from collection %s: %w", collectionID, err)
+	}
+	if _, err := tx.Exec(
+		"UPDATE collections SET parent_collection = NULL WHERE parent_collection IN ("+scope+") AND id NOT IN ("+scope+")",
+		collectionID, collectionID, collectionID, collectionID,
+	); err != nil {
+		return fmt.Errorf("failed to detach collections from collection %s: %w", collectionID, err)
+	}
+	for _, table := range []string{"collection_variables", "collection_retention", "collection_scripts", "item_tags"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE collection_id IN ("+scope+")", collectionID, collectionID); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+	var collections int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM ("+scope+")", collectionID, collectionID).Scan(&collections); err != nil {
+		return fmt.Errorf("failed to count collections: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM collections WHERE id IN ("+scope+")", collectionID, collectionID); err != nil {
+		return fmt.Errorf("failed to delete collection %s: %w", collectionID, err)
+	}
+
+	result.Collections += collections
+	result.Requests += requests
+	result.Responses += responses
+	return nil
+}
+
+func activeCollection(tx *sql.Tx, collectionID string) (bool, error) {
+	var active bool
+	if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM collections WHERE id = ? AND deleted_at IS NULL)", collectionID).Scan(&active); err != nil {
+		return false, fmt.Errorf("failed to load collection %s: %w", collectionID, err)
+	}
+	return active, nil
+}
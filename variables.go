@@ -0,0 +1,39 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// variableLookup resolves a placeholder name. ok is false when the name is unknown,
+// in which case the placeholder is left in place.
+type variableLookup func(name string) (value string, ok bool, err error)
+
+func expandPlaceholders(text string, lookup variableLookup) (string, error) {
+	if text == "" || !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	var firstErr error
+	expanded := placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+		name := strings.TrimSpace(match[2 : len(match)-2])
+		value, ok, err := lookup(name)
+		if err != nil {
+			firstErr = err
+			return match
+		}
+		if !ok {
+			return match
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return expanded, nil
+}
@@ -0,0 +1,620 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	workspaceArchiveFormat  = "curlew-workspace"
+	workspaceArchiveVersion = 1
+	workspaceManifestName   = "manifest.json"
+	workspaceTablesDir      = "tables"
+	workspaceEnvsDir        = "environments"
+
+	WorkspaceImportMerge   = "merge"
+	WorkspaceImportReplace = "replace"
+)
+
+type WorkspaceService struct {
+	db     *sql.DB
+	envars *EnvarService
+}
+
+type WorkspaceManifest struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Tables     []string  `json:"tables"`
+	EnvFiles   []string  `json:"envFiles"`
+	// Secrets holds the key derivation settings for the export passphrase that the secret
+	// variables in the archive are encrypted with. It is nil when the archive has no secrets.
+	Secrets *secretKDFParams `json:"secrets,omitempty"`
+}
+
+type WorkspaceImportSummary struct {
+	Mode        string         `json:"mode"`
+	Imported    map[string]int `json:"imported"`
+	Skipped     map[string]int `json:"skipped"`
+	EnvImported int            `json:"envImported"`
+	EnvSkipped  int            `json:"envSkipped"`
+	RemappedIDs map[int]int    `json:"remappedIds"`
+	ExportedAt  *time.Time     `json:"exportedAt,omitempty"`
+}
+
+// workspaceTable describes how a table is carried through an export/import round trip.
+// Tables are listed in dependency order; replace deletes them in reverse. The cookies table is
+// left out on purpose: cookies are mostly session credentials, which the archive would carry in
+// plain text, and servers hand out new ones on the next request.
+type workspaceTable struct {
+	name string
+	// requestColumn references requests.id and is remapped when a merge assigns new request ids.
+	requestColumn string
+	// autoID drops the id column on merge so rows never collide with local history.
+	autoID bool
+}
+
+var workspaceTables = []workspaceTable{
+	{name: "collections"},
+	{name: "requests"},
+	{name: "responses", requestColumn: "request_id", autoID: true},
+	{name: "request_failures", requestColumn: "request_id", autoID: true},
+	{name: "collection_variables"},
+	{name: "collection_retention"},
+	{name: "collection_scripts"},
+	{name: "request_variables", requestColumn: "request_id"},
+	{name: "request_scripts", requestColumn: "request_id"},
+	{name: "request_params", requestColumn: "request_id", autoID: true},
+	{name: "request_path_variables", requestColumn: "request_id"},
+	{name: "tags"},
+	{name: "item_tags", requestColumn: "request_id"},
+	{name: "hotkey_binds"},
+	{name: "app_state"},
+}
+
+// ExportWorkspace writes the workspace to a single archive. Secret variables are encrypted with
+// this machine's key, so they are re-encrypted under secretsPassphrase, which ImportWorkspace
+// then asks for; exporting a workspace that has secrets requires one.
+func (s *WorkspaceService) ExportWorkspace(archivePath string, secretsPassphrase string) error {
+	if s.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if strings.TrimSpace(archivePath) == "" {
+		return fmt.Errorf("export path is required")
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	manifest := WorkspaceManifest{
+		Format:     workspaceArchiveFormat,
+		Version:    workspaceArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	for _, table := range workspaceTables {
+		rows, err := dumpWorkspaceTable(s.db, table.name)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", table.name, err)
+		}
+		if err := writeWorkspaceJSON(zw, path.Join(workspaceTablesDir, table.name+".json"), rows); err != nil {
+			return err
+		}
+		manifest.Tables = append(manifest.Tables, table.name)
+	}
+
+	entries, err := os.ReadDir("./data/environments")
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read environments: %w", err)
+	}
+	var localKey, archiveKey []byte
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join("./data/environments", entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read environment %s: %w", entry.Name(), err)
+		}
+		if hasSecretValues(string(content)) {
+			if secretsPassphrase == "" {
+				return fmt.Errorf("environment %s has secret variables, set an export passphrase to include them", entry.Name())
+			}
+			if archiveKey == nil {
+				if localKey, err = s.localSecretKey(); err != nil {
+					return err
+				}
+				if manifest.Secrets, archiveKey, err = newSecretKDFParams(secretsPassphrase); err != nil {
+					return err
+				}
+			}
+			exported, _, err := reencryptSecrets(string(content), localKey, archiveKey)
+			if err != nil {
+				return fmt.Errorf("failed to export secrets of %s: %w", entry.Name(), err)
+			}
+			content = []byte(exported)
+		}
+		w, err := zw.Create(path.Join(workspaceEnvsDir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to add environment %s to archive: %w", entry.Name(), err)
+		}
+		if _, err := w.Write(content); err != nil {
+			return fmt.Errorf("failed to write environment %s to archive: %w", entry.Name(), err)
+		}
+		manifest.EnvFiles = append(manifest.EnvFiles, entry.Name())
+	}
+
+	if err := writeWorkspaceJSON(zw, workspaceManifestName, manifest); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize workspace archive: %w", err)
+	}
+
+	if dir := filepath.Dir(archivePath); dir != "" {
+		if err := os.MkdirAll(dir, fs.ModePerm); err != nil {
+			return fmt.Errorf("failed to create export directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write workspace archive: %w", err)
+	}
+
+	fmt.Printf("Exported workspace to %s\n", archivePath)
+	return nil
+}
+
+// ImportWorkspace restores an archive written by ExportWorkspace. secretsPassphrase is the
+// export passphrase and is only needed when the archive carries secret variables.
+func (s *WorkspaceService) ImportWorkspace(archivePath string, mode string, secretsPassphrase string) (WorkspaceImportSummary, error) {
+	summary := WorkspaceImportSummary{
+		Imported:    map[string]int{},
+		Skipped:     map[string]int{},
+		RemappedIDs: map[int]int{},
+	}
+	if s.db == nil {
+		return summary, fmt.Errorf("database not initialized")
+	}
+
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = WorkspaceImportMerge
+	}
+	if mode != WorkspaceImportMerge && mode != WorkspaceImportReplace {
+		return summary, fmt.Errorf("unknown import mode %q, expected %q or %q", mode, WorkspaceImportMerge, WorkspaceImportReplace)
+	}
+	summary.Mode = mode
+
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return summary, fmt.Errorf("failed to open workspace archive: %w", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var manifest WorkspaceManifest
+	manifestFile, ok := files[workspaceManifestName]
+	if !ok {
+		return summary, fmt.Errorf("archive is missing %s", workspaceManifestName)
+	}
+	if err := readWorkspaceJSON(manifestFile, &manifest); err != nil {
+		return summary, err
+	}
+	if manifest.Format != workspaceArchiveFormat {
+		return summary, fmt.Errorf("archive is not a curlew workspace")
+	}
+	if manifest.Version > workspaceArchiveVersion {
+		return summary, fmt.Errorf("workspace archive version %d is newer than supported version %d", manifest.Version, workspaceArchiveVersion)
+	}
+	exportedAt := manifest.ExportedAt
+	summary.ExportedAt = &exportedAt
+
+	// The passphrase is checked before anything is written so a wrong one imports nothing.
+	var archiveKey, localKey []byte
+	if manifest.Secrets != nil {
+		if secretsPassphrase == "" {
+			return summary, fmt.Errorf("the archive's secrets are protected by a passphrase")
+		}
+		if archiveKey, err = deriveSecretKey(secretsPassphrase, manifest.Secrets); err != nil {
+			return summary, err
+		}
+		if !verifySecretKey(archiveKey, manifest.Secrets) {
+			return summary, fmt.Errorf("incorrect passphrase for the archive's secrets")
+		}
+		if localKey, err = s.localSecretKey(); err != nil {
+			return summary, err
+		}
+	}
+
+	tableRows := make(map[string][]map[string]interface{})
+	for _, table := range workspaceTables {
+		f, ok := files[path.Join(workspaceTablesDir, table.name+".json")]
+		if !ok {
+			continue
+		}
+		var rows []map[string]interface{}
+		if err := readWorkspaceJSON(f, &rows); err != nil {
+			return summary, err
+		}
+		tableRows[table.name] = rows
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return summary, fmt.Errorf("failed to start workspace import transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if mode == WorkspaceImportReplace {
+		for i := len(workspaceTables) - 1; i >= 0; i-- {
+			if _, err := tx.Exec("DELETE FROM " + workspaceTables[i].name); err != nil {
+				return summary, fmt.Errorf("failed to clear %s: %w", workspaceTables[i].name, err)
+			}
+		}
+		// The journal refers to the rows that were just replaced, so they can no longer be undone.
+		if err := clearJournal(tx); err != nil {
+			return summary, err
+		}
+	}
+
+	// Imported request id -> local request id. Requests already present locally map to
+	// themselves and are recorded in existing so their dependent rows are left alone.
+	requestIDs := make(map[int64]int64)
+	existing := make(map[int64]bool)
+
+	for _, table := range workspaceTables {
+		rows := tableRows[table.name]
+		if len(rows) == 0 {
+			continue
+		}
+		columns, err := workspaceTableColumns(tx, table.name)
+		if err != nil {
+			return summary, err
+		}
+
+		if table.name == "requests" {
+			if err := importWorkspaceRequests(tx, rows, columns, mode, requestIDs, existing, &summary); err != nil {
+				return summary, err
+			}
+			continue
+		}
+
+		for _, row := range rows {
+			if table.requestColumn != "" {
+				ref, ok := workspaceInt(row[table.requestColumn])
+				if ok {
+					if existing[ref] {
+						summary.Skipped[table.name]++
+						continue
+					}
+					if mapped, found := requestIDs[ref]; found {
+						row[table.requestColumn] = mapped
+					}
+				}
+			}
+			if mode == WorkspaceImportMerge && table.autoID {
+				delete(row, "id")
+			}
+
+			verb := "INSERT OR IGNORE"
+			if mode == WorkspaceImportReplace {
+				verb = "INSERT OR REPLACE"
+			}
+			result, err := insertWorkspaceRow(tx, verb, table.name, columns, row)
+			if err != nil {
+				return summary, fmt.Errorf("failed to import %s row: %w", table.name, err)
+			}
+			if affected, _ := result.RowsAffected(); affected == 0 {
+				summary.Skipped[table.name]++
+				continue
+			}
+			summary.Imported[table.name]++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return summary, fmt.Errorf("failed to commit workspace import: %w", err)
+	}
+	committed = true
+
+	if err := importWorkspaceEnvFiles(files, mode, archiveKey, localKey, &summary); err != nil {
+		return summary, err
+	}
+
+	fmt.Printf("Imported workspace from %s (%s)\n", archivePath, mode)
+	return summary, nil
+}
+
+func importWorkspaceRequests(tx *sql.Tx, rows []map[string]interface{}, columns map[string]string, mode string, requestIDs map[int64]int64, existing map[int64]bool, summary *WorkspaceImportSummary) error {
+	for _, row := range rows {
+		importedID, hasID := workspaceInt(row["id"])
+
+		if hasID && mode == WorkspaceImportMerge {
+			var (
+				localCollection sql.NullString
+				localName       sql.NullString
+			)
+			err := tx.QueryRow("SELECT collection_id, name FROM requests WHERE id = ?", importedID).Scan(&localCollection, &localName)
+			if err != nil && err != sql.ErrNoRows {
+				return fmt.Errorf("failed to check request %d: %w", importedID, err)
+			}
+			if err == nil {
+				if localCollection.String == workspaceString(row["collection_id"]) && localName.String == workspaceString(row["name"]) {
+					requestIDs[importedID] = importedID
+					existing[importedID] = true
+					summary.Skipped["requests"]++
+					continue
+				}
+				// Same id, different request: keep the local one and give the import a fresh id.
+				delete(row, "id")
+			}
+		}
+
+		result, err := insertWorkspaceRow(tx, "INSERT", "requests", columns, row)
+		if err != nil {
+			return fmt.Errorf("failed to import request %v: %w", row["name"], err)
+		}
+		newID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to read imported request id: %w", err)
+		}
+		if hasID {
+			requestIDs[importedID] = newID
+			if newID != importedID {
+				summary.RemappedIDs[int(importedID)] = int(newID)
+			}
+		}
+		summary.Imported["requests"]++
+	}
+	return nil
+}
+
+// importWorkspaceEnvFiles restores the archive's environment files. When archiveKey is set their
+// secrets are re-encrypted from the export passphrase to this machine's key.
+func importWorkspaceEnvFiles(files map[string]*zip.File, mode string, archiveKey []byte, localKey []byte, summary *WorkspaceImportSummary) error {
+	if err := os.MkdirAll("./data/environments", fs.ModePerm); err != nil {
+		return err
+	}
+
+	if mode == WorkspaceImportReplace {
+		entries, err := os.ReadDir("./data/environments")
+		if err != nil {
+			return fmt.Errorf("failed to read environments: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if err := os.Remove(filepath.Join("./data/environments", entry.Name())); err != nil {
+				return fmt.Errorf("failed to remove environment %s: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	prefix := workspaceEnvsDir + "/"
+	for name, f := range files {
+		if !strings.HasPrefix(name, prefix) || f.FileInfo().IsDir() {
+			continue
+		}
+		envName := strings.TrimPrefix(name, prefix)
+		if envName == "" || envName != filepath.Base(envName) {
+			fmt.Println("Skipping unexpected environment entry in archive:", name)
+			continue
+		}
+
+		target := filepath.Join("./data/environments", envName)
+		if mode == WorkspaceImportMerge {
+			if _, err := os.Stat(target); err == nil {
+				summary.EnvSkipped++
+				continue
+			}
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open environment %s in archive: %w", envName, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read environment %s from archive: %w", envName, err)
+		}
+		if archiveKey != nil {
+			imported, _, err := reencryptSecrets(string(content), archiveKey, localKey)
+			if err != nil {
+				return fmt.Errorf("failed to import secrets of %s: %w", envName, err)
+			}
+			content = []byte(imported)
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return fmt.Errorf("failed to restore environment %s: %w", envName, err)
+		}
+		summary.EnvImported++
+	}
+	return nil
+}
+
+func (s *WorkspaceService) localSecretKey() ([]byte, error) {
+	if s.envars == nil {
+		return nil, fmt.Errorf("secrets are not available")
+	}
+	return s.envars.currentSecretKey()
+}
+
+func dumpWorkspaceTable(db *sql.DB, table string) ([]map[string]interface{}, error) {
+	rows, err := db.Query("SELECT * FROM " + table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			switch v := values[i].(type) {
+			case []byte:
+				row[column] = string(v)
+			case time.Time:
+				row[column] = v.UTC()
+			default:
+				row[column] = v
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// workspaceTableColumns returns the local columns of a table keyed by name with their declared type,
+// so archives from older or newer schemas only write the columns both sides know about.
+func workspaceTableColumns(tx *sql.Tx, table string) (map[string]string, error) {
+	rows, err := tx.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s schema: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]string)
+	for rows.Next() {
+		var cid int
+		var name, ctype string
+		var notnull int
+		var dfltValue sql.NullString
+		var pk int
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
+			return nil, err
+		}
+		columns[strings.ToLower(name)] = strings.ToUpper(ctype)
+	}
+	return columns, rows.Err()
+}
+
+func insertWorkspaceRow(tx *sql.Tx, verb string, table string, columns map[string]string, row map[string]interface{}) (sql.Result, error) {
+	names := make([]string, 0, len(row))
+	for name := range row {
+		if _, ok := columns[strings.ToLower(name)]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	placeholders := make([]string, len(names))
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		placeholders[i] = "?"
+		args[i] = workspaceValue(row[name], columns[strings.ToLower(name)])
+	}
+
+	query := fmt.Sprintf("%s INTO %s (%s) VALUES (%s)", verb, table, strings.Join(names, ", "), strings.Join(placeholders, ", "))
+	return tx.Exec(query, args...)
+}
+
+func workspaceValue(value interface{}, columnType string) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case string:
+		if columnType == "DATETIME" {
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t.UTC()
+			}
+		}
+		return v
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	return value
+}
+
+func workspaceInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func workspaceString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+func writeWorkspaceJSON(zw *zip.Writer, name string, value interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return nil
+}
+
+func readWorkspaceJSON(f *zip.File, target interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s in archive: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	decoder := json.NewDecoder(rc)
+	decoder.UseNumber()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("failed to parse %s in archive: %w", f.Name, err)
+	}
+	return nil
+}